@@ -17,10 +17,15 @@ package backend
 import (
 	"bufio"
 	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"io"
 	"math/big"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // Assignment is used to specify inputs to the Prove and Verify functions
@@ -37,32 +42,75 @@ func NewAssignment() Assignments {
 	return make(Assignments)
 }
 
-// Assign assign a value to a Secret/Public input identified by its name
+// Assign assign a value to a Secret/Public input identified by its name, it panics if TryAssign fails
 func (a Assignments) Assign(visibility Visibility, name string, v interface{}) {
+	if err := a.TryAssign(visibility, name, v); err != nil {
+		panic(err)
+	}
+}
+
+// TryAssign assign a value to a Secret/Public input identified by its name
+// it returns ErrDuplicateAssignment if the input is already assigned, ErrInputVisiblity if the
+// visibility is not Secret or Public, and ErrInvalidType if v can't be converted (see ParseInterface)
+func (a Assignments) TryAssign(visibility Visibility, name string, v interface{}) error {
 	if _, ok := a[name]; ok {
-		panic(name + " already assigned")
-	}
-	switch visibility {
-	case Secret:
-		a[name] = Assignment{Value: FromInterface(v)}
-	case Public:
-		a[name] = Assignment{
-			Value:    FromInterface(v),
-			IsPublic: true,
+		return fmt.Errorf("%w: %s", ErrDuplicateAssignment, name)
+	}
+	if visibility != Secret && visibility != Public {
+		return fmt.Errorf("%w: %q", ErrInputVisiblity, visibility)
+	}
+	value, err := ParseInterface(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	a[name] = Assignment{
+		Value:    value,
+		IsPublic: visibility == Public,
+	}
+	return nil
+}
+
+// AssignNamespace assigns the inputs of b to the inputs of the same name in the namespace namespace,
+// that is prefixed by namespace and NamespaceSeparator, as declared by frontend.CS.Namespace
+// it returns ErrDuplicateAssignment if one of them is already assigned
+func (a Assignments) AssignNamespace(namespace string, b Assignments) error {
+	for name, v := range b {
+		scoped := namespace + NamespaceSeparator + name
+		if _, ok := a[scoped]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateAssignment, scoped)
 		}
-	default:
-		panic("supported visibility attributes are SECRET and PUBLIC")
+		a[scoped] = v
 	}
+	return nil
 }
 
-// ReadFile parse r1cs.Assigments from given file
+// ReadFile parse r1cs.Assigments from given file, in the JSON format (see ReadJSON) if its
+// first non blank character is '{', in the CSV format (see Read) otherwise
 func (assignment Assignments) ReadFile(filePath string) error {
-	csvFile, err := os.Open(filePath)
+	file, err := os.Open(filePath)
 	if err != nil {
 		return err
 	}
-	defer csvFile.Close()
-	return assignment.Read(csvFile)
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	for {
+		c, _, err := r.ReadRune()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !unicode.IsSpace(c) {
+			if err := r.UnreadRune(); err != nil {
+				return err
+			}
+			if c == '{' {
+				return assignment.ReadJSON(r)
+			}
+			return assignment.Read(r)
+		}
+	}
 }
 
 // Read parse r1cs.Assigments from given io.Reader
@@ -81,19 +129,25 @@ func (assigment Assignments) Read(r io.Reader) error {
 		name := strings.TrimSpace(line[1])
 		value := strings.TrimSpace(line[2])
 
-		assigment.Assign(Visibility(visibility), name, value)
+		if err := assigment.TryAssign(Visibility(visibility), name, value); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-// WriteFile serialize given assigment to disk
+// WriteFile serialize given assigment to disk, in the JSON format if the extension of path is .json,
+// in the CSV format otherwise
 func (assignment Assignments) WriteFile(path string) error {
-	csvFile, err := os.Create(path)
+	file, err := os.Create(path)
 	if err != nil {
 		return err
 	}
-	defer csvFile.Close()
-	return assignment.Write(csvFile)
+	defer file.Close()
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		return assignment.WriteJSON(file)
+	}
+	return assignment.Write(file)
 }
 
 // Write serialize given assigment to io.Writer
@@ -113,6 +167,99 @@ func (assignment Assignments) Write(w io.Writer) error {
 	return nil
 }
 
+// ReadJSON parse r1cs.Assigments from given io.Reader, in the JSON format:
+//
+//	{
+//		"public": {"x": 42, "y": "0x2a"},
+//		"secret": {"path": ["1", "2"], "batch": [[3, 4], [5, 6]]}
+//	}
+//
+// The values are numbers, or strings holding decimal or hexadecimal (0x prefixed) integers.
+// Arrays are expanded to indexed names: path is assigned to path_0, path_1 and batch to batch_0_0,
+// batch_0_1, batch_1_0, batch_1_1, as the circuits name their inputs baseName + strconv.Itoa(i).
+// Objects are namespaces: {"sender": {"x": 1}} assigns sender/x (see NamespaceSeparator)
+func (assignment Assignments) ReadJSON(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber() // the values don't fit in a float64
+
+	var inputs map[string]map[string]interface{}
+	if err := decoder.Decode(&inputs); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInputFormat, err)
+	}
+	for v, values := range inputs {
+		visibility := Visibility(strings.ToLower(strings.TrimSpace(v)))
+		if visibility != Secret && visibility != Public {
+			return fmt.Errorf("%w: unknown visibility %q", ErrInvalidInputFormat, v)
+		}
+		for name, value := range values {
+			if err := assignment.assignJSON(visibility, strings.TrimSpace(name), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// assignJSON assigns the decoded JSON value to name, expanding the arrays and the objects (namespaces)
+func (assignment Assignments) assignJSON(visibility Visibility, name string, value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			if err := assignment.assignJSON(visibility, name+NamespaceSeparator+strings.TrimSpace(k), v[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		for i := range v {
+			if err := assignment.assignJSON(visibility, name+"_"+strconv.Itoa(i), v[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case json.Number:
+		s = v.String()
+	case string:
+		s = strings.TrimSpace(v)
+	default:
+		return fmt.Errorf("%w: %s: unsupported value %v", ErrInvalidInputFormat, name, value)
+	}
+
+	var n big.Int
+	var ok bool
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		_, ok = n.SetString(s[2:], 16)
+	} else {
+		_, ok = n.SetString(s, 10)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s: invalid integer %q", ErrInvalidInputFormat, name, s)
+	}
+	return assignment.TryAssign(visibility, name, n)
+}
+
+// WriteJSON serialize given assigment to io.Writer, in the JSON format read by ReadJSON
+// The values are written as decimal strings
+func (assignment Assignments) WriteJSON(w io.Writer) error {
+	inputs := map[Visibility]map[string]string{
+		Public: make(map[string]string),
+		Secret: make(map[string]string),
+	}
+	for k, v := range assignment {
+		visibility := Secret
+		if v.IsPublic {
+			visibility = Public
+		}
+		inputs[visibility][k] = v.Value.String()
+	}
+
+	// the keys of the maps are sorted by the encoder
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(inputs)
+}
+
 // DiscardSecrets returns a copy of self without Secret Assigment
 func (assignments Assignments) DiscardSecrets() Assignments {
 	toReturn := NewAssignment()
@@ -1,6 +1,13 @@
 package backend
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -28,3 +35,125 @@ func TestVisibility(t *testing.T) {
 	assert.True(a["x"].IsPublic)
 	assert.False(a["y"].IsPublic)
 }
+
+func TestReadJSON(t *testing.T) {
+	assert := require.New(t)
+
+	input := `{
+		"public": {"x": 42, "y": "0x2A"},
+		"secret": {"path": ["1", "21888242871839275222246405745257275088548364400416034343698204186575808495616"], "batch": [[3, 4], [5]], "sender": {"pubkey": {"x": 6}, "nonce": [7]}}
+	}`
+	a := NewAssignment()
+	assert.NoError(a.ReadJSON(strings.NewReader(input)))
+
+	expected := map[string]int64{"x": 42, "y": 42, "path_0": 1, "batch_0_0": 3, "batch_0_1": 4, "batch_1_0": 5, "sender/pubkey/x": 6, "sender/nonce_0": 7}
+	assert.Equal(len(expected)+1, len(a))
+	for name, v := range expected {
+		value := a[name].Value
+		assert.Equal(0, value.Cmp(big.NewInt(v)), name)
+	}
+	var large big.Int
+	large.SetString("21888242871839275222246405745257275088548364400416034343698204186575808495616", 10)
+	value := a["path_1"].Value
+	assert.Equal(0, value.Cmp(&large))
+
+	assert.True(a["x"].IsPublic)
+	assert.True(a["y"].IsPublic)
+	assert.False(a["path_0"].IsPublic)
+	assert.False(a["batch_1_0"].IsPublic)
+
+	// invalid inputs
+	for _, input := range []string{
+		`{"private": {"x": 1}}`,
+		`{"secret": {"x": "0xg"}}`,
+		`{"secret": {"x": 1.5}}`,
+		`{"secret": {"x": {"y": true}}}`,
+		`{"secret": ["x"]}`,
+		`secret, x, 1`,
+	} {
+		assert.True(errors.Is(NewAssignment().ReadJSON(strings.NewReader(input)), ErrInvalidInputFormat), input)
+	}
+
+	// the errors tell what is malformed
+	err := NewAssignment().ReadJSON(strings.NewReader(`secret, x, 1`))
+	assert.Contains(err.Error(), "invalid character")
+	err = NewAssignment().ReadJSON(strings.NewReader(`{"secret": {"x": "0xg"}}`))
+	assert.Contains(err.Error(), `x: invalid integer "0xg"`)
+}
+
+func TestWriteJSON(t *testing.T) {
+	assert := require.New(t)
+
+	a := NewAssignment()
+	a.Assign(Public, "x", 42)
+	a.Assign(Secret, "y", "21888242871839275222246405745257275088548364400416034343698204186575808495616")
+
+	var buf bytes.Buffer
+	assert.NoError(a.WriteJSON(&buf))
+
+	b := NewAssignment()
+	assert.NoError(b.ReadJSON(&buf))
+	assert.Equal(a, b)
+}
+
+func TestReadFile(t *testing.T) {
+	assert := require.New(t)
+
+	dir, err := ioutil.TempDir("", "assignment")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	a := NewAssignment()
+	a.Assign(Public, "x", 42)
+	a.Assign(Secret, "y", 3)
+
+	// the format is detected from the content of the file, not its extension
+	for _, name := range []string{"input.csv", "input.json"} {
+		path := filepath.Join(dir, name)
+		assert.NoError(a.WriteFile(path))
+
+		b := NewAssignment()
+		assert.NoError(b.ReadFile(path))
+		assert.Equal(a, b, name)
+
+		assert.NoError(os.Rename(path, path+".txt"))
+		b = NewAssignment()
+		assert.NoError(b.ReadFile(path + ".txt"))
+		assert.Equal(a, b, name)
+	}
+}
+
+func TestTryAssign(t *testing.T) {
+	assert := require.New(t)
+	a := NewAssignment()
+
+	assert.NoError(a.TryAssign(Public, "x", 1))
+	assert.True(errors.Is(a.TryAssign(Secret, "x", 1), ErrDuplicateAssignment))
+	assert.True(errors.Is(a.TryAssign(Visibility("private"), "y", 1), ErrInputVisiblity))
+	assert.True(errors.Is(a.TryAssign(Secret, "y", "0x12"), ErrInvalidType))
+	assert.True(errors.Is(a.TryAssign(Secret, "y", 1.5), ErrInvalidType))
+	assert.Equal(1, len(a))
+
+	// the readers return the errors instead of panicking
+	assert.True(errors.Is(NewAssignment().Read(strings.NewReader("secret, x, 1\npublic, x, 2")), ErrDuplicateAssignment))
+	assert.True(errors.Is(NewAssignment().ReadJSON(strings.NewReader(`{"secret": {"x": [1], "x_0": 2}}`)), ErrDuplicateAssignment))
+}
+
+func TestAssignNamespace(t *testing.T) {
+	assert := require.New(t)
+
+	sender := NewAssignment()
+	sender.Assign(Secret, "x", 1)
+	sender.Assign(Public, "y", 2)
+
+	a := NewAssignment()
+	a.Assign(Secret, "x", 3)
+	assert.NoError(a.AssignNamespace("sender", sender))
+	assert.NoError(a.AssignNamespace("transfer/receiver", sender))
+	assert.Equal(5, len(a))
+	assert.True(a["sender/y"].IsPublic)
+	value := a["transfer/receiver/x"].Value
+	assert.Equal(0, value.Cmp(big.NewInt(1)))
+
+	assert.True(errors.Is(a.AssignNamespace("sender", sender), ErrDuplicateAssignment))
+}
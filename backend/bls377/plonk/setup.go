@@ -0,0 +1,269 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark/internal/generators DO NOT EDIT
+
+package plonk
+
+import (
+	"errors"
+
+	curve "github.com/consensys/gurvy/bls377"
+	"github.com/consensys/gurvy/bls377/fr"
+
+	"github.com/consensys/gnark/internal/utils/parallel"
+
+	backend_bls377 "github.com/consensys/gnark/backend/bls377"
+)
+
+var (
+	ErrSRSTooSmall = errors.New("srs is too small for this circuit")
+)
+
+// SRS is a universal structured reference string, used by the KZG polynomial commitment scheme
+// it can be reused for any circuit up to a given size
+type SRS struct {
+	// [1]1, [τ]1, [τ²]1, ...
+	G1 []curve.G1Affine
+
+	// [1]2, [τ]2
+	G2 [2]curve.G2Affine
+}
+
+// ProvingKey is used by a PLONK prover to encode a proof of a statement
+type ProvingKey struct {
+	// [τ^i]1 used to commit to the prover's polynomials
+	G1 []curve.G1Affine
+
+	// size of the evaluation domain, and its generator
+	Size      int
+	Generator fr.Element
+
+	// selectors, permutation polynomials, in canonical form
+	Ql, Qr, Qm, Qo, Qk []fr.Element
+	S1, S2, S3         []fr.Element
+
+	// the permutation: the wire in slot i is copied in slot Permutation[i]
+	// slots [0, Size) are the left wires, [Size, 2⋅Size) the right wires and [2⋅Size, 3⋅Size) the output wires
+	Permutation []int64
+
+	// cosets shifters for the right and output wires
+	Shifter [2]fr.Element
+}
+
+// VerifyingKey is used by a PLONK verifier to verify the validity of a proof and a statement
+type VerifyingKey struct {
+	// size of the evaluation domain, and its generator
+	Size      int
+	Generator fr.Element
+
+	// commitments to the selectors and permutation polynomials
+	Ql, Qr, Qm, Qo, Qk curve.G1Affine
+	S1, S2, S3         curve.G1Affine
+
+	// cosets shifters for the right and output wires
+	Shifter [2]fr.Element
+
+	// [1]1
+	G1 curve.G1Affine
+
+	// [1]2, [τ]2
+	G2 [2]curve.G2Affine
+
+	PublicInputs []string // maps the name of the public input
+}
+
+// NewSRS returns a SRS that can commit to polynomials of degree < size
+// the secret τ is sampled locally and then discarded; this is fine for testing purposes
+// but a SRS for production should come from a multi party computation
+func NewSRS(size int) *SRS {
+	c := curve.BLS377()
+
+	srs := &SRS{G1: make([]curve.G1Affine, size)}
+
+	var tau fr.Element
+	tau.SetRandom()
+
+	powers := make([]fr.Element, size)
+	powers[0].SetOne()
+	for i := 1; i < size; i++ {
+		powers[i].Mul(&powers[i-1], &tau)
+	}
+
+	parallel.Execute(size, func(start, end int) {
+		var g curve.G1Jac
+		for i := start; i < end; i++ {
+			g.ScalarMulByGen(c, powers[i].ToRegular()).ToAffineFromJac(&srs.G1[i])
+		}
+	})
+
+	var g2 curve.G2Jac
+	g2.ScalarMulByGen(c, powers[0].ToRegular()).ToAffineFromJac(&srs.G2[0])
+	g2.ScalarMulByGen(c, tau.ToRegular()).ToAffineFromJac(&srs.G2[1])
+
+	return srs
+}
+
+// SRSSize returns the minimal size of a SRS to run Setup on the circuit
+func SRSSize(spr *backend_bls377.SparseR1CS) int {
+	domain := backend_bls377.NewDomain(root, backend_bls377.MaxOrder, spr.NbConstraints)
+	return maxDegree(domain.Cardinality) + 1
+}
+
+// Setup computes the proving and verifying keys of a circuit from a SRS
+func Setup(spr *backend_bls377.SparseR1CS, srs *SRS, pk *ProvingKey, vk *VerifyingKey) error {
+
+	/*
+		Setup
+		-----
+		- the selectors qL, qR, qM, qO, qC are interpolated from their value on each constraint
+		- the permutation σ links the slots (left, right, output wire of a constraint) sharing the same wire
+		- the permutation polynomials Sσ1, Sσ2, Sσ3 are interpolated from the labels of σ(slot),
+		where the label of the i-th slot is ωⁱ, k1⋅ωⁱ, k2⋅ωⁱ for the left, right, output wires
+		- the verifying key contains commitments to these polynomials
+	*/
+
+	domain := backend_bls377.NewDomain(root, backend_bls377.MaxOrder, spr.NbConstraints)
+	n := domain.Cardinality
+
+	if len(srs.G1) < maxDegree(n)+1 {
+		return ErrSRSTooSmall
+	}
+
+	pk.G1 = srs.G1[:maxDegree(n)+1]
+	pk.Size, vk.Size = n, n
+	pk.Generator, vk.Generator = domain.Generator, domain.Generator
+	pk.Shifter = cosetShifters(n)
+	vk.Shifter = pk.Shifter
+	vk.G1 = srs.G1[0]
+	vk.G2 = srs.G2
+	vk.PublicInputs = spr.R1CS.PublicWires
+
+	// selectors, in Lagrange form
+	pk.Ql = make([]fr.Element, n)
+	pk.Qr = make([]fr.Element, n)
+	pk.Qm = make([]fr.Element, n)
+	pk.Qo = make([]fr.Element, n)
+	pk.Qk = make([]fr.Element, n)
+	for i := 0; i < len(spr.Constraints); i++ {
+		pk.Ql[i].Set(&spr.Constraints[i].QL)
+		pk.Qr[i].Set(&spr.Constraints[i].QR)
+		pk.Qm[i].Set(&spr.Constraints[i].QM)
+		pk.Qo[i].Set(&spr.Constraints[i].QO)
+		pk.Qk[i].Set(&spr.Constraints[i].QC)
+	}
+
+	// permutation
+	pk.Permutation = buildPermutation(spr, n)
+	pk.S1, pk.S2, pk.S3 = make([]fr.Element, n), make([]fr.Element, n), make([]fr.Element, n)
+	labels := slotLabels(n, domain.Generator, pk.Shifter)
+	for i := 0; i < n; i++ {
+		pk.S1[i] = labels[pk.Permutation[i]]
+		pk.S2[i] = labels[pk.Permutation[n+i]]
+		pk.S3[i] = labels[pk.Permutation[2*n+i]]
+	}
+
+	// canonical form & commitments
+	polynomials := [][]fr.Element{pk.Ql, pk.Qr, pk.Qm, pk.Qo, pk.Qk, pk.S1, pk.S2, pk.S3}
+	commitments := []*curve.G1Affine{&vk.Ql, &vk.Qr, &vk.Qm, &vk.Qo, &vk.Qk, &vk.S1, &vk.S2, &vk.S3}
+	for i := 0; i < len(polynomials); i++ {
+		interpolate(polynomials[i], domain)
+		*commitments[i] = commit(pk.G1, polynomials[i])
+	}
+
+	return nil
+}
+
+// maxDegree returns the maximum degree of the polynomials committed by the prover,
+// for a domain of size n (that is the degree of the quotient polynomial t)
+func maxDegree(n int) int {
+	return 3*n + 5
+}
+
+// cosetShifters returns k1, k2 such that H, k1⋅H and k2⋅H are distinct cosets,
+// H being the subgroup of size n
+func cosetShifters(n int) [2]fr.Element {
+	var res [2]fr.Element
+	var one, tmp, ratio fr.Element
+	one.SetOne()
+	inH := func(x fr.Element) bool {
+		tmp.Exp(x, uint64(n))
+		return tmp.Equal(&one)
+	}
+	res[0].SetUint64(2)
+	for inH(res[0]) {
+		res[0].Add(&res[0], &one)
+	}
+	res[1].Add(&res[0], &one)
+	for {
+		ratio.Div(&res[1], &res[0])
+		if !inH(res[1]) && !inH(ratio) {
+			break
+		}
+		res[1].Add(&res[1], &one)
+	}
+	return res
+}
+
+// buildPermutation returns the permutation linking the slots sharing the same wire
+// the slots of the padding constraints contain the left wire of the first constraint
+func buildPermutation(spr *backend_bls377.SparseR1CS, n int) []int64 {
+	wires := make([]int64, 3*n)
+	filler := spr.Constraints[0].L
+	for i := 0; i < n; i++ {
+		if i < len(spr.Constraints) {
+			wires[i] = spr.Constraints[i].L
+			wires[n+i] = spr.Constraints[i].R
+			wires[2*n+i] = spr.Constraints[i].O
+		} else {
+			wires[i], wires[n+i], wires[2*n+i] = filler, filler, filler
+		}
+	}
+
+	// each cycle of the permutation goes through all the slots of a given wire
+	permutation := make([]int64, 3*n)
+	last := make([]int64, spr.NbWires)
+	first := make([]int64, spr.NbWires)
+	for i := range last {
+		last[i], first[i] = -1, -1
+	}
+	for i, w := range wires {
+		if last[w] == -1 {
+			first[w] = int64(i)
+		} else {
+			permutation[last[w]] = int64(i)
+		}
+		last[w] = int64(i)
+	}
+	for w := range last {
+		if last[w] != -1 {
+			permutation[last[w]] = first[w]
+		}
+	}
+	return permutation
+}
+
+// slotLabels returns the labels of the 3⋅n slots: ωⁱ, k1⋅ωⁱ, k2⋅ωⁱ
+func slotLabels(n int, generator fr.Element, shifter [2]fr.Element) []fr.Element {
+	labels := make([]fr.Element, 3*n)
+	labels[0].SetOne()
+	for i := 1; i < n; i++ {
+		labels[i].Mul(&labels[i-1], &generator)
+	}
+	for i := 0; i < n; i++ {
+		labels[n+i].Mul(&labels[i], &shifter[0])
+		labels[2*n+i].Mul(&labels[i], &shifter[1])
+	}
+	return labels
+}
@@ -18,9 +18,12 @@ package backend_bls377
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/consensys/gnark/backend"
+
 	"github.com/consensys/gurvy/bls377/fr"
 
 	"github.com/consensys/gnark/frontend"
@@ -41,6 +44,7 @@ type R1CS struct {
 	NbConstraints   int // total number of constraints
 	NbCOConstraints int // number of constraints that need to be solved, the first of the Constraints slice
 	Constraints     []R1C
+	CallSites       []string // optional, file:line of the frontend call which created each constraint -- debug info
 }
 
 // New return a typed R1CS with the curve from frontend.R1CS
@@ -62,6 +66,7 @@ func Cast(r1cs *frontend.R1CS) R1CS {
 		PrivateWires:    r1cs.PrivateWires,
 		PublicWires:     r1cs.PublicWires,
 		WireTags:        r1cs.WireTags,
+		CallSites:       r1cs.CallSites,
 		NbConstraints:   r1cs.NbConstraints,
 		NbCOConstraints: r1cs.NbCOConstraints,
 	}
@@ -70,6 +75,7 @@ func Cast(r1cs *frontend.R1CS) R1CS {
 		from := r1cs.Constraints[i]
 		to := R1C{
 			Solver: from.Solver,
+			HintID: from.HintID,
 			L:      make(LinearExpression, len(from.L)),
 			R:      make(LinearExpression, len(from.R)),
 			O:      make(LinearExpression, len(from.O)),
@@ -158,7 +164,9 @@ func (r1cs *R1CS) Solve(assignment backend.Assignments, a, b, c, wireValues []fr
 			// computationalGraph : we need to solve the constraint
 			// computationalGraph[i] contains exactly one uncomputed wire (due
 			// to the graph being correctly ordered), we solve it
-			r1cs.Constraints[i].solveR1c(wireInstantiated, wireValues)
+			if err := r1cs.Constraints[i].solveR1c(wireInstantiated, wireValues); err != nil {
+				return r1cs.solvingError(i, err)
+			}
 		}
 
 		// A this stage we are not guaranteed that a[i+sizecg]*b[i+sizecg]=c[i+sizecg] because we only query the values (computed
@@ -168,20 +176,89 @@ func (r1cs *R1CS) Solve(assignment backend.Assignments, a, b, c, wireValues []fr
 		// check that the constraint is satisfied
 		check.Mul(&a[i], &b[i])
 		if !check.Equal(&c[i]) {
-			invalidA := a[i]
-			invalidB := b[i]
-			invalidC := c[i]
-
-			return fmt.Errorf("%w: %q * %q != %q", backend.ErrUnsatisfiedConstraint,
-				invalidA.String(),
-				invalidB.String(),
-				invalidC.String())
+			return r1cs.unsatisfiedConstraintError(i, a[i], b[i], c[i])
 		}
 	}
 
 	return nil
 }
 
+// solvingError wraps the error of the hint solving the i-th constraint
+func (r1cs *R1CS) solvingError(i int, err error) error {
+	// the call sites are optional (eg a R1CS built without the frontend)
+	if i < len(r1cs.CallSites) {
+		return fmt.Errorf("constraint %d: %w at %s", i, err, r1cs.CallSites[i])
+	}
+	return fmt.Errorf("constraint %d: %w", i, err)
+}
+
+// unsatisfiedConstraintError describes the i-th constraint, whose instantiation a * b != c
+func (r1cs *R1CS) unsatisfiedConstraintError(i int, a, b, c fr.Element) error {
+	r1c := &r1cs.Constraints[i]
+	err := &backend.UnsatisfiedConstraintError{
+		ConstraintID: i,
+		L:            r1cs.linearExpressionString(r1c.L),
+		R:            r1cs.linearExpressionString(r1c.R),
+		O:            r1cs.linearExpressionString(r1c.O),
+	}
+	a.ToBigIntRegular(&err.A)
+	b.ToBigIntRegular(&err.B)
+	c.ToBigIntRegular(&err.C)
+	// the call sites are optional (eg a R1CS built without the frontend)
+	if i < len(r1cs.CallSites) {
+		err.CallSite = r1cs.CallSites[i]
+	}
+	return err
+}
+
+// linearExpressionString renders l with the wire names, the coefficients being printed in [-q/2, q/2]
+func (r1cs *R1CS) linearExpressionString(l LinearExpression) string {
+	if len(l) == 0 {
+		return "0"
+	}
+	modulus := fr.ElementModulus()
+	var halfModulus big.Int
+	halfModulus.Rsh(modulus, 1)
+
+	var sb strings.Builder
+	for i, t := range l {
+		var coeff big.Int
+		t.Coeff.ToBigIntRegular(&coeff)
+		if coeff.Cmp(&halfModulus) > 0 {
+			coeff.Sub(&coeff, modulus)
+		}
+		switch {
+		case i == 0 && coeff.Sign() < 0:
+			sb.WriteString("-")
+		case i > 0 && coeff.Sign() < 0:
+			sb.WriteString(" - ")
+		case i > 0:
+			sb.WriteString(" + ")
+		}
+		coeff.Abs(&coeff)
+		if !coeff.IsInt64() || coeff.Int64() != 1 {
+			sb.WriteString(coeff.String() + "*")
+		}
+		sb.WriteString(r1cs.wireName(int(t.ID)))
+	}
+	return sb.String()
+}
+
+// wireName returns the name of an input wire, the tags of an intermediate wire, or its ID
+func (r1cs *R1CS) wireName(id int) string {
+	privateOffset := r1cs.NbWires - r1cs.NbPublicWires - r1cs.NbPrivateWires
+	publicOffset := r1cs.NbWires - r1cs.NbPublicWires
+	switch {
+	case id >= publicOffset && id-publicOffset < len(r1cs.PublicWires):
+		return r1cs.PublicWires[id-publicOffset]
+	case id >= privateOffset && id-privateOffset < len(r1cs.PrivateWires):
+		return r1cs.PrivateWires[id-privateOffset]
+	case len(r1cs.WireTags[id]) > 0:
+		return strings.Join(r1cs.WireTags[id], "|")
+	}
+	return "wire_" + strconv.Itoa(id)
+}
+
 // Inspect returns the tagged variables with their corresponding value
 // If showsInput is set, it also puts in the resulting map the inputs (public and private).
 func (r1cs *R1CS) Inspect(solution backend.Assignments, showsInputs bool) (map[string]fr.Element, error) {
@@ -236,6 +313,8 @@ type solvingMethod int
 const (
 	SingleOutput solvingMethod = iota
 	BinaryDec
+	IsZero
+	Hint
 )
 
 // Term lightweight version of a term, no pointers
@@ -271,6 +350,7 @@ type R1C struct {
 	R      LinearExpression
 	O      LinearExpression
 	Solver frontend.SolvingMethod
+	HintID frontend.HintID // set if Solver is frontend.Hint
 }
 
 // String helper for a Rank1 Constraint
@@ -312,7 +392,8 @@ func (r1c *R1C) instantiate(r1cs *R1CS, wireValues []fr.Element) (a, b, c fr.Ele
 // alone, or it can be computed without ambiguity using the other computed wires
 // , eg when doing a binary decomposition: either way the missing wire can
 // be computed without ambiguity because the r1cs is correctly ordered)
-func (r1c *R1C) solveR1c(wireInstantiated []bool, wireValues []fr.Element) {
+// it fails only if the R1C is solved by a hint which fails
+func (r1c *R1C) solveR1c(wireInstantiated []bool, wireValues []fr.Element) error {
 
 	switch r1c.Solver {
 
@@ -407,7 +488,47 @@ func (r1c *R1C) solveR1c(wireInstantiated []bool, wireValues []fr.Element) {
 			}
 			i++
 		}
+
+	// in the case the R1C is x*m = 1 - res, where m is a hint: the inverse of x, or 0 if x == 0.
+	// Once m is set, res is the only uncomputed wire
+	case frontend.IsZero:
+
+		var x, tmp fr.Element
+		for _, t := range r1c.L {
+			tmp.Mul(&t.Coeff, &wireValues[t.ID])
+			x.Add(&x, &tmp)
+		}
+		m := r1c.R[0].ID
+		wireValues[m].Inverse(&x) // the inverse of 0 is 0
+		wireInstantiated[m] = true
+
+		singleOutput := R1C{L: r1c.L, R: r1c.R, O: r1c.O, Solver: frontend.SingleOutput}
+		return singleOutput.solveR1c(wireInstantiated, wireValues)
+
+	// in the case the R1C is inputs*0 = 0*outputs, the outputs being computed by the hint
+	// from the regular (non Mont) values of the inputs. An output can be an input wire (eg
+	// constrained to be equal to an input), in which case its value is kept, and checked by
+	// the other constraints
+	case frontend.Hint:
+
+		inputs := make([]*big.Int, len(r1c.L))
+		for i, t := range r1c.L {
+			inputs[i] = new(big.Int)
+			wireValues[t.ID].ToBigIntRegular(inputs[i])
+		}
+		outputs, err := frontend.SolveHint(r1c.HintID, fr.ElementModulus(), inputs, len(r1c.O))
+		if err != nil {
+			return err
+		}
+		for i, t := range r1c.O {
+			if !wireInstantiated[t.ID] {
+				wireValues[t.ID].SetBigInt(outputs[i])
+				wireInstantiated[t.ID] = true
+			}
+		}
+
 	default:
 		panic("unimplemented solving method")
 	}
+	return nil
 }
@@ -0,0 +1,142 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark/internal/generators DO NOT EDIT
+
+package backend_bls377
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/backend"
+
+	"github.com/consensys/gurvy/bls377/fr"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils/debug"
+)
+
+// SparseR1CS decsribes a set of PLONK constraints
+// qL⋅a + qR⋅b + qM⋅(a⋅b) + qO⋅c + qC = 0
+// see frontend.SparseR1CS
+type SparseR1CS struct {
+	// R1CS used to solve the wires shared by the two representations
+	R1CS R1CS
+
+	// Wires
+	NbWires int // includes the R1CS wires
+
+	// Constraints
+	NbConstraints int
+	Constraints   []SparseR1C
+}
+
+// SparseR1C PLONK constraint (wo pointers)
+// qL⋅a + qR⋅b + qM⋅(a⋅b) + qO⋅c + qC = 0
+type SparseR1C struct {
+	L, R, O            int64 // IDs of the wires a, b, c
+	QL, QR, QM, QO, QC fr.Element
+}
+
+// NewSparseR1CS return a typed SparseR1CS with the curve from frontend.SparseR1CS
+func NewSparseR1CS(cs *frontend.CS) SparseR1CS {
+
+	sparseR1CS := cs.ToSparseR1CS()
+
+	return CastSparseR1CS(sparseR1CS)
+}
+
+// CastSparseR1CS casts a frontend.SparseR1CS (whose coefficients are big.Int)
+// into a specialized SparseR1CS whose coefficients are fr elements
+func CastSparseR1CS(s *frontend.SparseR1CS) SparseR1CS {
+
+	toReturn := SparseR1CS{
+		R1CS:          Cast(&s.R1CS),
+		NbWires:       s.NbWires,
+		NbConstraints: s.NbConstraints,
+	}
+	toReturn.Constraints = make([]SparseR1C, len(s.Constraints))
+	for i := 0; i < len(s.Constraints); i++ {
+		from := s.Constraints[i]
+		to := &toReturn.Constraints[i]
+		to.L, to.R, to.O = from.L, from.R, from.O
+		to.QL.SetBigInt(&from.QL)
+		to.QR.SetBigInt(&from.QR)
+		to.QM.SetBigInt(&from.QM)
+		to.QO.SetBigInt(&from.QO)
+		to.QC.SetBigInt(&from.QC)
+	}
+
+	return toReturn
+}
+
+// Solve sets all the wires, in Montgomery form.
+// assignment: map[string]value: contains the input variables
+// wireValues =  [intermediateVariables | privateInputs | publicInputs | internal PLONK wires]
+func (s *SparseR1CS) Solve(assignment backend.Assignments, wireValues []fr.Element) error {
+	debug.Assert(len(wireValues) == s.NbWires)
+
+	// the wires shared with the R1CS are computed by the R1CS solver
+	nbR1CSWires := s.R1CS.NbWires
+	a := make([]fr.Element, s.R1CS.NbConstraints)
+	b := make([]fr.Element, s.R1CS.NbConstraints)
+	c := make([]fr.Element, s.R1CS.NbConstraints)
+	if err := s.R1CS.Solve(assignment, a, b, c, wireValues[:nbR1CSWires]); err != nil {
+		return err
+	}
+
+	// the internal wires are the output of the addition constraints splitting the linear expressions
+	// they are defined (qL⋅a + qR⋅b + qC = c) before being used
+	var tmp fr.Element
+	wireInstantiated := make([]bool, s.NbWires-nbR1CSWires)
+	for i := s.R1CS.NbPublicWires; i < len(s.Constraints); i++ {
+		sc := &s.Constraints[i]
+		if int(sc.O) >= nbR1CSWires && !wireInstantiated[int(sc.O)-nbR1CSWires] {
+			wireInstantiated[int(sc.O)-nbR1CSWires] = true
+			o := &wireValues[sc.O]
+			o.Mul(&sc.QL, &wireValues[sc.L])
+			tmp.Mul(&sc.QR, &wireValues[sc.R])
+			o.Add(o, &tmp).Add(o, &sc.QC)
+			tmp.Neg(&sc.QO)
+			o.Div(o, &tmp)
+		}
+	}
+
+	// check that the constraints are satisfied
+	for i := 0; i < len(s.Constraints); i++ {
+		check := s.Constraints[i].evaluate(wireValues)
+		if i < s.R1CS.NbPublicWires {
+			// public input
+			check.Add(&check, &wireValues[s.Constraints[i].L])
+		}
+		if !check.IsZero() {
+			return fmt.Errorf("%w: constraint %d: %s != 0", backend.ErrUnsatisfiedConstraint, i, check.String())
+		}
+	}
+
+	return nil
+}
+
+// evaluate returns qL⋅a + qR⋅b + qM⋅(a⋅b) + qO⋅c + qC
+func (sc *SparseR1C) evaluate(wireValues []fr.Element) fr.Element {
+	var res, tmp fr.Element
+	res.Mul(&sc.QL, &wireValues[sc.L])
+	tmp.Mul(&sc.QR, &wireValues[sc.R])
+	res.Add(&res, &tmp)
+	tmp.Mul(&wireValues[sc.L], &wireValues[sc.R]).Mul(&tmp, &sc.QM)
+	res.Add(&res, &tmp)
+	tmp.Mul(&sc.QO, &wireValues[sc.O])
+	res.Add(&res, &tmp).Add(&res, &sc.QC)
+	return res
+}
@@ -22,15 +22,26 @@ import (
 
 	backend_bls381 "github.com/consensys/gnark/backend/bls381"
 
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
 	"path/filepath"
+	"runtime"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/encoding/gob"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/generators/testcircuits/circuits"
 	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bls381/fp"
+
+	"reflect"
 )
 
 func TestCircuits(t *testing.T) {
@@ -66,6 +77,83 @@ func TestCircuits(t *testing.T) {
 	}
 }
 
+func TestOptimizedCircuits(t *testing.T) {
+	assert := NewAssert(t)
+	for name, circuit := range circuits.Circuits {
+		optimized, report := circuit.R1CS.Optimize(fr.ElementModulus())
+		if optimized.NbConstraints > circuit.R1CS.NbConstraints {
+			t.Fatal(name, "optimized R1CS has more constraints than the original one")
+		}
+		t.Log(name, report.NbConstraintsBefore, "->", report.NbConstraintsAfter, "constraints")
+		r1cs := backend_bls381.Cast(optimized)
+		assert.NotSolved(&r1cs, circuit.Bad)
+		assert.Solved(&r1cs, circuit.Good, nil)
+	}
+}
+
+func TestUnsatisfiedConstraintError(t *testing.T) {
+	assignment := backend.NewAssignment()
+	assignment.Assign(backend.Secret, "x", 2)
+	assignment.Assign(backend.Public, "y", 5)
+
+	// solve returns the error of Solve, which must have been created at the given line of this file
+	solve := func(cs *frontend.CS, line int) *backend.UnsatisfiedConstraintError {
+		t.Helper()
+		r1cs := backend_bls381.New(cs)
+		_, err := r1cs.Inspect(assignment, false)
+		if !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+			t.Fatal("expected ErrUnsatisfiedConstraint, got", err)
+		}
+		var unsatisfied *backend.UnsatisfiedConstraintError
+		if !errors.As(err, &unsatisfied) {
+			t.Fatal("expected an UnsatisfiedConstraintError, got", err)
+		}
+		if unsatisfied.ConstraintID < 0 || unsatisfied.ConstraintID >= r1cs.NbConstraints {
+			t.Fatal("invalid constraint index", unsatisfied.ConstraintID)
+		}
+		if site := filepath.Base(unsatisfied.CallSite); site != "groth16_test.go:"+strconv.Itoa(line) {
+			t.Fatal("expected the constraint to be created at line", line, "got", unsatisfied.CallSite)
+		}
+		if !strings.Contains(err.Error(), unsatisfied.CallSite) {
+			t.Fatal("the call site should be part of the error message", err)
+		}
+		return unsatisfied
+	}
+
+	// MUSTBE_EQ: x * x == y
+	cs := frontend.New()
+	x := cs.SECRET_INPUT("x")
+	y := cs.PUBLIC_INPUT("y")
+	cs.MUSTBE_EQ(cs.MUL(x, x), y)
+	_, _, line, _ := runtime.Caller(0)
+	err := solve(&cs, line-1)
+	if err.L != "x" || err.R != "x" || err.O != "y" {
+		t.Fatal("unexpected rendering of the constraint", err)
+	}
+	if err.A.Int64() != 2 || err.B.Int64() != 2 || err.C.Int64() != 5 {
+		t.Fatal("unexpected values", err)
+	}
+
+	// MUSTBE_BOOLEAN, on a tagged wire
+	cs = frontend.New()
+	x = cs.SECRET_INPUT("x")
+	double := cs.ADD(x, x)
+	double.Tag("double")
+	cs.MUSTBE_BOOLEAN(double)
+	_, _, line, _ = runtime.Caller(0)
+	err = solve(&cs, line-1)
+	if !strings.Contains(err.Error(), "double") {
+		t.Fatal("the tag should be part of the error message", err)
+	}
+
+	// MUSTBE_LESS_OR_EQ
+	cs = frontend.New()
+	y = cs.PUBLIC_INPUT("y")
+	cs.MUSTBE_LESS_OR_EQ(y, 4, 256)
+	_, _, line, _ = runtime.Caller(0)
+	solve(&cs, line-1)
+}
+
 func TestParsePublicInput(t *testing.T) {
 
 	expectedNames := [2]string{"data", "ONE_WIRE"}
@@ -108,6 +196,374 @@ func TestParsePublicInput(t *testing.T) {
 
 }
 
+func TestBatchVerify(t *testing.T) {
+	circuit := circuits.Circuits["reference_small"]
+	r1cs := backend_bls381.Cast(circuit.R1CS)
+
+	var pk ProvingKey
+	var vk VerifyingKey
+	Setup(&r1cs, &pk, &vk)
+
+	const nbProofs = 5
+	proofs := make([]*Proof, nbProofs)
+	inputs := make([]backend.Assignments, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		var err error
+		if proofs[i], err = Prove(&r1cs, &pk, circuit.Good); err != nil {
+			t.Fatal(err)
+		}
+		inputs[i] = circuit.Good.DiscardSecrets()
+	}
+
+	if ok, index, err := BatchVerify(proofs, &vk, inputs); err != nil || !ok || index != -1 {
+		t.Fatal("verifying a batch of correct proofs should return true", err)
+	}
+
+	// wrong public input
+	inputs[2] = circuit.Bad.DiscardSecrets()
+	if ok, index, err := BatchVerify(proofs, &vk, inputs); err != nil || ok || index != 2 {
+		t.Fatal("expected the batch to be rejected because of the 3rd proof, got", index, err)
+	}
+	inputs[2] = circuit.Good.DiscardSecrets()
+
+	// tampered proofs
+	proofs[3].Krs, proofs[4].Krs = proofs[4].Krs, proofs[3].Krs
+	if ok, index, err := BatchVerify(proofs, &vk, inputs); err != nil || ok || index != 3 {
+		t.Fatal("expected the batch to be rejected because of the 4th proof, got", index, err)
+	}
+
+	if _, _, err := BatchVerify(proofs, &vk, inputs[1:]); err != ErrBatchSize {
+		t.Fatal("expected ErrBatchSize")
+	}
+	if _, index, err := BatchVerify(proofs[:1], &vk, []backend.Assignments{backend.NewAssignment()}); err == nil || index != 0 {
+		t.Fatal("expected ErrInputNotSet on the first proof")
+	}
+}
+
+func TestCeremony(t *testing.T) {
+	circuit := circuits.Circuits["reference_small"]
+	r1cs := backend_bls381.Cast(circuit.R1CS)
+
+	// phase 1
+	phase1, err := NewPhase1(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		next := phase1.Contribute()
+		if err := VerifyPhase1(phase1, next); err != nil {
+			t.Fatal("phase 1 contribution", i, err)
+		}
+		phase1 = next
+	}
+
+	// a contribution which doesn't come with its proof of knowledge is rejected
+	tampered := phase1.Contribute()
+	tampered.PublicKeys.Tau.S.SetOne()
+	if err := VerifyPhase1(phase1, tampered); err == nil {
+		t.Fatal("expected an invalid phase 1 contribution")
+	}
+	tampered = phase1.Contribute()
+	tampered.G1.AlphaTau[3], tampered.G1.AlphaTau[4] = tampered.G1.AlphaTau[4], tampered.G1.AlphaTau[3]
+	if err := VerifyPhase1(phase1, tampered); err == nil {
+		t.Fatal("expected an invalid phase 1 contribution")
+	}
+
+	// phase 2
+	phase2, err := NewPhase2(&r1cs, phase1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		next := phase2.Contribute()
+		if err := VerifyPhase2(phase2, next); err != nil {
+			t.Fatal("phase 2 contribution", i, err)
+		}
+		phase2 = next
+	}
+	tampered2 := phase2.Contribute()
+	tampered2.G1.K[0] = tampered2.G1.K[1]
+	if err := VerifyPhase2(phase2, tampered2); err == nil {
+		t.Fatal("expected an invalid phase 2 contribution")
+	}
+
+	// the keys prove and verify
+	var pk ProvingKey
+	var vk VerifyingKey
+	Finalize(phase2, &pk, &vk)
+
+	proof, err := Prove(&r1cs, &pk, circuit.Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := Verify(proof, &vk, circuit.Good.DiscardSecrets()); err != nil || !ok {
+		t.Fatal("proof generated with the ceremony keys should verify", err)
+	}
+	if ok, _ := Verify(proof, &vk, circuit.Bad.DiscardSecrets()); ok {
+		t.Fatal("proof shouldn't verify with wrong public inputs")
+	}
+
+	// the phase 1 must be large enough for the circuit
+	small, _ := NewPhase1(2)
+	if _, err := NewPhase2(&r1cs, small); err != ErrCeremonyTooSmall {
+		t.Fatal("expected ErrCeremonyTooSmall")
+	}
+}
+
+func TestR1CSSerialization(t *testing.T) {
+	for name, circuit := range circuits.Circuits {
+		r1cs := backend_bls381.Cast(circuit.R1CS)
+
+		var buf bytes.Buffer
+		if _, err := r1cs.WriteTo(&buf); err != nil {
+			t.Fatal(name, err)
+		}
+		var r1csRead backend_bls381.R1CS
+		if _, err := r1csRead.ReadFrom(&buf); err != nil {
+			t.Fatal(name, err)
+		}
+		if !reflect.DeepEqual(r1cs, r1csRead) {
+			t.Fatal(name, "R1CS serialization round trip failed")
+		}
+	}
+
+	// a R1CS written with the version 1 of the format has no call sites
+	r1cs := backend_bls381.Cast(circuits.Circuits["reference_small"].R1CS)
+	r1cs.CallSites = nil
+	var buf bytes.Buffer
+	if _, err := r1cs.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	v1 := append([]byte(nil), encoded[:len(encoded)-4]...) // without the empty slice of call sites
+	binary.BigEndian.PutUint16(v1[4:6], 1)
+	var r1csRead backend_bls381.R1CS
+	if _, err := r1csRead.ReadFrom(bytes.NewReader(v1)); err != nil {
+		t.Fatal("couldn't read a version 1 R1CS", err)
+	}
+	if !reflect.DeepEqual(r1cs, r1csRead) {
+		t.Fatal("version 1 R1CS read incorrectly")
+	}
+
+	// the R1Cs solved by a hint can't be in a R1CS written before the version 3
+	hint := backend_bls381.Cast(circuits.Circuits["hint"].R1CS)
+	buf.Reset()
+	if _, err := hint.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	v2 := buf.Bytes()
+	binary.BigEndian.PutUint16(v2[4:6], 2)
+	if _, err := r1csRead.ReadFrom(bytes.NewReader(v2)); !errors.Is(err, backend.ErrInvalidR1CS) {
+		t.Fatal("expected ErrInvalidR1CS for a version 2 R1CS with hints, got", err)
+	}
+
+	// unknown versions are rejected
+	for _, version := range []uint16{0, backend.BinaryVersion + 1} {
+		binary.BigEndian.PutUint16(encoded[4:6], version)
+		if _, err := r1csRead.ReadFrom(bytes.NewReader(encoded)); !errors.Is(err, backend.ErrUnsupportedVersion) {
+			t.Fatal("expected ErrUnsupportedVersion for version", version, "got", err)
+		}
+	}
+}
+
+func TestR1CSCorrupted(t *testing.T) {
+	r1cs := backend_bls381.Cast(circuits.Circuits["div"].R1CS)
+	var buf bytes.Buffer
+	if _, err := r1cs.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	// a truncated R1CS is rejected
+	var r1csRead backend_bls381.R1CS
+	for i := 0; i < len(encoded); i++ {
+		if _, err := r1csRead.ReadFrom(bytes.NewReader(encoded[:i])); err == nil {
+			t.Fatal("R1CS truncated to", i, "bytes read without error")
+		}
+	}
+
+	// the corrupted lengths, counts and IDs must not crash the decoder
+	for i := 0; i+8 <= len(encoded); i++ {
+		corrupted := append([]byte(nil), encoded...)
+		binary.BigEndian.PutUint64(corrupted[i:i+8], ^uint64(0))
+		r1csRead.ReadFrom(bytes.NewReader(corrupted))
+	}
+
+	// a number of wires overflowing an int is rejected
+	_, headerSize, err := backend.ReadHeader(bytes.NewReader(encoded), gurvy.BLS381, backend.BinaryR1CS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overflow := append([]byte(nil), encoded...)
+	binary.BigEndian.PutUint64(overflow[headerSize:], ^uint64(0))
+	if _, err := r1csRead.ReadFrom(bytes.NewReader(overflow)); !errors.Is(err, backend.ErrInvalidR1CS) {
+		t.Fatal("expected ErrInvalidR1CS, got", err)
+	}
+
+	// unknown solving methods and wires out of range are rejected
+	invalid := []backend_bls381.R1C{
+		{Solver: frontend.Hint + 1},
+		{L: backend_bls381.LinearExpression{backend_bls381.Term{ID: 2}}},
+	}
+	for _, r1c := range invalid {
+		r1cs := backend_bls381.R1CS{
+			NbWires:       2,
+			NbConstraints: 1,
+			Constraints:   []backend_bls381.R1C{r1c},
+		}
+		buf.Reset()
+		if _, err := r1cs.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r1csRead.ReadFrom(&buf); !errors.Is(err, backend.ErrInvalidR1CS) {
+			t.Fatal("expected ErrInvalidR1CS, got", err)
+		}
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	circuit := circuits.Circuits["reference_small"]
+	r1cs := backend_bls381.Cast(circuit.R1CS)
+
+	var pk ProvingKey
+	var vk VerifyingKey
+	Setup(&r1cs, &pk, &vk)
+	proof, err := Prove(&r1cs, &pk, circuit.Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// R1CS
+	var buf bytes.Buffer
+	if _, err := r1cs.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var r1csRead backend_bls381.R1CS
+	if _, err := r1csRead.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r1cs, r1csRead) {
+		t.Fatal("R1CS serialization round trip failed")
+	}
+
+	type serializable interface {
+		io.WriterTo
+		io.ReaderFrom
+		WriteRawTo(io.Writer) (int64, error)
+	}
+	objects := []struct {
+		name          string
+		written, read serializable
+	}{
+		{"proving key", &pk, &ProvingKey{}},
+		{"verifying key", &vk, &VerifyingKey{}},
+		{"proof", proof, &Proof{}},
+	}
+
+	for _, o := range objects {
+		var compressed, raw bytes.Buffer
+		if n, err := o.written.WriteTo(&compressed); err != nil || n != int64(compressed.Len()) {
+			t.Fatal(o.name, "WriteTo failed", err)
+		}
+		if n, err := o.written.WriteRawTo(&raw); err != nil || n != int64(raw.Len()) {
+			t.Fatal(o.name, "WriteRawTo failed", err)
+		}
+		if compressed.Len() >= raw.Len() {
+			t.Fatal(o.name, "compressed encoding should be smaller")
+		}
+
+		// the encoding is deterministic
+		var again bytes.Buffer
+		o.written.WriteTo(&again)
+		if !bytes.Equal(again.Bytes(), compressed.Bytes()) {
+			t.Fatal(o.name, "encoding is not deterministic")
+		}
+
+		for _, encoded := range []*bytes.Buffer{&compressed, &raw} {
+			size := int64(encoded.Len())
+			if n, err := o.read.ReadFrom(encoded); err != nil || n != size {
+				t.Fatal(o.name, "ReadFrom failed", err)
+			}
+			if !reflect.DeepEqual(o.written, o.read) {
+				t.Fatal(o.name, "serialization round trip failed")
+			}
+		}
+	}
+
+	// keys and proofs read back work together
+	pkRead, vkRead := objects[0].read.(*ProvingKey), objects[1].read.(*VerifyingKey)
+	proofRead, err := Prove(&r1csRead, pkRead, circuit.Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := Verify(proofRead, vkRead, circuit.Good.DiscardSecrets()); err != nil || !ok {
+		t.Fatal("proof generated with deserialized keys should verify", err)
+	}
+
+	// invalid encodings
+	var encoded bytes.Buffer
+	proof.WriteRawTo(&encoded)
+	corrupt := func(offset int, value byte) *bytes.Reader {
+		b := append([]byte{}, encoded.Bytes()...)
+		b[offset] ^= value
+		return bytes.NewReader(b)
+	}
+	if _, err := new(VerifyingKey).ReadFrom(bytes.NewReader(encoded.Bytes())); !errors.Is(err, backend.ErrObjectMismatch) {
+		t.Fatal("expected ErrObjectMismatch, got", err)
+	}
+	if _, err := new(Proof).ReadFrom(corrupt(0, 1)); !errors.Is(err, backend.ErrInvalidMagic) {
+		t.Fatal("expected ErrInvalidMagic, got", err)
+	}
+	if _, err := new(Proof).ReadFrom(corrupt(7, 0xff)); !errors.Is(err, backend.ErrCurveMismatch) {
+		t.Fatal("expected ErrCurveMismatch, got", err)
+	}
+	if _, err := new(Proof).ReadFrom(corrupt(encoded.Len()-1, 1)); !errors.Is(err, backend.ErrInvalidPoint) {
+		t.Fatal("expected ErrInvalidPoint for a point not on the curve, got", err)
+	}
+	if _, err := new(Proof).ReadFrom(bytes.NewReader(encoded.Bytes()[:encoded.Len()-1])); err == nil {
+		t.Fatal("expected an error on a truncated proof")
+	}
+
+	// the raw proof is the header followed by 8 coordinates: Ar, Krs (G1) and Bs (G2)
+	fpSize := (encoded.Len() - 10) / 8
+
+	// a coordinate y + p is rejected
+	nonCanonical := append([]byte{}, encoded.Bytes()...)
+	arY := nonCanonical[10+fpSize : 10+2*fpSize]
+	var y big.Int
+	y.SetBytes(arY).Add(&y, fp.ElementModulus())
+	copy(arY[fpSize-len(y.Bytes()):], y.Bytes())
+	if _, err := new(Proof).ReadFrom(bytes.NewReader(nonCanonical)); !errors.Is(err, backend.ErrInvalidPoint) {
+		t.Fatal("expected ErrInvalidPoint for a non canonical coordinate, got", err)
+	}
+
+	// the points out of the subgroup of order r are rejected: the compressed x coordinates 1, 2, ...
+	// of Ar (G1) or Bs (G2) are tried until one of them is on the curve
+	var compressed bytes.Buffer
+	proof.WriteTo(&compressed)
+	offSubGroup := func(offset, size int, group string) {
+		for k := 1; k < 64; k++ {
+			b := append([]byte{}, compressed.Bytes()...)
+			x := b[offset : offset+size]
+			for i := range x {
+				x[i] = 0
+			}
+			x[0] = 0b10 << 6
+			x[size-1] = byte(k)
+			_, err := new(Proof).ReadFrom(bytes.NewReader(b))
+			if !errors.Is(err, backend.ErrInvalidPoint) {
+				t.Fatal("expected ErrInvalidPoint, got", err)
+			}
+			if strings.Contains(err.Error(), "subgroup") {
+				return
+			}
+		}
+		t.Fatal("no", group, "point out of the subgroup found")
+	}
+	offSubGroup(10, fpSize, "G1")
+	offSubGroup(10+2*fpSize, 2*fpSize, "G2")
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -187,3 +643,31 @@ func BenchmarkVerifier(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkBatchVerifier is a helper to benchmark BatchVerify on a given circuit
+// it will run the Setup, the Prover and reset the benchmark timer and benchmark the batch verifier
+func BenchmarkBatchVerifier(b *testing.B) {
+	const nbProofs = 16
+	r1cs, solution, _ := referenceCircuit()
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+	var pk ProvingKey
+	var vk VerifyingKey
+	Setup(&r1cs, &pk, &vk)
+	proofs := make([]*Proof, nbProofs)
+	inputs := make([]backend.Assignments, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		proof, err := Prove(&r1cs, &pk, solution)
+		if err != nil {
+			panic(err)
+		}
+		proofs[i] = proof
+		inputs[i] = solution.DiscardSecrets()
+	}
+
+	b.ResetTimer()
+	b.Run("batch verifier", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _ = BatchVerify(proofs, &vk, inputs)
+		}
+	})
+}
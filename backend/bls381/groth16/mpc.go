@@ -0,0 +1,718 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark/internal/generators DO NOT EDIT
+
+package groth16
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	curve "github.com/consensys/gurvy/bls381"
+	"github.com/consensys/gurvy/bls381/fr"
+
+	backend_bls381 "github.com/consensys/gnark/backend/bls381"
+
+	"github.com/consensys/gnark/internal/utils/parallel"
+)
+
+var (
+	ErrInvalidContribution = errors.New("invalid contribution")
+	ErrCeremonyTooSmall    = errors.New("the phase 1 of the ceremony is too small for this circuit")
+)
+
+/*
+	Multi-party computation ceremony
+	--------------------------------
+	Setup samples the toxic waste (τ, α, β, γ, δ) locally: whoever runs it can forge proofs.
+	The ceremony computes the proving and verifying keys such that the toxic waste remains unknown
+	as long as one of the contributors is honest (and discards their secrets).
+
+	- phase 1 (powers of tau) doesn't depend on the circuit, it samples τ, α, β
+	- phase 2 is initialized from the output of phase 1 and a circuit, it samples δ (γ is set to 1)
+	- Finalize outputs the keys from the phase 2 transcript
+
+	each contribution comes with a PublicKey, a proof of knowledge of the contribution, which allows
+	anyone to check that it was correctly applied to the previous transcript (VerifyPhase1, VerifyPhase2)
+*/
+
+// PublicKey proves the knowledge of the secret x of a contribution
+// and enables the verification of its application to the parameters
+type PublicKey struct {
+	XG1 curve.G1Affine // [x]1
+	XG2 curve.G2Affine // [x]2
+
+	// Schnorr proof of knowledge of x:
+	// R = [r]1, S = r + c⋅x, where c is the hash of the transcript, [x]1 and R
+	R curve.G1Affine
+	S fr.Element
+}
+
+// Phase1 is the transcript of the phase 1 (powers of tau) of the ceremony
+type Phase1 struct {
+	G1 struct {
+		Tau      []curve.G1Affine // {[τ⁰]1, [τ¹]1, [τ²]1, …, [τ²ⁿ⁻¹]1}
+		AlphaTau []curve.G1Affine // {α[τ⁰]1, α[τ¹]1, α[τ²]1, …, α[τⁿ⁻¹]1}
+		BetaTau  []curve.G1Affine // {β[τ⁰]1, β[τ¹]1, β[τ²]1, …, β[τⁿ⁻¹]1}
+	}
+	G2 struct {
+		Tau  []curve.G2Affine // {[τ⁰]2, [τ¹]2, [τ²]2, …, [τⁿ⁻¹]2}
+		Beta curve.G2Affine   // [β]2
+	}
+
+	// public keys of the last contribution
+	PublicKeys struct {
+		Tau, Alpha, Beta PublicKey
+	}
+
+	// hash of the transcript, chaining the public keys of the contributions
+	Hash []byte
+}
+
+// Phase2 is the transcript of the phase 2 (circuit specific) of the ceremony
+type Phase2 struct {
+	// ProvingKey and VerifyingKey parameters
+	// the ones which depend on δ are updated by the contributions
+	G1 struct {
+		Alpha, Beta, Delta curve.G1Affine
+		A, B               []curve.G1Affine
+		Z                  []curve.G1Affine // [τⁱ(τⁿ-1)/δ]1
+		K                  []curve.G1Affine // [(β⋅Aᵢ(τ)+α⋅Bᵢ(τ)+Cᵢ(τ))/δ]1, private wires
+		KVerifier          []curve.G1Affine // [β⋅Aᵢ(τ)+α⋅Bᵢ(τ)+Cᵢ(τ)]1, public wires (γ = 1)
+	}
+	G2 struct {
+		Beta, Delta curve.G2Affine
+		B           []curve.G2Affine
+	}
+
+	PublicInputs []string
+
+	// public key of the last contribution
+	PublicKey PublicKey
+
+	// hash of the transcript, chaining the public keys of the contributions
+	Hash []byte
+}
+
+// NewPhase1 returns the initial transcript of a phase 1 supporting circuits of up to size constraints
+// size must be a power of 2
+func NewPhase1(size int) (*Phase1, error) {
+	if size < 2 || bits.OnesCount(uint(size)) != 1 {
+		return nil, errors.New("the size of the ceremony must be a power of 2")
+	}
+	g1, g2 := generators()
+
+	p := &Phase1{}
+	p.G1.Tau = make([]curve.G1Affine, 2*size)
+	p.G1.AlphaTau = make([]curve.G1Affine, size)
+	p.G1.BetaTau = make([]curve.G1Affine, size)
+	p.G2.Tau = make([]curve.G2Affine, size)
+	for i := 0; i < len(p.G1.Tau); i++ {
+		p.G1.Tau[i] = g1
+	}
+	for i := 0; i < size; i++ {
+		p.G1.AlphaTau[i] = g1
+		p.G1.BetaTau[i] = g1
+		p.G2.Tau[i] = g2
+	}
+	p.G2.Beta = g2
+
+	return p, nil
+}
+
+// Contribute returns a new transcript, updated with the contribution of random τ, α, β
+// which are discarded
+func (p *Phase1) Contribute() *Phase1 {
+	var tau, alpha, beta fr.Element
+	tau.SetRandom()
+	alpha.SetRandom()
+	beta.SetRandom()
+
+	next := &Phase1{}
+	next.PublicKeys.Tau = newPublicKey(tau, p.Hash)
+	next.PublicKeys.Alpha = newPublicKey(alpha, p.Hash)
+	next.PublicKeys.Beta = newPublicKey(beta, p.Hash)
+	next.Hash = phase1Hash(p.Hash, &next.PublicKeys.Tau, &next.PublicKeys.Alpha, &next.PublicKeys.Beta)
+
+	var one fr.Element
+	one.SetOne()
+	next.G1.Tau = scaleG1(p.G1.Tau, one, tau)
+	next.G1.AlphaTau = scaleG1(p.G1.AlphaTau, alpha, tau)
+	next.G1.BetaTau = scaleG1(p.G1.BetaTau, beta, tau)
+	next.G2.Tau = scaleG2(p.G2.Tau, one, tau)
+	next.G2.Beta = scaleG2([]curve.G2Affine{p.G2.Beta}, beta, one)[0]
+
+	return next
+}
+
+// VerifyPhase1 checks that next is obtained by a valid contribution to prev
+func VerifyPhase1(prev, next *Phase1) error {
+	n := len(prev.G2.Tau)
+	if len(next.G1.Tau) != 2*n || len(next.G1.AlphaTau) != n || len(next.G1.BetaTau) != n || len(next.G2.Tau) != n {
+		return fmt.Errorf("%w: parameters have wrong sizes", ErrInvalidContribution)
+	}
+	g1, g2 := generators()
+	pks := &next.PublicKeys
+
+	// the contribution hash chains the public keys
+	if string(next.Hash) != string(phase1Hash(prev.Hash, &pks.Tau, &pks.Alpha, &pks.Beta)) {
+		return fmt.Errorf("%w: wrong hash", ErrInvalidContribution)
+	}
+
+	// proofs of knowledge of τ, α, β
+	for _, pk := range []*PublicKey{&pks.Tau, &pks.Alpha, &pks.Beta} {
+		if !pk.verify(prev.Hash) {
+			return fmt.Errorf("%w: wrong proof of knowledge", ErrInvalidContribution)
+		}
+	}
+
+	// the contributions are applied to the previous parameters
+	if !next.G1.Tau[0].Equal(&g1) || !next.G2.Tau[0].Equal(&g2) {
+		return fmt.Errorf("%w: [τ⁰] must be the generator", ErrInvalidContribution)
+	}
+	if next.G1.Tau[1].IsInfinity() || next.G1.AlphaTau[0].IsInfinity() || next.G1.BetaTau[0].IsInfinity() {
+		return fmt.Errorf("%w: degenerate parameters", ErrInvalidContribution)
+	}
+	if !sameRatio(prev.G1.Tau[1], next.G1.Tau[1], g2, pks.Tau.XG2) {
+		return fmt.Errorf("%w: τ was not applied", ErrInvalidContribution)
+	}
+	if !sameRatio(prev.G1.AlphaTau[0], next.G1.AlphaTau[0], g2, pks.Alpha.XG2) {
+		return fmt.Errorf("%w: α was not applied", ErrInvalidContribution)
+	}
+	if !sameRatio(prev.G1.BetaTau[0], next.G1.BetaTau[0], g2, pks.Beta.XG2) ||
+		!sameRatio(g1, next.G1.BetaTau[0], g2, next.G2.Beta) {
+		return fmt.Errorf("%w: β was not applied", ErrInvalidContribution)
+	}
+
+	// the parameters are successive powers of τ
+	tau1, tau2 := linearCombinationG1(next.G1.Tau)
+	if !sameRatio(tau1, tau2, g2, next.G2.Tau[1]) {
+		return fmt.Errorf("%w: [τⁱ]1 are not powers of τ", ErrInvalidContribution)
+	}
+	tau1, tau2 = linearCombinationG1(next.G1.AlphaTau)
+	if !sameRatio(tau1, tau2, g2, next.G2.Tau[1]) {
+		return fmt.Errorf("%w: α[τⁱ]1 are not powers of τ", ErrInvalidContribution)
+	}
+	tau1, tau2 = linearCombinationG1(next.G1.BetaTau)
+	if !sameRatio(tau1, tau2, g2, next.G2.Tau[1]) {
+		return fmt.Errorf("%w: β[τⁱ]1 are not powers of τ", ErrInvalidContribution)
+	}
+	tauG2, tauG2Next := linearCombinationG2(next.G2.Tau)
+	if !sameRatio(g1, next.G1.Tau[1], tauG2, tauG2Next) {
+		return fmt.Errorf("%w: [τⁱ]2 are not powers of τ", ErrInvalidContribution)
+	}
+
+	return nil
+}
+
+// NewPhase2 returns the initial transcript of the phase 2 of the ceremony for a circuit
+// from the (verified) transcript of the phase 1
+func NewPhase2(r1cs *backend_bls381.R1CS, phase1 *Phase1) (*Phase2, error) {
+	c := curve.BLS381()
+
+	domain := backend_bls381.NewDomain(root, backend_bls381.MaxOrder, r1cs.NbConstraints)
+	n := domain.Cardinality
+	if n > len(phase1.G2.Tau) {
+		return nil, ErrCeremonyTooSmall
+	}
+	g1, g2 := generators()
+
+	// Lagrange basis evaluated at τ: [Lᵢ(τ)]1, α[Lᵢ(τ)]1, β[Lᵢ(τ)]1, [Lᵢ(τ)]2
+	lG1 := lagrangeG1(phase1.G1.Tau[:n], domain)
+	lAlphaG1 := lagrangeG1(phase1.G1.AlphaTau[:n], domain)
+	lBetaG1 := lagrangeG1(phase1.G1.BetaTau[:n], domain)
+	lG2 := lagrangeG2(phase1.G2.Tau[:n], domain)
+
+	// [Aᵢ(τ)]1, [Bᵢ(τ)]1, [Bᵢ(τ)]2, [β⋅Aᵢ(τ)+α⋅Bᵢ(τ)+Cᵢ(τ)]1
+	nbWires := r1cs.NbWires
+	A := make([]curve.G1Jac, nbWires)
+	B := make([]curve.G1Jac, nbWires)
+	BG2 := make([]curve.G2Jac, nbWires)
+	K := make([]curve.G1Jac, nbWires)
+	var tmp curve.G1Jac
+	var tmpG2 curve.G2Jac
+	for i, r1c := range r1cs.Constraints {
+		for _, t := range r1c.L {
+			coeff := t.Coeff.ToRegular()
+			A[t.ID].Add(c, tmp.ScalarMul(c, &lG1[i], coeff))
+			K[t.ID].Add(c, tmp.ScalarMul(c, &lBetaG1[i], coeff))
+		}
+		for _, t := range r1c.R {
+			coeff := t.Coeff.ToRegular()
+			B[t.ID].Add(c, tmp.ScalarMul(c, &lG1[i], coeff))
+			BG2[t.ID].Add(c, tmpG2.ScalarMul(c, &lG2[i], coeff))
+			K[t.ID].Add(c, tmp.ScalarMul(c, &lAlphaG1[i], coeff))
+		}
+		for _, t := range r1c.O {
+			coeff := t.Coeff.ToRegular()
+			K[t.ID].Add(c, tmp.ScalarMul(c, &lG1[i], coeff))
+		}
+	}
+
+	p := &Phase2{}
+	p.PublicInputs = r1cs.PublicWires
+	p.G1.Alpha = phase1.G1.AlphaTau[0]
+	p.G1.Beta = phase1.G1.BetaTau[0]
+	p.G1.Delta = g1
+	p.G2.Beta = phase1.G2.Beta
+	p.G2.Delta = g2
+
+	p.G1.A = toAffineG1(A)
+	p.G1.B = toAffineG1(B)
+	p.G2.B = toAffineG2(BG2)
+	k := toAffineG1(K)
+	publicStartIndex := r1cs.NbWires - r1cs.NbPublicWires
+	p.G1.K = k[:publicStartIndex]
+	p.G1.KVerifier = k[publicStartIndex:]
+
+	// [τⁱ(τⁿ-1)]1 = [τⁱ⁺ⁿ]1 - [τⁱ]1
+	Z := make([]curve.G1Jac, n)
+	parallel.Execute(n, func(start, end int) {
+		var tmp curve.G1Jac
+		for i := start; i < end; i++ {
+			phase1.G1.Tau[i+n].ToJacobian(&Z[i])
+			phase1.G1.Tau[i].ToJacobian(&tmp)
+			Z[i].Sub(c, tmp)
+		}
+	})
+	p.G1.Z = toAffineG1(Z)
+
+	return p, nil
+}
+
+// Contribute returns a new transcript, updated with the contribution of a random δ
+// which is discarded
+func (p *Phase2) Contribute() *Phase2 {
+	var delta, deltaInv, one fr.Element
+	delta.SetRandom()
+	deltaInv.Inverse(&delta)
+	one.SetOne()
+
+	next := &Phase2{}
+	*next = *p
+	next.PublicKey = newPublicKey(delta, p.Hash)
+	next.Hash = phase2Hash(p.Hash, &next.PublicKey)
+
+	next.G1.Delta = scaleG1([]curve.G1Affine{p.G1.Delta}, delta, one)[0]
+	next.G2.Delta = scaleG2([]curve.G2Affine{p.G2.Delta}, delta, one)[0]
+	next.G1.Z = scaleG1(p.G1.Z, deltaInv, one)
+	next.G1.K = scaleG1(p.G1.K, deltaInv, one)
+
+	return next
+}
+
+// VerifyPhase2 checks that next is obtained by a valid contribution to prev
+func VerifyPhase2(prev, next *Phase2) error {
+	if len(next.G1.Z) != len(prev.G1.Z) || len(next.G1.K) != len(prev.G1.K) {
+		return fmt.Errorf("%w: parameters have wrong sizes", ErrInvalidContribution)
+	}
+	g1, g2 := generators()
+
+	// the contribution hash chains the public keys
+	if string(next.Hash) != string(phase2Hash(prev.Hash, &next.PublicKey)) {
+		return fmt.Errorf("%w: wrong hash", ErrInvalidContribution)
+	}
+
+	// proof of knowledge of δ
+	if !next.PublicKey.verify(prev.Hash) {
+		return fmt.Errorf("%w: wrong proof of knowledge", ErrInvalidContribution)
+	}
+
+	// the parameters which don't depend on δ are unchanged
+	if !next.G1.Alpha.Equal(&prev.G1.Alpha) || !next.G1.Beta.Equal(&prev.G1.Beta) || !next.G2.Beta.Equal(&prev.G2.Beta) ||
+		!equalG1(next.G1.A, prev.G1.A) || !equalG1(next.G1.B, prev.G1.B) || !equalG2(next.G2.B, prev.G2.B) ||
+		!equalG1(next.G1.KVerifier, prev.G1.KVerifier) || len(next.PublicInputs) != len(prev.PublicInputs) {
+		return fmt.Errorf("%w: parameters independent of δ were modified", ErrInvalidContribution)
+	}
+	for i := 0; i < len(next.PublicInputs); i++ {
+		if next.PublicInputs[i] != prev.PublicInputs[i] {
+			return fmt.Errorf("%w: parameters independent of δ were modified", ErrInvalidContribution)
+		}
+	}
+
+	// δ was applied
+	if next.G1.Delta.IsInfinity() {
+		return fmt.Errorf("%w: degenerate parameters", ErrInvalidContribution)
+	}
+	if !sameRatio(prev.G1.Delta, next.G1.Delta, g2, next.PublicKey.XG2) ||
+		!sameRatio(g1, next.G1.Delta, g2, next.G2.Delta) {
+		return fmt.Errorf("%w: δ was not applied", ErrInvalidContribution)
+	}
+
+	// 1/δ was applied: e(next, [δ]2) == e(prev, [δ_prev]2)
+	kNext, kPrev := randomCombinationG1(next.G1.K, prev.G1.K)
+	if !sameRatio(kNext, kPrev, prev.G2.Delta, next.G2.Delta) {
+		return fmt.Errorf("%w: 1/δ was not applied to [Kpk]1", ErrInvalidContribution)
+	}
+	zNext, zPrev := randomCombinationG1(next.G1.Z, prev.G1.Z)
+	if !sameRatio(zNext, zPrev, prev.G2.Delta, next.G2.Delta) {
+		return fmt.Errorf("%w: 1/δ was not applied to [Z]1", ErrInvalidContribution)
+	}
+
+	return nil
+}
+
+// Finalize outputs the proving and verifying keys from the transcript of the phase 2
+func Finalize(phase2 *Phase2, pk *ProvingKey, vk *VerifyingKey) {
+	c := curve.BLS381()
+	_, g2 := generators()
+
+	pk.G1.Alpha = phase2.G1.Alpha
+	pk.G1.Beta = phase2.G1.Beta
+	pk.G1.Delta = phase2.G1.Delta
+	pk.G1.A = phase2.G1.A
+	pk.G1.B = phase2.G1.B
+	pk.G1.Z = phase2.G1.Z
+	pk.G1.K = phase2.G1.K
+	pk.G2.Beta = phase2.G2.Beta
+	pk.G2.Delta = phase2.G2.Delta
+	pk.G2.B = phase2.G2.B
+
+	vk.G1.Alpha = phase2.G1.Alpha
+	vk.G1.K = phase2.G1.KVerifier
+	vk.G2.Beta = phase2.G2.Beta
+	vk.G2.GammaNeg.Neg(&g2)
+	vk.G2.DeltaNeg.Neg(&phase2.G2.Delta)
+	vk.E = c.FinalExponentiation(c.MillerLoop(vk.G1.Alpha, vk.G2.Beta, &vk.E))
+	vk.PublicInputs = phase2.PublicInputs
+}
+
+// newPublicKey returns the PublicKey of the contribution x to the transcript of hash transcriptHash
+func newPublicKey(x fr.Element, transcriptHash []byte) PublicKey {
+	c := curve.BLS381()
+	var pk PublicKey
+	var tmp curve.G1Jac
+	var tmpG2 curve.G2Jac
+	tmp.ScalarMulByGen(c, x.ToRegular()).ToAffineFromJac(&pk.XG1)
+	tmpG2.ScalarMulByGen(c, x.ToRegular()).ToAffineFromJac(&pk.XG2)
+
+	var r fr.Element
+	r.SetRandom()
+	tmp.ScalarMulByGen(c, r.ToRegular()).ToAffineFromJac(&pk.R)
+
+	challenge := pk.challenge(transcriptHash)
+	pk.S.Mul(&challenge, &x).Add(&pk.S, &r)
+
+	return pk
+}
+
+// verify checks the proof of knowledge [s]1 == R + c⋅[x]1 and that [x]1 and [x]2 are consistent
+func (pk *PublicKey) verify(transcriptHash []byte) bool {
+	c := curve.BLS381()
+	g1, g2 := generators()
+
+	if pk.XG1.IsInfinity() || !sameRatio(g1, pk.XG1, g2, pk.XG2) {
+		return false
+	}
+
+	challenge := pk.challenge(transcriptHash)
+	var left, right curve.G1Jac
+	left.ScalarMulByGen(c, pk.S.ToRegular())
+	pk.XG1.ToJacobian(&right)
+	right.ScalarMul(c, &right, challenge.ToRegular())
+	right.AddMixed(&pk.R)
+
+	return left.Equal(&right)
+}
+
+func (pk *PublicKey) challenge(transcriptHash []byte) fr.Element {
+	h := sha256.New()
+	h.Write(transcriptHash)
+	h.Write(pk.XG1.X.Bytes())
+	h.Write(pk.XG1.Y.Bytes())
+	h.Write(pk.R.X.Bytes())
+	h.Write(pk.R.Y.Bytes())
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
+
+func (pk *PublicKey) write(h interface{ Write([]byte) (int, error) }) {
+	h.Write(pk.XG1.X.Bytes())
+	h.Write(pk.XG1.Y.Bytes())
+	h.Write(pk.XG2.X.A0.Bytes())
+	h.Write(pk.XG2.X.A1.Bytes())
+	h.Write(pk.XG2.Y.A0.Bytes())
+	h.Write(pk.XG2.Y.A1.Bytes())
+	h.Write(pk.R.X.Bytes())
+	h.Write(pk.R.Y.Bytes())
+	h.Write(pk.S.Bytes())
+}
+
+func phase1Hash(prev []byte, tau, alpha, beta *PublicKey) []byte {
+	h := sha256.New()
+	h.Write([]byte("phase1"))
+	h.Write(prev)
+	tau.write(h)
+	alpha.write(h)
+	beta.write(h)
+	return h.Sum(nil)
+}
+
+func phase2Hash(prev []byte, delta *PublicKey) []byte {
+	h := sha256.New()
+	h.Write([]byte("phase2"))
+	h.Write(prev)
+	delta.write(h)
+	return h.Sum(nil)
+}
+
+// generators returns [1]1, [1]2
+func generators() (curve.G1Affine, curve.G2Affine) {
+	c := curve.BLS381()
+	var one fr.Element
+	one.SetOne()
+	var g1 curve.G1Jac
+	var g2 curve.G2Jac
+	var g1Aff curve.G1Affine
+	var g2Aff curve.G2Affine
+	g1.ScalarMulByGen(c, one.ToRegular()).ToAffineFromJac(&g1Aff)
+	g2.ScalarMulByGen(c, one.ToRegular()).ToAffineFromJac(&g2Aff)
+	return g1Aff, g2Aff
+}
+
+// sameRatio checks that e(a, d) == e(b, c), that is b/a == d/c in the exponent
+func sameRatio(a, b curve.G1Affine, c, d curve.G2Affine) bool {
+	cu := curve.BLS381()
+	var bNeg curve.G1Affine
+	bNeg.Neg(&b)
+	var e1, e2, one curve.PairingResult
+	cu.MillerLoop(a, d, &e1)
+	cu.MillerLoop(bNeg, c, &e2)
+	res := cu.FinalExponentiation(&e1, &e2)
+	one.SetOne()
+	return res.Equal(&one)
+}
+
+// scaleG1 returns {points[i]⋅(a⋅xⁱ)}
+func scaleG1(points []curve.G1Affine, a, x fr.Element) []curve.G1Affine {
+	c := curve.BLS381()
+	scalars := powers(a, x, len(points))
+	res := make([]curve.G1Affine, len(points))
+	parallel.Execute(len(points), func(start, end int) {
+		var p curve.G1Jac
+		for i := start; i < end; i++ {
+			points[i].ToJacobian(&p)
+			p.ScalarMul(c, &p, scalars[i]).ToAffineFromJac(&res[i])
+		}
+	})
+	return res
+}
+
+// scaleG2 returns {points[i]⋅(a⋅xⁱ)}
+func scaleG2(points []curve.G2Affine, a, x fr.Element) []curve.G2Affine {
+	c := curve.BLS381()
+	scalars := powers(a, x, len(points))
+	res := make([]curve.G2Affine, len(points))
+	parallel.Execute(len(points), func(start, end int) {
+		var p curve.G2Jac
+		for i := start; i < end; i++ {
+			points[i].ToJacobian(&p)
+			p.ScalarMul(c, &p, scalars[i]).ToAffineFromJac(&res[i])
+		}
+	})
+	return res
+}
+
+// powers returns {a⋅xⁱ}, in regular form
+func powers(a, x fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	acc := a
+	for i := 0; i < n; i++ {
+		res[i] = acc.ToRegular()
+		acc.MulAssign(&x)
+	}
+	return res
+}
+
+// linearCombinationG1 returns Σρⁱ⋅points[i] and Σρⁱ⋅points[i+1], for a random ρ
+func linearCombinationG1(points []curve.G1Affine) (curve.G1Affine, curve.G1Affine) {
+	c := curve.BLS381()
+	var rho, one fr.Element
+	rho.SetRandom()
+	one.SetOne()
+	scalars := powers(one, rho, len(points)-1)
+	var l, r curve.G1Jac
+	var lAff, rAff curve.G1Affine
+	<-l.MultiExp(c, points[:len(points)-1], scalars)
+	<-r.MultiExp(c, points[1:], scalars)
+	l.ToAffineFromJac(&lAff)
+	r.ToAffineFromJac(&rAff)
+	return lAff, rAff
+}
+
+// linearCombinationG2 returns Σρⁱ⋅points[i] and Σρⁱ⋅points[i+1], for a random ρ
+func linearCombinationG2(points []curve.G2Affine) (curve.G2Affine, curve.G2Affine) {
+	c := curve.BLS381()
+	var rho, one fr.Element
+	rho.SetRandom()
+	one.SetOne()
+	scalars := powers(one, rho, len(points)-1)
+	var l, r curve.G2Jac
+	var lAff, rAff curve.G2Affine
+	<-l.MultiExp(c, points[:len(points)-1], scalars)
+	<-r.MultiExp(c, points[1:], scalars)
+	l.ToAffineFromJac(&lAff)
+	r.ToAffineFromJac(&rAff)
+	return lAff, rAff
+}
+
+// randomCombinationG1 returns Σρᵢ⋅a[i] and Σρᵢ⋅b[i], for random ρᵢ
+func randomCombinationG1(a, b []curve.G1Affine) (curve.G1Affine, curve.G1Affine) {
+	c := curve.BLS381()
+	scalars := make([]fr.Element, len(a))
+	for i := 0; i < len(scalars); i++ {
+		scalars[i].SetRandom().FromMont()
+	}
+	var l, r curve.G1Jac
+	var lAff, rAff curve.G1Affine
+	<-l.MultiExp(c, a, scalars)
+	<-r.MultiExp(c, b, scalars)
+	l.ToAffineFromJac(&lAff)
+	r.ToAffineFromJac(&rAff)
+	return lAff, rAff
+}
+
+// lagrangeG1 returns {[Lᵢ(τ)]1} from {[τⁱ]1}, Lᵢ being the Lagrange polynomials of the domain
+// [Lᵢ(τ)]1 = 1/n⋅Σⱼ ω⁻ⁱʲ[τʲ]1, which is an inverse FFT
+func lagrangeG1(powers []curve.G1Affine, domain *backend_bls381.Domain) []curve.G1Jac {
+	c := curve.BLS381()
+	res := make([]curve.G1Jac, len(powers))
+	for i := 0; i < len(powers); i++ {
+		powers[i].ToJacobian(&res[i])
+	}
+	n := len(res)
+	twiddles := twiddles(domain.GeneratorInv, n)
+	bitReverse(n, func(i, j int) { res[i], res[j] = res[j], res[i] })
+	for m := 2; m <= n; m <<= 1 {
+		stride := n / m
+		parallel.Execute(n/2, func(start, end int) {
+			var t curve.G1Jac
+			for b := start; b < end; b++ {
+				k, j := (b/(m/2))*m, b%(m/2)
+				t.ScalarMul(c, &res[k+j+m/2], twiddles[j*stride])
+				res[k+j+m/2].Set(&res[k+j])
+				res[k+j+m/2].Sub(c, t)
+				res[k+j].Add(c, &t)
+			}
+		})
+	}
+	cardinalityInv := domain.CardinalityInv.ToRegular()
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].ScalarMul(c, &res[i], cardinalityInv)
+		}
+	})
+	return res
+}
+
+// lagrangeG2 returns {[Lᵢ(τ)]2} from {[τⁱ]2}, see lagrangeG1
+func lagrangeG2(powers []curve.G2Affine, domain *backend_bls381.Domain) []curve.G2Jac {
+	c := curve.BLS381()
+	res := make([]curve.G2Jac, len(powers))
+	for i := 0; i < len(powers); i++ {
+		powers[i].ToJacobian(&res[i])
+	}
+	n := len(res)
+	twiddles := twiddles(domain.GeneratorInv, n)
+	bitReverse(n, func(i, j int) { res[i], res[j] = res[j], res[i] })
+	for m := 2; m <= n; m <<= 1 {
+		stride := n / m
+		parallel.Execute(n/2, func(start, end int) {
+			var t curve.G2Jac
+			for b := start; b < end; b++ {
+				k, j := (b/(m/2))*m, b%(m/2)
+				t.ScalarMul(c, &res[k+j+m/2], twiddles[j*stride])
+				res[k+j+m/2].Set(&res[k+j])
+				res[k+j+m/2].Sub(c, t)
+				res[k+j].Add(c, &t)
+			}
+		})
+	}
+	cardinalityInv := domain.CardinalityInv.ToRegular()
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].ScalarMul(c, &res[i], cardinalityInv)
+		}
+	})
+	return res
+}
+
+// twiddles returns {wⁱ}, i < n/2, in regular form
+func twiddles(w fr.Element, n int) []fr.Element {
+	var one fr.Element
+	one.SetOne()
+	if n < 2 {
+		return nil
+	}
+	return powers(one, w, n/2)
+}
+
+// bitReverse calls swap on the pairs of indexes which are bit reversed of each other
+func bitReverse(n int, swap func(i, j int)) {
+	nn := uint(bits.Len(uint(n)) - 1)
+	for i := 0; i < n; i++ {
+		j := int(bits.Reverse(uint(i)) >> (bits.UintSize - nn))
+		if i < j {
+			swap(i, j)
+		}
+	}
+}
+
+func toAffineG1(points []curve.G1Jac) []curve.G1Affine {
+	res := make([]curve.G1Affine, len(points))
+	parallel.Execute(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			points[i].ToAffineFromJac(&res[i])
+		}
+	})
+	return res
+}
+
+func toAffineG2(points []curve.G2Jac) []curve.G2Affine {
+	res := make([]curve.G2Affine, len(points))
+	parallel.Execute(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			points[i].ToAffineFromJac(&res[i])
+		}
+	})
+	return res
+}
+
+func equalG1(a, b []curve.G1Affine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if !a[i].Equal(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalG2(a, b []curve.G2Affine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if !a[i].Equal(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,314 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark/internal/generators DO NOT EDIT
+
+package plonk
+
+import (
+	curve "github.com/consensys/gurvy/bls381"
+	"github.com/consensys/gurvy/bls381/fr"
+
+	backend_bls381 "github.com/consensys/gnark/backend/bls381"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/utils/parallel"
+)
+
+// Proof represents a PLONK proof that was encoded with a ProvingKey and can be verified
+// with a valid statement and a VerifyingKey
+type Proof struct {
+	// commitments to the wires polynomials, the permutation accumulator and the quotient
+	LRO [3]curve.G1Affine
+	Z   curve.G1Affine
+	T   curve.G1Affine
+
+	// evaluations at ζ of the committed polynomials, in the order of Proof.commitments()
+	Evaluations [13]fr.Element
+
+	// Z(ζω)
+	ZShifted fr.Element
+
+	// opening proofs at ζ (batched) and ζω
+	W, WShifted curve.G1Affine
+}
+
+// index of the evaluations in Proof.Evaluations
+const (
+	idxL = iota
+	idxR
+	idxO
+	idxQl
+	idxQr
+	idxQm
+	idxQo
+	idxQk
+	idxS1
+	idxS2
+	idxS3
+	idxZ
+	idxT
+)
+
+// Prove creates a proof from a circuit
+func Prove(spr *backend_bls381.SparseR1CS, pk *ProvingKey, solution backend.Assignments) (*Proof, error) {
+	proof := &Proof{}
+
+	// Solve the constraint system
+	wireValues := make([]fr.Element, spr.NbWires)
+	if err := spr.Solve(solution, wireValues); err != nil {
+		return nil, err
+	}
+
+	n := pk.Size
+	domain := backend_bls381.NewDomain(root, backend_bls381.MaxOrder, n)
+
+	// public inputs
+	publicInputs := make([]fr.Element, n)
+	offset := spr.R1CS.NbWires - spr.R1CS.NbPublicWires
+	copy(publicInputs, wireValues[offset:offset+spr.R1CS.NbPublicWires])
+
+	fs := newTranscript()
+	fs.appendScalars(toPointers(publicInputs[:spr.R1CS.NbPublicWires])...)
+
+	// 1 - wires polynomials a, b, c, blinded by (b0⋅X + b1)⋅Z_H
+	lro := wiresLagrange(spr, wireValues, n)
+	var polynomials [3][]fr.Element
+	for i := 0; i < 3; i++ {
+		p := make([]fr.Element, n)
+		copy(p, lro[i])
+		interpolate(p, domain)
+		polynomials[i] = blind(p, n, randomElements(2)...)
+		proof.LRO[i] = commit(pk.G1, polynomials[i])
+	}
+	fs.appendPoints(&proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
+	beta := fs.challenge()
+	gamma := fs.challenge()
+
+	// 2 - permutation accumulator Z, blinded by (b0⋅X² + b1⋅X + b2)⋅Z_H
+	z := permutationAccumulator(pk, lro, beta, gamma)
+	interpolate(z, domain)
+	z = blind(z, n, randomElements(3)...)
+	proof.Z = commit(pk.G1, z)
+	fs.appendPoints(&proof.Z)
+	alpha := fs.challenge()
+
+	// 3 - quotient t = (gate + α⋅permutation + α²⋅(Z-1)⋅L0) / Z_H
+	interpolate(publicInputs, domain)
+	t := computeQuotient(pk, polynomials, z, publicInputs, alpha, beta, gamma)
+	proof.T = commit(pk.G1, t)
+	fs.appendPoints(&proof.T)
+	zeta := fs.challenge()
+
+	// 4 - evaluations at ζ
+	committed := [13][]fr.Element{
+		polynomials[0], polynomials[1], polynomials[2],
+		pk.Ql, pk.Qr, pk.Qm, pk.Qo, pk.Qk,
+		pk.S1, pk.S2, pk.S3,
+		z, t,
+	}
+	for i := 0; i < len(committed); i++ {
+		proof.Evaluations[i] = evaluate(committed[i], zeta)
+	}
+	var zetaShifted fr.Element
+	zetaShifted.Mul(&zeta, &pk.Generator)
+	proof.ZShifted = evaluate(z, zetaShifted)
+	fs.appendScalars(toPointers(proof.Evaluations[:])...)
+	fs.appendScalars(&proof.ZShifted)
+	v := fs.challenge()
+
+	// 5 - opening proofs
+	// W = Σ vⁱ⋅(Pᵢ(X) - Pᵢ(ζ)) / (X - ζ)
+	folded := make([]fr.Element, len(pk.G1))
+	var vi, tmp fr.Element
+	vi.SetOne()
+	for i := 0; i < len(committed); i++ {
+		for j := 0; j < len(committed[i]); j++ {
+			tmp.Mul(&committed[i][j], &vi)
+			folded[j].Add(&folded[j], &tmp)
+		}
+		vi.Mul(&vi, &v)
+	}
+	proof.W = commit(pk.G1, divideByLinear(folded, zeta))
+	proof.WShifted = commit(pk.G1, divideByLinear(z, zetaShifted))
+
+	return proof, nil
+}
+
+// wiresLagrange returns the values of the left, right and output wires on each constraint
+// the padding constraints use the left wire of the first constraint
+func wiresLagrange(spr *backend_bls381.SparseR1CS, wireValues []fr.Element, n int) [3][]fr.Element {
+	var res [3][]fr.Element
+	for i := 0; i < 3; i++ {
+		res[i] = make([]fr.Element, n)
+	}
+	filler := wireValues[spr.Constraints[0].L]
+	for i := 0; i < n; i++ {
+		if i < len(spr.Constraints) {
+			res[0][i] = wireValues[spr.Constraints[i].L]
+			res[1][i] = wireValues[spr.Constraints[i].R]
+			res[2][i] = wireValues[spr.Constraints[i].O]
+		} else {
+			res[0][i], res[1][i], res[2][i] = filler, filler, filler
+		}
+	}
+	return res
+}
+
+// permutationAccumulator returns the values of Z on the domain
+// Z(1) = 1, Z(ωⁱ⁺¹) = Z(ωⁱ)⋅Π(wⱼ(ωⁱ) + β⋅labelⱼ(i) + γ)/Π(wⱼ(ωⁱ) + β⋅labelⱼ(σ(i)) + γ)
+func permutationAccumulator(pk *ProvingKey, lro [3][]fr.Element, beta, gamma fr.Element) []fr.Element {
+	n := pk.Size
+	labels := slotLabels(n, pk.Generator, pk.Shifter)
+
+	num := make([]fr.Element, n)
+	den := make([]fr.Element, n)
+	parallel.Execute(n, func(start, end int) {
+		var f, g fr.Element
+		for i := start; i < end; i++ {
+			num[i].SetOne()
+			den[i].SetOne()
+			for j := 0; j < 3; j++ {
+				f.Mul(&beta, &labels[j*n+i]).Add(&f, &gamma).Add(&f, &lro[j][i])
+				g.Mul(&beta, &labels[pk.Permutation[j*n+i]]).Add(&g, &gamma).Add(&g, &lro[j][i])
+				num[i].MulAssign(&f)
+				den[i].MulAssign(&g)
+			}
+		}
+	})
+
+	z := make([]fr.Element, n)
+	z[0].SetOne()
+	for i := 0; i < n-1; i++ {
+		z[i+1].Div(&num[i], &den[i]).MulAssign(&z[i])
+	}
+	return z
+}
+
+// computeQuotient returns t = (gate + α⋅permutation + α²⋅(Z-1)⋅L0) / Z_H in canonical form
+// the numerator is evaluated on a coset of a domain large enough to interpolate it
+func computeQuotient(pk *ProvingKey, lro [3][]fr.Element, z, publicInputs []fr.Element, alpha, beta, gamma fr.Element) []fr.Element {
+	n := pk.Size
+	domain := backend_bls381.NewDomain(root, backend_bls381.MaxOrder, maxDegree(n)+n+1)
+	m := domain.Cardinality
+	shift := domain.GeneratorSqRt
+
+	// L0 = (Xⁿ - 1) / (n⋅(X - 1)), its canonical form is (1/n, .., 1/n)
+	l0 := make([]fr.Element, n)
+	var nInv fr.Element
+	nInv.SetUint64(uint64(n)).Inverse(&nInv)
+	for i := 0; i < n; i++ {
+		l0[i] = nInv
+	}
+
+	polynomials := [][]fr.Element{
+		lro[0], lro[1], lro[2],
+		pk.Ql, pk.Qr, pk.Qm, pk.Qo, pk.Qk,
+		pk.S1, pk.S2, pk.S3,
+		z, publicInputs, l0,
+	}
+	evaluations := make([][]fr.Element, len(polynomials))
+	parallel.Execute(len(polynomials), func(start, end int) {
+		for i := start; i < end; i++ {
+			evaluations[i] = evaluateOnCoset(polynomials[i], domain, shift)
+		}
+	})
+	a, b, c := evaluations[0], evaluations[1], evaluations[2]
+	ql, qr, qm, qo, qk := evaluations[3], evaluations[4], evaluations[5], evaluations[6], evaluations[7]
+	s1, s2, s3 := evaluations[8], evaluations[9], evaluations[10]
+	zz, pi, lz := evaluations[11], evaluations[12], evaluations[13]
+
+	// Z_H(x) = xⁿ - 1 takes m/n values on the coset
+	ratio := m / n
+	zhInv := make([]fr.Element, ratio)
+	var one, wn fr.Element
+	one.SetOne()
+	wn.Exp(domain.Generator, uint64(n))
+	zhInv[0].Exp(shift, uint64(n))
+	for i := 1; i < ratio; i++ {
+		zhInv[i].Mul(&zhInv[i-1], &wn)
+	}
+	for i := 0; i < ratio; i++ {
+		zhInv[i].Sub(&zhInv[i], &one).Inverse(&zhInv[i])
+	}
+
+	var alphaSquare fr.Element
+	alphaSquare.Square(&alpha)
+
+	t := make([]fr.Element, m)
+	parallel.Execute(m, func(start, end int) {
+		var x, gate, f, g, tmp fr.Element
+		x.Exp(domain.Generator, uint64(start)).MulAssign(&shift)
+		for i := start; i < end; i++ {
+			// gate
+			gate.Mul(&ql[i], &a[i])
+			tmp.Mul(&qr[i], &b[i])
+			gate.Add(&gate, &tmp)
+			tmp.Mul(&a[i], &b[i]).MulAssign(&qm[i])
+			gate.Add(&gate, &tmp)
+			tmp.Mul(&qo[i], &c[i])
+			gate.Add(&gate, &tmp).Add(&gate, &qk[i]).Add(&gate, &pi[i])
+
+			// permutation
+			f.Mul(&beta, &x).Add(&f, &gamma).Add(&f, &a[i])
+			tmp.Mul(&beta, &x).MulAssign(&pk.Shifter[0]).Add(&tmp, &gamma).Add(&tmp, &b[i])
+			f.MulAssign(&tmp)
+			tmp.Mul(&beta, &x).MulAssign(&pk.Shifter[1]).Add(&tmp, &gamma).Add(&tmp, &c[i])
+			f.MulAssign(&tmp).MulAssign(&zz[i])
+
+			g.Mul(&beta, &s1[i]).Add(&g, &gamma).Add(&g, &a[i])
+			tmp.Mul(&beta, &s2[i]).Add(&tmp, &gamma).Add(&tmp, &b[i])
+			g.MulAssign(&tmp)
+			tmp.Mul(&beta, &s3[i]).Add(&tmp, &gamma).Add(&tmp, &c[i])
+			g.MulAssign(&tmp).MulAssign(&zz[(i+ratio)%m])
+
+			f.Sub(&f, &g).MulAssign(&alpha)
+
+			// Z(1) = 1
+			tmp.Sub(&zz[i], &one).MulAssign(&lz[i]).MulAssign(&alphaSquare)
+
+			t[i].Add(&gate, &f).Add(&t[i], &tmp).MulAssign(&zhInv[i%ratio])
+
+			x.MulAssign(&domain.Generator)
+		}
+	})
+
+	// back to canonical form
+	backend_bls381.FFT(t, domain.GeneratorInv)
+	var shiftInv, acc fr.Element
+	shiftInv.Inverse(&shift)
+	acc.Set(&domain.CardinalityInv)
+	for i := 0; i < m; i++ {
+		t[i].MulAssign(&acc)
+		acc.MulAssign(&shiftInv)
+	}
+
+	return t[:maxDegree(n)+1]
+}
+
+func randomElements(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func toPointers(s []fr.Element) []*fr.Element {
+	res := make([]*fr.Element, len(s))
+	for i := 0; i < len(s); i++ {
+		res[i] = &s[i]
+	}
+	return res
+}
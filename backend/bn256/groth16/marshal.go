@@ -0,0 +1,178 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark/internal/generators DO NOT EDIT
+
+package groth16
+
+import (
+	"io"
+
+	"github.com/consensys/gnark/backend"
+
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+
+	"github.com/consensys/gurvy"
+
+	curve "github.com/consensys/gurvy/bn256"
+)
+
+// WriteTo writes the binary encoding of the proof to w, with compressed points
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, true)
+}
+
+// WriteRawTo writes the binary encoding of the proof to w, with uncompressed points
+// (larger, but faster to read)
+func (proof *Proof) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, false)
+}
+
+func (proof *Proof) writeTo(w io.Writer, compressed bool) (int64, error) {
+	return encode(w, backend.BinaryProof, compressed, []interface{}{
+		&proof.Ar,
+		&proof.Krs,
+		&proof.Bs,
+	})
+}
+
+// ReadFrom reads the binary encoding of a proof from r, with compressed or uncompressed points
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	return decode(r, backend.BinaryProof, []interface{}{
+		&proof.Ar,
+		&proof.Krs,
+		&proof.Bs,
+	})
+}
+
+// WriteTo writes the binary encoding of the proving key to w, with compressed points
+func (pk *ProvingKey) WriteTo(w io.Writer) (int64, error) {
+	return pk.writeTo(w, true)
+}
+
+// WriteRawTo writes the binary encoding of the proving key to w, with uncompressed points
+// (larger, but faster to read)
+func (pk *ProvingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return pk.writeTo(w, false)
+}
+
+func (pk *ProvingKey) writeTo(w io.Writer, compressed bool) (int64, error) {
+	return encode(w, backend.BinaryProvingKey, compressed, []interface{}{
+		&pk.G1.Alpha,
+		&pk.G1.Beta,
+		&pk.G1.Delta,
+		pk.G1.A,
+		pk.G1.B,
+		pk.G1.Z,
+		pk.G1.K,
+		&pk.G2.Beta,
+		&pk.G2.Delta,
+		pk.G2.B,
+	})
+}
+
+// ReadFrom reads the binary encoding of a proving key from r, with compressed or uncompressed points
+func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	return decode(r, backend.BinaryProvingKey, []interface{}{
+		&pk.G1.Alpha,
+		&pk.G1.Beta,
+		&pk.G1.Delta,
+		&pk.G1.A,
+		&pk.G1.B,
+		&pk.G1.Z,
+		&pk.G1.K,
+		&pk.G2.Beta,
+		&pk.G2.Delta,
+		&pk.G2.B,
+	})
+}
+
+// WriteTo writes the binary encoding of the verifying key to w, with compressed points
+// e(α, β) is not written, it is computed by ReadFrom
+func (vk *VerifyingKey) WriteTo(w io.Writer) (int64, error) {
+	return vk.writeTo(w, true)
+}
+
+// WriteRawTo writes the binary encoding of the verifying key to w, with uncompressed points
+// (larger, but faster to read)
+func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return vk.writeTo(w, false)
+}
+
+func (vk *VerifyingKey) writeTo(w io.Writer, compressed bool) (int64, error) {
+	return encode(w, backend.BinaryVerifyingKey, compressed, []interface{}{
+		&vk.G2.Beta,
+		&vk.G2.GammaNeg,
+		&vk.G2.DeltaNeg,
+		&vk.G1.Alpha,
+		vk.G1.K,
+		vk.PublicInputs,
+	})
+}
+
+// ReadFrom reads the binary encoding of a verifying key from r, with compressed or uncompressed points
+func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	n, err := decode(r, backend.BinaryVerifyingKey, []interface{}{
+		&vk.G2.Beta,
+		&vk.G2.GammaNeg,
+		&vk.G2.DeltaNeg,
+		&vk.G1.Alpha,
+		&vk.G1.K,
+		&vk.PublicInputs,
+	})
+	if err != nil {
+		return n, err
+	}
+
+	// e(α, β)
+	c := curve.BN256()
+	vk.E = c.FinalExponentiation(c.MillerLoop(vk.G1.Alpha, vk.G2.Beta, &vk.E))
+
+	return n, nil
+}
+
+// encode writes the header of the object, followed by the encoding of the values
+func encode(w io.Writer, object backend.BinaryObject, compressed bool, values []interface{}) (int64, error) {
+	n, err := backend.WriteHeader(w, backend.BinaryHeader{
+		Version:    backend.BinaryVersion,
+		CurveID:    gurvy.BN256,
+		Object:     object,
+		Compressed: compressed,
+	})
+	if err != nil {
+		return n, err
+	}
+	enc := backend_bn256.NewEncoder(w, compressed)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return n + enc.BytesWritten(), err
+		}
+	}
+	return n + enc.BytesWritten(), nil
+}
+
+// decode reads and checks the header of the object, and decodes the values
+func decode(r io.Reader, object backend.BinaryObject, values []interface{}) (int64, error) {
+	header, n, err := backend.ReadHeader(r, gurvy.BN256, object)
+	if err != nil {
+		return n, err
+	}
+	dec := backend_bn256.NewDecoder(r, header.Compressed)
+	for _, v := range values {
+		if err := dec.Decode(v); err != nil {
+			return n + dec.BytesRead(), err
+		}
+	}
+	return n + dec.BytesRead(), nil
+}
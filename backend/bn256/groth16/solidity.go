@@ -0,0 +1,268 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"text/template"
+
+	curve "github.com/consensys/gurvy/bn256"
+	"github.com/consensys/gurvy/bn256/fp"
+	"github.com/consensys/gurvy/bn256/fr"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/consensys/gnark/backend"
+)
+
+var (
+	// ErrMissingAlphaBeta is returned when exporting a verifying key that was created before
+	// [α]1 and [β]2 were part of it. Running the Setup again fixes it.
+	ErrMissingAlphaBeta = errors.New("verifying key doesn't contain [α]1 and [β]2")
+)
+
+// ExportSolidity writes a Solidity smart contract which verifies proofs for this verifying key
+// on Ethereum, using the bn256 precompiled contracts (EIP-196 and EIP-197)
+//
+// the contract exposes verifyProof(a, b, c, input), see SolidityCalldata to encode a call to it
+func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
+	if vk.G1.Alpha.IsInfinity() || vk.G2.Beta.IsInfinity() {
+		return ErrMissingAlphaBeta
+	}
+	oneWire, inputs, err := solidityInputs(vk)
+	if err != nil {
+		return err
+	}
+
+	// the contract checks e(A, B)⋅e(C, -[δ]2)⋅e(Σx⋅[Kvk]1, -[γ]2)⋅e(-[α]1, [β]2) == 1
+	var alphaNeg curve.G1Affine
+	alphaNeg.Neg(&vk.G1.Alpha)
+
+	data := struct {
+		AlphaNeg           [2]string
+		Beta               [4]string
+		GammaNeg, DeltaNeg [4]string
+		K0                 [2]string
+		K                  [][2]string
+		Inputs             []string
+		Signature          string
+	}{
+		AlphaNeg:  g1Strings(&alphaNeg),
+		Beta:      g2Strings(&vk.G2.Beta),
+		GammaNeg:  g2Strings(&vk.G2.GammaNeg),
+		DeltaNeg:  g2Strings(&vk.G2.DeltaNeg),
+		K0:        g1Strings(&vk.G1.K[oneWire]),
+		Signature: soliditySignature(len(inputs)),
+	}
+	for _, i := range inputs {
+		data.K = append(data.K, g1Strings(&vk.G1.K[i]))
+		data.Inputs = append(data.Inputs, vk.PublicInputs[i])
+	}
+
+	tmpl, err := template.New("verifier").Funcs(template.FuncMap{"add": func(a, b int) int { return a + b }}).Parse(solidityTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
+
+// SolidityCalldata returns the ABI encoded call to verifyProof(a, b, c, input)
+// on the contract generated by vk.ExportSolidity
+//
+// the public inputs are ordered as in vk.PublicInputs, ONE_WIRE excepted
+func SolidityCalldata(proof *Proof, vk *VerifyingKey, inputs backend.Assignments) ([]byte, error) {
+	_, indexes, err := solidityInputs(vk)
+	if err != nil {
+		return nil, err
+	}
+
+	// ensure we don't use private inputs
+	publicInput := inputs.DiscardSecrets()
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(soliditySignature(len(indexes))))
+	calldata := h.Sum(nil)[:4]
+
+	// a, b, c
+	calldata = append(calldata, proof.Ar.X.Bytes()...)
+	calldata = append(calldata, proof.Ar.Y.Bytes()...)
+	for _, e := range g2Elements(&proof.Bs) {
+		calldata = append(calldata, e.Bytes()...)
+	}
+	calldata = append(calldata, proof.Krs.X.Bytes()...)
+	calldata = append(calldata, proof.Krs.Y.Bytes()...)
+
+	// input
+	for _, i := range indexes {
+		val, ok := publicInput[vk.PublicInputs[i]]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", backend.ErrInputNotSet, vk.PublicInputs[i])
+		}
+		var e fr.Element
+		e.SetBigInt(&val.Value)
+		calldata = append(calldata, e.Bytes()...)
+	}
+
+	return calldata, nil
+}
+
+// solidityInputs returns the index of ONE_WIRE in vk.PublicInputs and the indexes of the
+// public inputs passed to the contract
+func solidityInputs(vk *VerifyingKey) (int, []int, error) {
+	oneWire := -1
+	var inputs []int
+	for i, name := range vk.PublicInputs {
+		if name == backend.OneWire {
+			oneWire = i
+		} else {
+			inputs = append(inputs, i)
+		}
+	}
+	if oneWire == -1 || len(vk.G1.K) != len(vk.PublicInputs) {
+		return 0, nil, errors.New("invalid verifying key")
+	}
+	return oneWire, inputs, nil
+}
+
+// soliditySignature returns the signature of the verifyProof function of the contract
+func soliditySignature(nbInputs int) string {
+	if nbInputs == 0 {
+		return "verifyProof(uint256[2],uint256[2][2],uint256[2])"
+	}
+	return fmt.Sprintf("verifyProof(uint256[2],uint256[2][2],uint256[2],uint256[%d])", nbInputs)
+}
+
+// g2Elements returns the coordinates of p in the order expected by EIP-197: x.A1, x.A0, y.A1, y.A0
+func g2Elements(p *curve.G2Affine) [4]fp.Element {
+	return [4]fp.Element{p.X.A1, p.X.A0, p.Y.A1, p.Y.A0}
+}
+
+func g1Strings(p *curve.G1Affine) [2]string {
+	return [2]string{fpString(&p.X), fpString(&p.Y)}
+}
+
+func g2Strings(p *curve.G2Affine) [4]string {
+	var res [4]string
+	for i, e := range g2Elements(p) {
+		res[i] = fpString(&e)
+	}
+	return res
+}
+
+func fpString(e *fp.Element) string {
+	return new(big.Int).SetBytes(e.Bytes()).String()
+}
+
+const solidityTemplate = `// SPDX-License-Identifier: Apache-2.0
+// Code generated by gnark DO NOT EDIT
+
+pragma solidity ^0.6.0;
+
+// Verifier verifies Groth16 proofs on bn256
+contract Verifier {
+
+    // order of the scalar field
+    uint256 constant SNARK_SCALAR_FIELD = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
+
+    // -[α]1
+    uint256 constant ALPHA_NEG_X = {{index .AlphaNeg 0}};
+    uint256 constant ALPHA_NEG_Y = {{index .AlphaNeg 1}};
+
+    // [β]2
+    uint256 constant BETA_X_1 = {{index .Beta 0}};
+    uint256 constant BETA_X_0 = {{index .Beta 1}};
+    uint256 constant BETA_Y_1 = {{index .Beta 2}};
+    uint256 constant BETA_Y_0 = {{index .Beta 3}};
+
+    // -[γ]2
+    uint256 constant GAMMA_NEG_X_1 = {{index .GammaNeg 0}};
+    uint256 constant GAMMA_NEG_X_0 = {{index .GammaNeg 1}};
+    uint256 constant GAMMA_NEG_Y_1 = {{index .GammaNeg 2}};
+    uint256 constant GAMMA_NEG_Y_0 = {{index .GammaNeg 3}};
+
+    // -[δ]2
+    uint256 constant DELTA_NEG_X_1 = {{index .DeltaNeg 0}};
+    uint256 constant DELTA_NEG_X_0 = {{index .DeltaNeg 1}};
+    uint256 constant DELTA_NEG_Y_1 = {{index .DeltaNeg 2}};
+    uint256 constant DELTA_NEG_Y_0 = {{index .DeltaNeg 3}};
+
+    // [Kvk]1 of ONE_WIRE
+    uint256 constant K_0_X = {{index .K0 0}};
+    uint256 constant K_0_Y = {{index .K0 1}};
+{{- range $i, $k := .K}}
+
+    // [Kvk]1 of input[{{$i}}] ({{index $.Inputs $i}})
+    uint256 constant K_{{add $i 1}}_X = {{index $k 0}};
+    uint256 constant K_{{add $i 1}}_Y = {{index $k 1}};
+{{- end}}
+
+    // {{.Signature}}
+{{- if .Inputs}}
+    // public inputs: {{- range $i, $name := .Inputs}}{{if $i}},{{end}} input[{{$i}}] = {{$name}}{{- end}}
+{{- end}}
+    function verifyProof(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c
+        {{- if .Inputs}},
+        uint256[{{len .Inputs}}] memory input
+        {{- end}}
+    ) public view returns (bool) {
+
+        // Σx⋅[Kvk]1
+        uint256[2] memory x = [K_0_X, K_0_Y];
+{{- range $i, $k := .K}}
+        require(input[{{$i}}] < SNARK_SCALAR_FIELD, "input not in scalar field");
+        x = ecAdd(x, ecMul([K_{{add $i 1}}_X, K_{{add $i 1}}_Y], input[{{$i}}]));
+{{- end}}
+
+        // e(A, B)⋅e(C, -[δ]2)⋅e(Σx⋅[Kvk]1, -[γ]2)⋅e(-[α]1, [β]2) == 1
+        uint256[24] memory p = [
+            a[0], a[1], b[0][0], b[0][1], b[1][0], b[1][1],
+            c[0], c[1], DELTA_NEG_X_1, DELTA_NEG_X_0, DELTA_NEG_Y_1, DELTA_NEG_Y_0,
+            x[0], x[1], GAMMA_NEG_X_1, GAMMA_NEG_X_0, GAMMA_NEG_Y_1, GAMMA_NEG_Y_0,
+            ALPHA_NEG_X, ALPHA_NEG_Y, BETA_X_1, BETA_X_0, BETA_Y_1, BETA_Y_0
+        ];
+        uint256[1] memory out;
+        bool success;
+        assembly {
+            success := staticcall(gas(), 8, p, 0x300, out, 0x20)
+        }
+        return success && out[0] == 1;
+    }
+
+    // ecAdd returns p1 + p2 (EIP-196)
+    function ecAdd(uint256[2] memory p1, uint256[2] memory p2) internal view returns (uint256[2] memory r) {
+        uint256[4] memory p = [p1[0], p1[1], p2[0], p2[1]];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 6, p, 0x80, r, 0x40)
+        }
+        require(success, "ecAdd failed");
+    }
+
+    // ecMul returns s⋅p1 (EIP-196)
+    function ecMul(uint256[2] memory p1, uint256 s) internal view returns (uint256[2] memory r) {
+        uint256[3] memory p = [p1[0], p1[1], s];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 7, p, 0x60, r, 0x40)
+        }
+        require(success, "ecMul failed");
+    }
+}
+`
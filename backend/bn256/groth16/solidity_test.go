@@ -0,0 +1,226 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	curve "github.com/consensys/gurvy/bn256"
+	"github.com/consensys/gurvy/bn256/fp"
+	"github.com/consensys/gurvy/bn256/fr"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/consensys/gnark/backend"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+	"github.com/consensys/gnark/frontend"
+)
+
+func solidityCircuit() (backend_bn256.R1CS, backend.Assignments) {
+	circuit := frontend.New()
+
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.PUBLIC_INPUT("y")
+	z := circuit.PUBLIC_INPUT("z")
+	x3 := circuit.MUL(x, x, x)
+	circuit.MUSTBE_EQ(y, circuit.ADD(x3, x, 5))
+	circuit.MUSTBE_EQ(z, circuit.MUL(x, 2))
+
+	solution := backend.NewAssignment()
+	solution.Assign(backend.Secret, "x", 3)
+	solution.Assign(backend.Public, "y", 35)
+	solution.Assign(backend.Public, "z", 6)
+
+	return backend_bn256.Cast(circuit.ToR1CS()), solution
+}
+
+func TestExportSolidity(t *testing.T) {
+	r1cs, _ := solidityCircuit()
+	var pk ProvingKey
+	var vk VerifyingKey
+	Setup(&r1cs, &pk, &vk)
+
+	var buf bytes.Buffer
+	if err := vk.ExportSolidity(&buf); err != nil {
+		t.Fatal(err)
+	}
+	contract := buf.String()
+
+	if solc, err := exec.LookPath("solc"); err == nil {
+		dir, err := ioutil.TempDir("", "gnark")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "verifier.sol")
+		if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if out, err := exec.Command(solc, "--bin", path).CombinedOutput(); err != nil {
+			t.Fatal("solc failed to compile the contract", string(out))
+		}
+	}
+
+	// check the emitted constants against the key
+	var alphaNeg curve.G1Affine
+	alphaNeg.Neg(&vk.G1.Alpha)
+	expected := map[string]fp.Element{
+		"ALPHA_NEG_X":   alphaNeg.X,
+		"ALPHA_NEG_Y":   alphaNeg.Y,
+		"BETA_X_1":      vk.G2.Beta.X.A1,
+		"BETA_X_0":      vk.G2.Beta.X.A0,
+		"BETA_Y_1":      vk.G2.Beta.Y.A1,
+		"BETA_Y_0":      vk.G2.Beta.Y.A0,
+		"GAMMA_NEG_X_1": vk.G2.GammaNeg.X.A1,
+		"GAMMA_NEG_X_0": vk.G2.GammaNeg.X.A0,
+		"GAMMA_NEG_Y_1": vk.G2.GammaNeg.Y.A1,
+		"GAMMA_NEG_Y_0": vk.G2.GammaNeg.Y.A0,
+		"DELTA_NEG_X_1": vk.G2.DeltaNeg.X.A1,
+		"DELTA_NEG_X_0": vk.G2.DeltaNeg.X.A0,
+		"DELTA_NEG_Y_1": vk.G2.DeltaNeg.Y.A1,
+		"DELTA_NEG_Y_0": vk.G2.DeltaNeg.Y.A0,
+	}
+	// K_0 is ONE_WIRE, then the inputs in the order of vk.PublicInputs
+	k := 1
+	for i, name := range vk.PublicInputs {
+		prefix := "K_" + strconv.Itoa(k) + "_"
+		if name == backend.OneWire {
+			prefix = "K_0_"
+		} else {
+			k++
+		}
+		expected[prefix+"X"] = vk.G1.K[i].X
+		expected[prefix+"Y"] = vk.G1.K[i].Y
+	}
+
+	constants := regexp.MustCompile(`uint256 constant (\w+) = (\d+);`).FindAllStringSubmatch(contract, -1)
+	found := make(map[string]bool)
+	for _, c := range constants {
+		e, ok := expected[c[1]]
+		if !ok {
+			continue
+		}
+		if fpString(&e) != c[2] {
+			t.Fatal("wrong value for", c[1])
+		}
+		found[c[1]] = true
+	}
+	if len(found) != len(expected) {
+		t.Fatal("missing constants in the contract", len(found), len(expected))
+	}
+
+	if !regexp.MustCompile(`uint256\[2\] memory input`).MatchString(contract) {
+		t.Fatal("verifyProof should take 2 public inputs")
+	}
+
+	// a verifying key without [α]1, [β]2 can't be exported
+	vk.G1.Alpha = curve.G1Affine{}
+	if err := vk.ExportSolidity(&buf); err != ErrMissingAlphaBeta {
+		t.Fatal("expected ErrMissingAlphaBeta")
+	}
+}
+
+func TestSolidityCalldata(t *testing.T) {
+	r1cs, solution := solidityCircuit()
+	var pk ProvingKey
+	var vk VerifyingKey
+	Setup(&r1cs, &pk, &vk)
+	proof, err := Prove(&r1cs, &pk, solution)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calldata, err := SolidityCalldata(proof, &vk, solution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calldata) != 4+32*(2+4+2+2) {
+		t.Fatal("unexpected calldata length", len(calldata))
+	}
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte("verifyProof(uint256[2],uint256[2][2],uint256[2],uint256[2])"))
+	if !bytes.Equal(calldata[:4], h.Sum(nil)[:4]) {
+		t.Fatal("wrong function selector")
+	}
+
+	// decode the arguments and run the contract's check
+	word := func(i int) []byte { return calldata[4+32*i : 4+32*(i+1)] }
+	var a, c curve.G1Affine
+	var b curve.G2Affine
+	a.X.SetBytes(word(0))
+	a.Y.SetBytes(word(1))
+	b.X.A1.SetBytes(word(2))
+	b.X.A0.SetBytes(word(3))
+	b.Y.A1.SetBytes(word(4))
+	b.Y.A0.SetBytes(word(5))
+	c.X.SetBytes(word(6))
+	c.Y.SetBytes(word(7))
+
+	oneWire, inputs, err := solidityInputs(&vk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scalars := []fr.Element{fr.One()}
+	points := []curve.G1Affine{vk.G1.K[oneWire]}
+	for j, i := range inputs {
+		var s fr.Element
+		s.SetBytes(word(8 + j))
+		value := solution[vk.PublicInputs[i]].Value
+		if !s.Equal(new(fr.Element).SetBigInt(&value)) {
+			t.Fatal("wrong public input in calldata", vk.PublicInputs[i])
+		}
+		scalars = append(scalars, s)
+		points = append(points, vk.G1.K[i])
+	}
+	for i := range scalars {
+		scalars[i].FromMont()
+	}
+	var xJac curve.G1Jac
+	var x, alphaNeg curve.G1Affine
+	<-xJac.MultiExp(curve.BN256(), points, scalars)
+	xJac.ToAffineFromJac(&x)
+	alphaNeg.Neg(&vk.G1.Alpha)
+
+	check := func(a curve.G1Affine) bool {
+		var e1, e2, e3, e4, one curve.PairingResult
+		c1 := curve.BN256()
+		c1.MillerLoop(a, b, &e1)
+		c1.MillerLoop(c, vk.G2.DeltaNeg, &e2)
+		c1.MillerLoop(x, vk.G2.GammaNeg, &e3)
+		c1.MillerLoop(alphaNeg, vk.G2.Beta, &e4)
+		res := c1.FinalExponentiation(&e1, &e2, &e3, &e4)
+		one.SetOne()
+		return res.Equal(&one)
+	}
+	if !check(a) {
+		t.Fatal("the contract would reject a valid proof")
+	}
+	if check(c) {
+		t.Fatal("the contract would accept an invalid proof")
+	}
+
+	// missing public input
+	delete(solution, "z")
+	if _, err := SolidityCalldata(proof, &vk, solution); err == nil {
+		t.Fatal("expected ErrInputNotSet")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark/internal/generators DO NOT EDIT
+
+package plonk
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	curve "github.com/consensys/gurvy/bn256"
+	"github.com/consensys/gurvy/bn256/fr"
+
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/utils/parallel"
+)
+
+var root fr.Element
+
+func init() {
+	root.SetString(backend_bn256.RootOfUnityStr)
+}
+
+// interpolate sets p to the canonical form of the polynomial whose values on the domain are p
+func interpolate(p []fr.Element, domain *backend_bn256.Domain) {
+	backend_bn256.FFT(p, domain.GeneratorInv)
+	parallel.Execute(len(p), func(start, end int) {
+		for i := start; i < end; i++ {
+			p[i].MulAssign(&domain.CardinalityInv)
+		}
+	})
+}
+
+// evaluateOnCoset returns the values of p (canonical form) on shift⋅H, H being the domain
+// len(p) must be <= domain.Cardinality
+func evaluateOnCoset(p []fr.Element, domain *backend_bn256.Domain, shift fr.Element) []fr.Element {
+	res := make([]fr.Element, domain.Cardinality)
+	copy(res, p)
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(p); i++ {
+		res[i].MulAssign(&acc)
+		acc.MulAssign(&shift)
+	}
+	backend_bn256.FFT(res, domain.Generator)
+	return res
+}
+
+// evaluate returns p(x), p in canonical form
+func evaluate(p []fr.Element, x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x).Add(&res, &p[i])
+	}
+	return res
+}
+
+// divideByLinear returns (p(X) - p(z)) / (X - z), p in canonical form
+func divideByLinear(p []fr.Element, z fr.Element) []fr.Element {
+	if len(p) < 2 {
+		return []fr.Element{}
+	}
+	res := make([]fr.Element, len(p)-1)
+	res[len(res)-1].Set(&p[len(p)-1])
+	for i := len(res) - 2; i >= 0; i-- {
+		res[i].Mul(&res[i+1], &z).Add(&res[i], &p[i+1])
+	}
+	return res
+}
+
+// blind returns p(X) + b(X)⋅(Xⁿ - 1), p being in canonical form of degree < n
+func blind(p []fr.Element, n int, b ...fr.Element) []fr.Element {
+	res := make([]fr.Element, n+len(b))
+	copy(res, p)
+	for i := 0; i < len(b); i++ {
+		res[i].Sub(&res[i], &b[i])
+		res[n+i].Add(&res[n+i], &b[i])
+	}
+	return res
+}
+
+// commit returns [p(τ)]1, p in canonical form
+func commit(g1 []curve.G1Affine, p []fr.Element) curve.G1Affine {
+	scalars := make([]fr.Element, len(p))
+	for i := 0; i < len(p); i++ {
+		scalars[i] = p[i].ToRegular()
+	}
+	var res curve.G1Jac
+	var resAffine curve.G1Affine
+	<-res.MultiExp(curve.BN256(), g1[:len(p)], scalars)
+	res.ToAffineFromJac(&resAffine)
+	return resAffine
+}
+
+// parsePublicInput return the ordered public input values
+func parsePublicInput(expectedNames []string, input backend.Assignments) ([]fr.Element, error) {
+	toReturn := make([]fr.Element, len(expectedNames))
+
+	// ensure we don't assign private inputs
+	publicInput := input.DiscardSecrets()
+
+	for i := 0; i < len(expectedNames); i++ {
+		if expectedNames[i] == backend.OneWire {
+			// ONE_WIRE is a reserved name, it should not be set by the user
+			toReturn[i].SetOne()
+		} else {
+			if val, ok := publicInput[expectedNames[i]]; ok {
+				toReturn[i].SetBigInt(&val.Value)
+			} else {
+				return nil, backend.ErrInputNotSet
+			}
+		}
+	}
+
+	return toReturn, nil
+}
+
+// transcript derives the verifier challenges from the prover messages (Fiat-Shamir)
+type transcript struct {
+	h hash.Hash
+}
+
+func newTranscript() *transcript {
+	return &transcript{h: sha256.New()}
+}
+
+func (t *transcript) appendPoints(points ...*curve.G1Affine) {
+	for _, p := range points {
+		t.h.Write(p.X.Bytes())
+		t.h.Write(p.Y.Bytes())
+	}
+}
+
+func (t *transcript) appendScalars(scalars ...*fr.Element) {
+	for _, s := range scalars {
+		t.h.Write(s.Bytes())
+	}
+}
+
+// challenge returns a challenge derived from all the previous messages
+func (t *transcript) challenge() fr.Element {
+	digest := t.h.Sum(nil)
+	t.h.Reset()
+	t.h.Write(digest)
+
+	var res fr.Element
+	res.SetBytes(digest)
+	return res
+}
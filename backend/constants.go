@@ -14,11 +14,19 @@
 
 package backend
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
 
 // OneWire is the assignment label / name used for the constant wire one
 const OneWire = "ONE_WIRE"
 
+// NamespaceSeparator separates the names of the nested namespaces of a circuit, and the name of an input
+// or a tag from its namespace (eg "sender/pubkey_x", see frontend.CS.Namespace)
+const NamespaceSeparator = "/"
+
 // Visibility type alias on string to define circuit input's visibility
 type Visibility string
 
@@ -37,4 +45,29 @@ var (
 	ErrInputVisiblity        = errors.New("input has incorrect visibility (secret / public)")
 	ErrUnsatisfiedConstraint = errors.New("constraint is not satisfied")
 	ErrInvalidInputFormat    = errors.New("incorrect input format")
+	ErrInvalidType           = errors.New("invalid type")
+	ErrDuplicateAssignment   = errors.New("input already assigned")
 )
+
+// UnsatisfiedConstraintError is returned by R1CS.Solve when a constraint L * R == O does not hold.
+// It wraps ErrUnsatisfiedConstraint
+type UnsatisfiedConstraintError struct {
+	ConstraintID int     // index of the constraint in the R1CS
+	L, R, O      string  // the linear expressions, with wire names and tags
+	A, B, C      big.Int // the computed values of L, R and O
+	CallSite     string  // file:line of the frontend call which created the constraint, if known
+}
+
+func (e *UnsatisfiedConstraintError) Error() string {
+	res := fmt.Sprintf("%s: constraint %d: (%s) * (%s) == %s, with %s * %s != %s",
+		ErrUnsatisfiedConstraint, e.ConstraintID, e.L, e.R, e.O, e.A.String(), e.B.String(), e.C.String())
+	if e.CallSite != "" {
+		res += " at " + e.CallSite
+	}
+	return res
+}
+
+// Unwrap allows errors.Is(err, ErrUnsatisfiedConstraint)
+func (e *UnsatisfiedConstraintError) Unwrap() error {
+	return ErrUnsatisfiedConstraint
+}
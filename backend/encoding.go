@@ -0,0 +1,155 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gurvy"
+)
+
+/*
+	Binary format
+	-------------
+	The R1CS, keys and proofs of the curve specific backends implement io.WriterTo and io.ReaderFrom.
+	Unlike encoding/gob, the format doesn't depend on Go, it is described below.
+
+	All integers are big-endian. An object starts with a 10 bytes header:
+
+		magic      [4]byte  "gnrk"
+		version    uint16   BinaryVersion
+		curve ID   uint16   gurvy.ID (1: bls377, 2: bls381, 3: bn256)
+		object     uint8    BinaryObject (1: R1CS, 2: proving key, 3: verifying key, 4: proof)
+		flags      uint8    bit 0 is set if the points are compressed
+
+	followed by the fields of the object, in their declaration order:
+
+		integer    uint64
+		string     uint32 length, followed by the bytes
+		fr         the regular (not Montgomery) value, on 32 bytes
+		fp         the regular value, on 32 bytes (bn256) or 48 bytes (bls377, bls381)
+		G1         uncompressed: X | Y, compressed: X
+		G2         uncompressed: X.A1 | X.A0 | Y.A1 | Y.A0, compressed: X.A1 | X.A0
+		slice      uint32 length, followed by the elements
+
+	a R1C is its solving method (integer), followed by its hint identifier (integer) if it is solved by a hint
+	(see frontend.HINT), then by its linear expressions L, R and O, each term being the wire ID (integer) and
+	the coefficient (fr).
+
+	the 2 most significant bits of the first byte of a point are flags (the fp elements have enough spare bits):
+
+		00  uncompressed point
+		01  point at infinity (the remaining bits are 0)
+		10  compressed point, Y is the lexicographically smallest of ±Y
+		11  compressed point, Y is the lexicographically largest of ±Y
+
+	where Y is larger than -Y if Y > (p-1)/2 (in G2, Y.A1 is compared, or Y.A0 if Y.A1 is 0)
+
+	the decoders reject the points whose coordinates are not less than p, and the points which are not on
+	the curve (or the twist) or not in the subgroup of order r, such that each point has a single encoding.
+	They reject the integers which overflow an int, and allocate the slices as their elements are read,
+	such that a corrupted length fails with io.ErrUnexpectedEOF. The R1CS decoder checks the counts, the
+	solving methods and the wire IDs are consistent, such that the R1CS can be solved.
+
+	the decoders read the objects written with any version up to BinaryVersion:
+
+		1  initial format
+		2  the R1CS ends with the call sites of its constraints (slice of strings, see frontend.R1CS)
+		3  the R1Cs solved by a hint have a hint identifier (older versions have no such R1C)
+*/
+
+// BinaryVersion is the version of the binary format written by WriteTo methods
+const BinaryVersion uint16 = 3
+
+// BinaryObject identifies the type of the object following the header
+type BinaryObject uint8
+
+// objects which can be serialized
+const (
+	BinaryR1CS BinaryObject = iota + 1
+	BinaryProvingKey
+	BinaryVerifyingKey
+	BinaryProof
+)
+
+// BinaryHeader starts every object serialized in the binary format
+type BinaryHeader struct {
+	Version    uint16
+	CurveID    gurvy.ID
+	Object     BinaryObject
+	Compressed bool
+}
+
+// binaryHeaderSize is the size in bytes of an encoded BinaryHeader
+const binaryHeaderSize = 10
+
+var binaryMagic = [4]byte{'g', 'n', 'r', 'k'}
+
+var (
+	ErrInvalidMagic       = errors.New("invalid magic number, not a gnark binary object")
+	ErrUnsupportedVersion = errors.New("unsupported binary format version")
+	ErrCurveMismatch      = errors.New("binary object was serialized with another curve")
+	ErrObjectMismatch     = errors.New("binary object is not of the expected type")
+	ErrInvalidPoint       = errors.New("invalid point encoding")
+	ErrInvalidR1CS        = errors.New("invalid R1CS encoding")
+	ErrInvalidLength      = errors.New("invalid length")
+)
+
+// WriteHeader writes the header of an object serialized in the binary format
+func WriteHeader(w io.Writer, header BinaryHeader) (int64, error) {
+	var buf [binaryHeaderSize]byte
+	copy(buf[:4], binaryMagic[:])
+	binary.BigEndian.PutUint16(buf[4:6], header.Version)
+	binary.BigEndian.PutUint16(buf[6:8], uint16(header.CurveID))
+	buf[8] = byte(header.Object)
+	if header.Compressed {
+		buf[9] = 1
+	}
+	n, err := w.Write(buf[:])
+	return int64(n), err
+}
+
+// ReadHeader reads the header of an object serialized in the binary format, and checks it
+// matches the expected curve and object type, and that its version is supported
+func ReadHeader(r io.Reader, curveID gurvy.ID, object BinaryObject) (BinaryHeader, int64, error) {
+	var header BinaryHeader
+	var buf [binaryHeaderSize]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return header, int64(n), err
+	}
+	if buf[0] != binaryMagic[0] || buf[1] != binaryMagic[1] || buf[2] != binaryMagic[2] || buf[3] != binaryMagic[3] {
+		return header, int64(n), ErrInvalidMagic
+	}
+	header.Version = binary.BigEndian.Uint16(buf[4:6])
+	header.CurveID = gurvy.ID(binary.BigEndian.Uint16(buf[6:8]))
+	header.Object = BinaryObject(buf[8])
+	header.Compressed = buf[9]&1 == 1
+
+	if header.Version == 0 || header.Version > BinaryVersion {
+		return header, int64(n), fmt.Errorf("%w: %d", ErrUnsupportedVersion, header.Version)
+	}
+	if header.CurveID != curveID {
+		return header, int64(n), ErrCurveMismatch
+	}
+	if header.Object != object {
+		return header, int64(n), ErrObjectMismatch
+	}
+
+	return header, int64(n), nil
+}
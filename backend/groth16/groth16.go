@@ -0,0 +1,210 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package groth16 exposes the Groth16 backends of all the supported curves behind a single API,
+// dispatching to backend/<curve>/groth16 according to the curve of the keys and proofs:
+// a program can setup, prove and verify circuits on any curve without switching on gurvy.ID.
+package groth16
+
+import (
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark/backend"
+	backend_bls377 "github.com/consensys/gnark/backend/bls377"
+	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
+	backend_bls381 "github.com/consensys/gnark/backend/bls381"
+	groth16_bls381 "github.com/consensys/gnark/backend/bls381/groth16"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+var (
+	ErrUnknownCurve  = errors.New("unknown curve id")
+	ErrCurveMismatch = errors.New("the proof and the key are on different curves")
+)
+
+// ProvingKey Groth16 proving key of one of the supported curves
+// (*groth16_bls377.ProvingKey, *groth16_bls381.ProvingKey or *groth16_bn256.ProvingKey)
+type ProvingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// VerifyingKey Groth16 verifying key of one of the supported curves
+// (*groth16_bls377.VerifyingKey, *groth16_bls381.VerifyingKey or *groth16_bn256.VerifyingKey)
+type VerifyingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// Proof Groth16 proof of one of the supported curves
+// (*groth16_bls377.Proof, *groth16_bls381.Proof or *groth16_bn256.Proof)
+type Proof interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// Setup runs the setup of the circuit on the curve curveID, and returns the proving and verifying keys
+func Setup(r1cs *frontend.R1CS, curveID gurvy.ID) (ProvingKey, VerifyingKey, error) {
+	switch curveID {
+	case gurvy.BLS377:
+		var pk groth16_bls377.ProvingKey
+		var vk groth16_bls377.VerifyingKey
+		r := backend_bls377.Cast(r1cs)
+		groth16_bls377.Setup(&r, &pk, &vk)
+		return &pk, &vk, nil
+	case gurvy.BLS381:
+		var pk groth16_bls381.ProvingKey
+		var vk groth16_bls381.VerifyingKey
+		r := backend_bls381.Cast(r1cs)
+		groth16_bls381.Setup(&r, &pk, &vk)
+		return &pk, &vk, nil
+	case gurvy.BN256:
+		var pk groth16_bn256.ProvingKey
+		var vk groth16_bn256.VerifyingKey
+		r := backend_bn256.Cast(r1cs)
+		groth16_bn256.Setup(&r, &pk, &vk)
+		return &pk, &vk, nil
+	default:
+		return nil, nil, ErrUnknownCurve
+	}
+}
+
+// Prove computes a proof of the solution of the circuit, on the curve of the proving key
+func Prove(r1cs *frontend.R1CS, pk ProvingKey, solution backend.Assignments) (Proof, error) {
+	prove, err := NewProver(r1cs, pk)
+	if err != nil {
+		return nil, err
+	}
+	return prove(solution)
+}
+
+// Prover computes a proof of a solution of a circuit, the proof is nil if an error is returned
+type Prover func(solution backend.Assignments) (Proof, error)
+
+// NewProver returns a Prover of the circuit on the curve of the proving key: unlike Prove, the R1CS
+// is converted to the curve once, for all the proofs
+func NewProver(r1cs *frontend.R1CS, pk ProvingKey) (Prover, error) {
+	switch pk := pk.(type) {
+	case *groth16_bls377.ProvingKey:
+		r := backend_bls377.Cast(r1cs)
+		return func(solution backend.Assignments) (Proof, error) {
+			proof, err := groth16_bls377.Prove(&r, pk, solution)
+			if err != nil {
+				return nil, err
+			}
+			return proof, nil
+		}, nil
+	case *groth16_bls381.ProvingKey:
+		r := backend_bls381.Cast(r1cs)
+		return func(solution backend.Assignments) (Proof, error) {
+			proof, err := groth16_bls381.Prove(&r, pk, solution)
+			if err != nil {
+				return nil, err
+			}
+			return proof, nil
+		}, nil
+	case *groth16_bn256.ProvingKey:
+		r := backend_bn256.Cast(r1cs)
+		return func(solution backend.Assignments) (Proof, error) {
+			proof, err := groth16_bn256.Prove(&r, pk, solution)
+			if err != nil {
+				return nil, err
+			}
+			return proof, nil
+		}, nil
+	default:
+		return nil, ErrUnknownCurve
+	}
+}
+
+// Verify verifies a proof with the public inputs, the proof and the verifying key must be on the same curve
+func Verify(proof Proof, vk VerifyingKey, inputs backend.Assignments) (bool, error) {
+	switch vk := vk.(type) {
+	case *groth16_bls377.VerifyingKey:
+		if proof, ok := proof.(*groth16_bls377.Proof); ok {
+			return groth16_bls377.Verify(proof, vk, inputs)
+		}
+	case *groth16_bls381.VerifyingKey:
+		if proof, ok := proof.(*groth16_bls381.Proof); ok {
+			return groth16_bls381.Verify(proof, vk, inputs)
+		}
+	case *groth16_bn256.VerifyingKey:
+		if proof, ok := proof.(*groth16_bn256.Proof); ok {
+			return groth16_bn256.Verify(proof, vk, inputs)
+		}
+	default:
+		return false, ErrUnknownCurve
+	}
+	return false, ErrCurveMismatch
+}
+
+// NewProvingKey returns an empty proving key on the curve curveID, to be deserialized (ReadFrom, encoding/gob)
+func NewProvingKey(curveID gurvy.ID) (ProvingKey, error) {
+	switch curveID {
+	case gurvy.BLS377:
+		return &groth16_bls377.ProvingKey{}, nil
+	case gurvy.BLS381:
+		return &groth16_bls381.ProvingKey{}, nil
+	case gurvy.BN256:
+		return &groth16_bn256.ProvingKey{}, nil
+	default:
+		return nil, ErrUnknownCurve
+	}
+}
+
+// NewVerifyingKey returns an empty verifying key on the curve curveID, to be deserialized (ReadFrom, encoding/gob)
+func NewVerifyingKey(curveID gurvy.ID) (VerifyingKey, error) {
+	switch curveID {
+	case gurvy.BLS377:
+		return &groth16_bls377.VerifyingKey{}, nil
+	case gurvy.BLS381:
+		return &groth16_bls381.VerifyingKey{}, nil
+	case gurvy.BN256:
+		return &groth16_bn256.VerifyingKey{}, nil
+	default:
+		return nil, ErrUnknownCurve
+	}
+}
+
+// NewProof returns an empty proof on the curve curveID, to be deserialized (ReadFrom, encoding/gob)
+func NewProof(curveID gurvy.ID) (Proof, error) {
+	switch curveID {
+	case gurvy.BLS377:
+		return &groth16_bls377.Proof{}, nil
+	case gurvy.BLS381:
+		return &groth16_bls381.Proof{}, nil
+	case gurvy.BN256:
+		return &groth16_bn256.Proof{}, nil
+	default:
+		return nil, ErrUnknownCurve
+	}
+}
+
+// CurveID returns the curve of a proving key, a verifying key or a proof (gurvy.UNKNOWN if it isn't one of them)
+func CurveID(object interface{}) gurvy.ID {
+	switch object.(type) {
+	case *groth16_bls377.ProvingKey, *groth16_bls377.VerifyingKey, *groth16_bls377.Proof:
+		return gurvy.BLS377
+	case *groth16_bls381.ProvingKey, *groth16_bls381.VerifyingKey, *groth16_bls381.Proof:
+		return gurvy.BLS381
+	case *groth16_bn256.ProvingKey, *groth16_bn256.VerifyingKey, *groth16_bn256.Proof:
+		return gurvy.BN256
+	default:
+		return gurvy.UNKNOWN
+	}
+}
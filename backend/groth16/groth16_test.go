@@ -0,0 +1,116 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/generators/testcircuits/circuits"
+	"github.com/consensys/gurvy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupProveVerify(t *testing.T) {
+	assert := require.New(t)
+	circuit := circuits.Circuits["reference_small"]
+
+	for _, curveID := range []gurvy.ID{gurvy.BLS377, gurvy.BLS381, gurvy.BN256} {
+		pk, vk, err := Setup(circuit.R1CS, curveID)
+		assert.NoError(err, curveID.String())
+		assert.Equal(curveID, CurveID(pk))
+		assert.Equal(curveID, CurveID(vk))
+
+		proof, err := Prove(circuit.R1CS, pk, circuit.Good)
+		assert.NoError(err, curveID.String())
+		assert.Equal(curveID, CurveID(proof))
+
+		// the objects of the facade are deserialized in empty objects of the same curve
+		var buf bytes.Buffer
+		_, err = proof.WriteTo(&buf)
+		assert.NoError(err)
+		readProof, err := NewProof(curveID)
+		assert.NoError(err)
+		_, err = readProof.ReadFrom(&buf)
+		assert.NoError(err)
+
+		buf.Reset()
+		_, err = vk.WriteTo(&buf)
+		assert.NoError(err)
+		readVk, err := NewVerifyingKey(curveID)
+		assert.NoError(err)
+		_, err = readVk.ReadFrom(&buf)
+		assert.NoError(err)
+
+		ok, err := Verify(readProof, readVk, circuit.Good.DiscardSecrets())
+		assert.NoError(err, curveID.String())
+		assert.True(ok, curveID.String())
+
+		ok, err = Verify(proof, vk, circuit.Bad.DiscardSecrets())
+		assert.NoError(err, curveID.String())
+		assert.False(ok, curveID.String())
+
+		proof, err = Prove(circuit.R1CS, pk, circuit.Bad)
+		assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint), curveID.String())
+		assert.True(proof == nil, "the proof must be an untyped nil on error")
+
+		// a Prover converts the R1CS once
+		prove, err := NewProver(circuit.R1CS, pk)
+		assert.NoError(err)
+		proof, err = prove(circuit.Good)
+		assert.NoError(err)
+		ok, err = Verify(proof, vk, circuit.Good.DiscardSecrets())
+		assert.NoError(err)
+		assert.True(ok, curveID.String())
+	}
+}
+
+func TestCurveMismatch(t *testing.T) {
+	assert := require.New(t)
+	circuit := circuits.Circuits["reference_small"]
+
+	pk, _, err := Setup(circuit.R1CS, gurvy.BN256)
+	assert.NoError(err)
+	proof, err := Prove(circuit.R1CS, pk, circuit.Good)
+	assert.NoError(err)
+	_, vk, err := Setup(circuit.R1CS, gurvy.BLS381)
+	assert.NoError(err)
+
+	_, err = Verify(proof, vk, circuit.Good.DiscardSecrets())
+	assert.Equal(ErrCurveMismatch, err)
+}
+
+func TestUnknownCurve(t *testing.T) {
+	assert := require.New(t)
+	circuit := circuits.Circuits["reference_small"]
+
+	_, _, err := Setup(circuit.R1CS, gurvy.UNKNOWN)
+	assert.Equal(ErrUnknownCurve, err)
+	_, err = Prove(circuit.R1CS, nil, circuit.Good)
+	assert.Equal(ErrUnknownCurve, err)
+	_, err = NewProver(circuit.R1CS, nil)
+	assert.Equal(ErrUnknownCurve, err)
+	_, err = Verify(nil, nil, circuit.Good)
+	assert.Equal(ErrUnknownCurve, err)
+	_, err = NewProvingKey(gurvy.UNKNOWN)
+	assert.Equal(ErrUnknownCurve, err)
+	_, err = NewVerifyingKey(gurvy.UNKNOWN)
+	assert.Equal(ErrUnknownCurve, err)
+	_, err = NewProof(gurvy.UNKNOWN)
+	assert.Equal(ErrUnknownCurve, err)
+	assert.Equal(gurvy.UNKNOWN, CurveID(circuit.R1CS))
+}
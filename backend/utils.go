@@ -15,28 +15,40 @@
 package backend
 
 import (
+	"fmt"
 	"math/big"
-	"strconv"
 
+	fp_bls377 "github.com/consensys/gurvy/bls377/fp"
 	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fp_bls381 "github.com/consensys/gurvy/bls381/fp"
 	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fp_bn256 "github.com/consensys/gurvy/bn256/fp"
 	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
 )
 
-// FromInterface converts an interface to a big.Int element
+// FromInterface converts an interface to a big.Int element, it panics if ParseInterface fails
 func FromInterface(i1 interface{}) big.Int {
+	val, err := ParseInterface(i1)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ParseInterface converts an interface to a big.Int element
+// supported types are integers (uint64, int), base 10 strings, big.Int, fr and fp elements and
+// big endian []byte; other types yield ErrInvalidType
+func ParseInterface(i1 interface{}) (big.Int, error) {
 	var val big.Int
 
 	switch c1 := i1.(type) {
 	case uint64:
 		val.SetUint64(c1)
 	case int:
-		if _, ok := val.SetString(strconv.Itoa(c1), 10); !ok {
-			panic("unable to set big.Int from base10 string")
-		}
+		val.SetInt64(int64(c1))
 	case string:
 		if _, ok := val.SetString(c1, 10); !ok {
-			panic("unable to set big.Int from base10 string")
+			return val, fmt.Errorf("%w: %q is not a base 10 integer", ErrInvalidType, c1)
 		}
 	case big.Int:
 		val = c1
@@ -54,11 +66,23 @@ func FromInterface(i1 interface{}) big.Int {
 		c1.ToBigIntRegular(&val)
 	case *fr_bls377.Element:
 		c1.ToBigIntRegular(&val)
+	case fp_bn256.Element: // base fields, for circuits manipulating the coordinates of points
+		c1.ToBigIntRegular(&val)
+	case *fp_bn256.Element:
+		c1.ToBigIntRegular(&val)
+	case fp_bls381.Element:
+		c1.ToBigIntRegular(&val)
+	case *fp_bls381.Element:
+		c1.ToBigIntRegular(&val)
+	case fp_bls377.Element:
+		c1.ToBigIntRegular(&val)
+	case *fp_bls377.Element:
+		c1.ToBigIntRegular(&val)
 	case []byte:
 		val.SetBytes(c1)
 	default:
-		panic("invalid type")
+		return val, fmt.Errorf("%w: %T", ErrInvalidType, i1)
 	}
 
-	return val
+	return val, nil
 }
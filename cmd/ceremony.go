@@ -0,0 +1,454 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	backend_bls377 "github.com/consensys/gnark/backend/bls377"
+	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
+	backend_bls381 "github.com/consensys/gnark/backend/bls381"
+	groth16_bls381 "github.com/consensys/gnark/backend/bls381/groth16"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	"github.com/consensys/gnark/encoding/gob"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+	"github.com/spf13/cobra"
+)
+
+// ceremonyCmd represents the ceremony command
+//
+// a groth16 setup ran by a single party is fine for testing purposes only: whoever knows the toxic waste
+// can forge proofs. The ceremony commands run a multi-party computation producing the proving and
+// verifying keys, which are secure as long as one of the contributors is honest
+var ceremonyCmd = &cobra.Command{
+	Use:   "ceremony",
+	Short: "runs a multi-party computation ceremony producing groth16 proving and verifying keys",
+	Long: `runs a multi-party computation ceremony producing groth16 proving and verifying keys
+
+phase 1 (powers of tau) doesn't depend on the circuit:
+	gnark ceremony init --curve bn256 --size 1024 --output phase1.ceremony
+	gnark ceremony contribute phase1.ceremony --output phase1.1.ceremony
+	gnark ceremony verify phase1.ceremony phase1.1.ceremony
+
+phase 2 is specific to a circuit:
+	gnark ceremony init circuit.r1cs --phase1 phase1.1.ceremony --output phase2.ceremony
+	gnark ceremony contribute phase2.ceremony --phase 2 --output phase2.1.ceremony
+	gnark ceremony verify phase2.ceremony phase2.1.ceremony --phase 2
+	gnark ceremony finalize phase2.1.ceremony --pk circuit.pk --vk circuit.vk`,
+	Version: Version,
+}
+
+var ceremonyInitCmd = &cobra.Command{
+	Use:     "init [circuit.r1cs]",
+	Short:   "outputs the initial transcript of phase 1, or of phase 2 if a circuit is given",
+	Run:     cmdCeremonyInit,
+	Version: Version,
+}
+
+var ceremonyContributeCmd = &cobra.Command{
+	Use:     "contribute [transcript]",
+	Short:   "outputs a transcript updated with a random contribution",
+	Run:     cmdCeremonyContribute,
+	Version: Version,
+}
+
+var ceremonyVerifyCmd = &cobra.Command{
+	Use:     "verify [previous transcript] [next transcript]",
+	Short:   "verifies that the next transcript is a valid contribution to the previous one",
+	Run:     cmdCeremonyVerify,
+	Version: Version,
+}
+
+var ceremonyFinalizeCmd = &cobra.Command{
+	Use:     "finalize [phase 2 transcript]",
+	Short:   "outputs proving and verifying keys from the transcript of phase 2",
+	Run:     cmdCeremonyFinalize,
+	Version: Version,
+}
+
+var (
+	fCeremonyCurve, fCeremonyOutput, fCeremonyPhase1 string
+	fCeremonySize, fCeremonyPhase                    int
+)
+
+func init() {
+	rootCmd.AddCommand(ceremonyCmd)
+	ceremonyCmd.AddCommand(ceremonyInitCmd, ceremonyContributeCmd, ceremonyVerifyCmd, ceremonyFinalizeCmd)
+
+	ceremonyInitCmd.PersistentFlags().StringVar(&fCeremonyCurve, "curve", "bn256", "phase 1 curve -- bls377, bls381 or bn256")
+	ceremonyInitCmd.PersistentFlags().IntVar(&fCeremonySize, "size", 1<<10, "phase 1 maximum number of constraints -- must be a power of 2")
+	ceremonyInitCmd.PersistentFlags().StringVar(&fCeremonyPhase1, "phase1", "", "phase 2 only, specifies the path to the final transcript of phase 1")
+	ceremonyInitCmd.PersistentFlags().StringVar(&fCeremonyOutput, "output", "", "specifies full path for the transcript -- default is ./phase1.ceremony or ./[circuit].ceremony")
+
+	ceremonyContributeCmd.PersistentFlags().IntVar(&fCeremonyPhase, "phase", 1, "phase of the transcript -- 1 or 2")
+	ceremonyContributeCmd.PersistentFlags().StringVar(&fCeremonyOutput, "output", "", "specifies full path for the updated transcript -- default overwrites the transcript")
+
+	ceremonyVerifyCmd.PersistentFlags().IntVar(&fCeremonyPhase, "phase", 1, "phase of the transcripts -- 1 or 2")
+
+	ceremonyFinalizeCmd.PersistentFlags().StringVar(&fVkPath, "vk", "", "specifies full path for verifying key -- default is ./[transcript].vk")
+	ceremonyFinalizeCmd.PersistentFlags().StringVar(&fPkPath, "pk", "", "specifies full path for proving key   -- default is ./[transcript].pk")
+}
+
+func cmdCeremonyInit(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		ceremonyInitPhase1()
+		return
+	}
+
+	// phase 2
+	circuitPath := filepath.Clean(args[0])
+	if !fileExists(circuitPath) {
+		fmt.Println(circuitPath, errNotFound)
+		os.Exit(-1)
+	}
+	if fCeremonyPhase1 == "" || !fileExists(fCeremonyPhase1) {
+		fmt.Println("missing phase 1 transcript -- gnark ceremony init -h for help")
+		os.Exit(-1)
+	}
+	outputPath := filepath.Join(".", trimExt(circuitPath)+".ceremony")
+	if fCeremonyOutput != "" {
+		outputPath = fCeremonyOutput
+	}
+
+	curveID, err := gob.PeekCurveID(fCeremonyPhase1)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	circuitID, err := gob.PeekCurveID(circuitPath)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	var bigIntR1cs frontend.R1CS
+	if err := gob.Read(circuitPath, &bigIntR1cs, circuitID); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+
+	start := time.Now()
+	switch curveID {
+	case gurvy.BLS377:
+		r1cs := backend_bls377.Cast(&bigIntR1cs)
+		var phase1 groth16_bls377.Phase1
+		if err := gob.Read(fCeremonyPhase1, &phase1, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		phase2, err := groth16_bls377.NewPhase2(&r1cs, &phase1)
+		if err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		writeTranscript(outputPath, phase2, curveID)
+	case gurvy.BLS381:
+		r1cs := backend_bls381.Cast(&bigIntR1cs)
+		var phase1 groth16_bls381.Phase1
+		if err := gob.Read(fCeremonyPhase1, &phase1, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		phase2, err := groth16_bls381.NewPhase2(&r1cs, &phase1)
+		if err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		writeTranscript(outputPath, phase2, curveID)
+	case gurvy.BN256:
+		r1cs := backend_bn256.Cast(&bigIntR1cs)
+		var phase1 groth16_bn256.Phase1
+		if err := gob.Read(fCeremonyPhase1, &phase1, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		phase2, err := groth16_bn256.NewPhase2(&r1cs, &phase1)
+		if err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		writeTranscript(outputPath, phase2, curveID)
+	default:
+		fmt.Println("error:", errUnknownCurve)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-30s\n", "phase 2 initialized", "", time.Since(start))
+}
+
+func ceremonyInitPhase1() {
+	var curveID gurvy.ID
+	for _, id := range []gurvy.ID{gurvy.BLS377, gurvy.BLS381, gurvy.BN256} {
+		if strings.ToLower(fCeremonyCurve) == id.String() {
+			curveID = id
+		}
+	}
+	outputPath := filepath.Join(".", "phase1.ceremony")
+	if fCeremonyOutput != "" {
+		outputPath = fCeremonyOutput
+	}
+
+	start := time.Now()
+	switch curveID {
+	case gurvy.BLS377:
+		phase1, err := groth16_bls377.NewPhase1(fCeremonySize)
+		if err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		writeTranscript(outputPath, phase1, curveID)
+	case gurvy.BLS381:
+		phase1, err := groth16_bls381.NewPhase1(fCeremonySize)
+		if err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		writeTranscript(outputPath, phase1, curveID)
+	case gurvy.BN256:
+		phase1, err := groth16_bn256.NewPhase1(fCeremonySize)
+		if err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		writeTranscript(outputPath, phase1, curveID)
+	default:
+		fmt.Println("error:", errUnknownCurve)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-30s\n", "phase 1 initialized", "", time.Since(start))
+}
+
+func cmdCeremonyContribute(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		fmt.Println("missing transcript path -- gnark ceremony contribute -h for help")
+		os.Exit(-1)
+	}
+	if fCeremonyPhase != 1 && fCeremonyPhase != 2 {
+		fmt.Println("error: --phase must be 1 or 2")
+		os.Exit(-1)
+	}
+	transcriptPath := filepath.Clean(args[0])
+	outputPath := transcriptPath
+	if fCeremonyOutput != "" {
+		outputPath = fCeremonyOutput
+	}
+	curveID := peekTranscript(transcriptPath)
+
+	start := time.Now()
+	switch curveID {
+	case gurvy.BLS377:
+		if fCeremonyPhase == 1 {
+			var prev groth16_bls377.Phase1
+			readTranscript(transcriptPath, &prev, curveID)
+			writeTranscript(outputPath, prev.Contribute(), curveID)
+		} else {
+			var prev groth16_bls377.Phase2
+			readTranscript(transcriptPath, &prev, curveID)
+			writeTranscript(outputPath, prev.Contribute(), curveID)
+		}
+	case gurvy.BLS381:
+		if fCeremonyPhase == 1 {
+			var prev groth16_bls381.Phase1
+			readTranscript(transcriptPath, &prev, curveID)
+			writeTranscript(outputPath, prev.Contribute(), curveID)
+		} else {
+			var prev groth16_bls381.Phase2
+			readTranscript(transcriptPath, &prev, curveID)
+			writeTranscript(outputPath, prev.Contribute(), curveID)
+		}
+	case gurvy.BN256:
+		if fCeremonyPhase == 1 {
+			var prev groth16_bn256.Phase1
+			readTranscript(transcriptPath, &prev, curveID)
+			writeTranscript(outputPath, prev.Contribute(), curveID)
+		} else {
+			var prev groth16_bn256.Phase2
+			readTranscript(transcriptPath, &prev, curveID)
+			writeTranscript(outputPath, prev.Contribute(), curveID)
+		}
+	default:
+		fmt.Println("error:", errUnknownCurve)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-30s\n", "contribution completed", "", time.Since(start))
+}
+
+func cmdCeremonyVerify(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		fmt.Println("missing transcript paths -- gnark ceremony verify -h for help")
+		os.Exit(-1)
+	}
+	if fCeremonyPhase != 1 && fCeremonyPhase != 2 {
+		fmt.Println("error: --phase must be 1 or 2")
+		os.Exit(-1)
+	}
+	prevPath, nextPath := filepath.Clean(args[0]), filepath.Clean(args[1])
+	curveID := peekTranscript(prevPath)
+	if id := peekTranscript(nextPath); id != curveID {
+		fmt.Println("error: transcripts are defined over different curves")
+		os.Exit(-1)
+	}
+
+	start := time.Now()
+	var err error
+	switch curveID {
+	case gurvy.BLS377:
+		if fCeremonyPhase == 1 {
+			var prev, next groth16_bls377.Phase1
+			readTranscript(prevPath, &prev, curveID)
+			readTranscript(nextPath, &next, curveID)
+			err = groth16_bls377.VerifyPhase1(&prev, &next)
+		} else {
+			var prev, next groth16_bls377.Phase2
+			readTranscript(prevPath, &prev, curveID)
+			readTranscript(nextPath, &next, curveID)
+			err = groth16_bls377.VerifyPhase2(&prev, &next)
+		}
+	case gurvy.BLS381:
+		if fCeremonyPhase == 1 {
+			var prev, next groth16_bls381.Phase1
+			readTranscript(prevPath, &prev, curveID)
+			readTranscript(nextPath, &next, curveID)
+			err = groth16_bls381.VerifyPhase1(&prev, &next)
+		} else {
+			var prev, next groth16_bls381.Phase2
+			readTranscript(prevPath, &prev, curveID)
+			readTranscript(nextPath, &next, curveID)
+			err = groth16_bls381.VerifyPhase2(&prev, &next)
+		}
+	case gurvy.BN256:
+		if fCeremonyPhase == 1 {
+			var prev, next groth16_bn256.Phase1
+			readTranscript(prevPath, &prev, curveID)
+			readTranscript(nextPath, &next, curveID)
+			err = groth16_bn256.VerifyPhase1(&prev, &next)
+		} else {
+			var prev, next groth16_bn256.Phase2
+			readTranscript(prevPath, &prev, curveID)
+			readTranscript(nextPath, &next, curveID)
+			err = groth16_bn256.VerifyPhase2(&prev, &next)
+		}
+	default:
+		fmt.Println("error:", errUnknownCurve)
+		os.Exit(-1)
+	}
+	duration := time.Since(start)
+	if err != nil {
+		fmt.Printf("%-30s %-30s %-30s\n", "contribution is invalid", "", duration)
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-30s\n", "contribution is valid", "", duration)
+}
+
+func cmdCeremonyFinalize(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		fmt.Println("missing transcript path -- gnark ceremony finalize -h for help")
+		os.Exit(-1)
+	}
+	transcriptPath := filepath.Clean(args[0])
+	name := trimExt(transcriptPath)
+
+	vkPath := filepath.Join(".", name+".vk")
+	pkPath := filepath.Join(".", name+".pk")
+	if fVkPath != "" {
+		vkPath = fVkPath
+	}
+	if fPkPath != "" {
+		pkPath = fPkPath
+	}
+	curveID := peekTranscript(transcriptPath)
+
+	switch curveID {
+	case gurvy.BLS377:
+		var phase2 groth16_bls377.Phase2
+		readTranscript(transcriptPath, &phase2, curveID)
+		var pk groth16_bls377.ProvingKey
+		var vk groth16_bls377.VerifyingKey
+		groth16_bls377.Finalize(&phase2, &pk, &vk)
+		writeKeys(pkPath, vkPath, &pk, &vk, curveID)
+	case gurvy.BLS381:
+		var phase2 groth16_bls381.Phase2
+		readTranscript(transcriptPath, &phase2, curveID)
+		var pk groth16_bls381.ProvingKey
+		var vk groth16_bls381.VerifyingKey
+		groth16_bls381.Finalize(&phase2, &pk, &vk)
+		writeKeys(pkPath, vkPath, &pk, &vk, curveID)
+	case gurvy.BN256:
+		var phase2 groth16_bn256.Phase2
+		readTranscript(transcriptPath, &phase2, curveID)
+		var pk groth16_bn256.ProvingKey
+		var vk groth16_bn256.VerifyingKey
+		groth16_bn256.Finalize(&phase2, &pk, &vk)
+		writeKeys(pkPath, vkPath, &pk, &vk, curveID)
+	default:
+		fmt.Println("error:", errUnknownCurve)
+		os.Exit(-1)
+	}
+}
+
+func peekTranscript(path string) gurvy.ID {
+	if !fileExists(path) {
+		fmt.Println(path, errNotFound)
+		os.Exit(-1)
+	}
+	curveID, err := gob.PeekCurveID(path)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	return curveID
+}
+
+func readTranscript(path string, into interface{}, curveID gurvy.ID) {
+	if err := gob.Read(path, into, curveID); err != nil {
+		fmt.Println("can't load transcript", path)
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+}
+
+func writeTranscript(path string, transcript interface{}, curveID gurvy.ID) {
+	if err := gob.Write(path, transcript, curveID); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %s\n", "generated transcript", path)
+}
+
+func writeKeys(pkPath, vkPath string, pk, vk interface{}, curveID gurvy.ID) {
+	if err := gob.Write(vkPath, vk, curveID); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %s\n", "generated verifying key", vkPath)
+	if err := gob.Write(pkPath, pk, curveID); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %s\n", "generated proving key", pkPath)
+}
+
+// trimExt returns the base name of path, without extension
+func trimExt(path string) string {
+	name := filepath.Base(path)
+	return name[0 : len(name)-len(filepath.Ext(name))]
+}
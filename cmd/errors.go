@@ -19,6 +19,9 @@ package cmd
 import "errors"
 
 var (
-	errNotFound     = errors.New("file not found")
-	errUnknownCurve = errors.New("unknown curve id")
+	errNotFound      = errors.New("file not found")
+	errUnknownCurve  = errors.New("unknown curve id")
+	errUnknownScheme = errors.New("unknown proving scheme")
+
+	errUnsupportedCurve = errors.New("curve not supported by this command")
 )
@@ -0,0 +1,97 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	"github.com/consensys/gnark/encoding/gob"
+	"github.com/consensys/gurvy"
+	"github.com/spf13/cobra"
+)
+
+// exportSolidityCmd represents the export-solidity command
+var exportSolidityCmd = &cobra.Command{
+	Use:     "export-solidity [circuit.vk]",
+	Short:   "outputs a Solidity smart contract verifying proofs for a given (bn256, groth16) verifying key",
+	Run:     cmdExportSolidity,
+	Version: Version,
+}
+
+var (
+	fSolidityPath string
+)
+
+func init() {
+	rootCmd.AddCommand(exportSolidityCmd)
+	exportSolidityCmd.PersistentFlags().StringVar(&fSolidityPath, "output", "", "specifies full path for the contract -- default is ./[circuit].sol")
+}
+
+func cmdExportSolidity(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		fmt.Println("missing verifying key path -- gnark export-solidity -h for help")
+		os.Exit(-1)
+	}
+	vkPath := filepath.Clean(args[0])
+	vkName := filepath.Base(vkPath)
+	vkExt := filepath.Ext(vkName)
+	vkName = vkName[0 : len(vkName)-len(vkExt)]
+
+	solidityPath := filepath.Join(".", vkName+".sol")
+	if fSolidityPath != "" {
+		solidityPath = fSolidityPath
+	}
+
+	if !fileExists(vkPath) {
+		fmt.Println(vkPath, errNotFound)
+		os.Exit(-1)
+	}
+
+	// check curve ID, the contract uses the Ethereum precompiled contracts which only support bn256
+	curveID, err := gob.PeekCurveID(vkPath)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	if curveID != gurvy.BN256 {
+		fmt.Println("error:", errUnsupportedCurve)
+		os.Exit(-1)
+	}
+
+	var vk groth16_bn256.VerifyingKey
+	if err := gob.Read(vkPath, &vk, curveID); err != nil {
+		fmt.Println("can't load verifying key")
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s\n", "loaded verifying key", vkPath)
+
+	f, err := os.Create(solidityPath)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	defer f.Close()
+	if err := vk.ExportSolidity(f); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %s\n", "generated solidity verifier", solidityPath)
+}
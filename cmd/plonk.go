@@ -0,0 +1,341 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/consensys/gnark/backend"
+	backend_bls377 "github.com/consensys/gnark/backend/bls377"
+	plonk_bls377 "github.com/consensys/gnark/backend/bls377/plonk"
+	backend_bls381 "github.com/consensys/gnark/backend/bls381"
+	plonk_bls381 "github.com/consensys/gnark/backend/bls381/plonk"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+	plonk_bn256 "github.com/consensys/gnark/backend/bn256/plonk"
+	"github.com/consensys/gnark/encoding/gob"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+// PLONK counterparts of the setup, prove and verify commands
+// the circuit is converted to a SparseR1CS, and the setup samples a SRS of the appropriate size
+// (as for groth16, a setup ran by a single party is fine for testing purposes only)
+
+func plonkSetup(circuitPath, pkPath, vkPath string, curveID gurvy.ID) {
+	var bigIntR1cs frontend.R1CS
+	if err := gob.Read(circuitPath, &bigIntR1cs, curveID); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	// TODO clean that up with interfaces and type casts
+	switch curveID {
+	case gurvy.BLS377:
+		r1cs := backend_bls377.CastSparseR1CS(bigIntR1cs.ToSparseR1CS())
+		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
+		// run setup
+		var pk plonk_bls377.ProvingKey
+		var vk plonk_bls377.VerifyingKey
+		start := time.Now()
+		if err := plonk_bls377.Setup(&r1cs, plonk_bls377.NewSRS(plonk_bls377.SRSSize(&r1cs)), &pk, &vk); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		duration := time.Since(start)
+		fmt.Printf("%-30s %-30s %-30s\n", "setup completed", "", duration)
+
+		if err := gob.Write(vkPath, &vk, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %s\n", "generated verifying key", vkPath)
+		if err := gob.Write(pkPath, &pk, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %s\n", "generated proving key", pkPath)
+	case gurvy.BLS381:
+		r1cs := backend_bls381.CastSparseR1CS(bigIntR1cs.ToSparseR1CS())
+		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
+		// run setup
+		var pk plonk_bls381.ProvingKey
+		var vk plonk_bls381.VerifyingKey
+		start := time.Now()
+		if err := plonk_bls381.Setup(&r1cs, plonk_bls381.NewSRS(plonk_bls381.SRSSize(&r1cs)), &pk, &vk); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		duration := time.Since(start)
+		fmt.Printf("%-30s %-30s %-30s\n", "setup completed", "", duration)
+
+		if err := gob.Write(vkPath, &vk, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %s\n", "generated verifying key", vkPath)
+		if err := gob.Write(pkPath, &pk, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %s\n", "generated proving key", pkPath)
+	case gurvy.BN256:
+		r1cs := backend_bn256.CastSparseR1CS(bigIntR1cs.ToSparseR1CS())
+		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
+		// run setup
+		var pk plonk_bn256.ProvingKey
+		var vk plonk_bn256.VerifyingKey
+		start := time.Now()
+		if err := plonk_bn256.Setup(&r1cs, plonk_bn256.NewSRS(plonk_bn256.SRSSize(&r1cs)), &pk, &vk); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		duration := time.Since(start)
+		fmt.Printf("%-30s %-30s %-30s\n", "setup completed", "", duration)
+
+		if err := gob.Write(vkPath, &vk, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %s\n", "generated verifying key", vkPath)
+		if err := gob.Write(pkPath, &pk, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %s\n", "generated proving key", pkPath)
+	default:
+		fmt.Println("error:", errUnknownCurve)
+		os.Exit(-1)
+	}
+}
+
+func plonkProve(circuitPath, pkPath, proofPath string, curveID gurvy.ID) {
+	var bigIntR1cs frontend.R1CS
+	if err := gob.Read(circuitPath, &bigIntR1cs, curveID); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+
+	// parse input file
+	r1csInput := backend.NewAssignment()
+	if err := r1csInput.ReadFile(fInputPath); err != nil {
+		fmt.Println("can't parse input", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
+
+	// TODO clean that up with interfaces and type casts
+	switch curveID {
+	case gurvy.BLS377:
+		r1cs := backend_bls377.CastSparseR1CS(bigIntR1cs.ToSparseR1CS())
+		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
+		var pk plonk_bls377.ProvingKey
+		if err := gob.Read(pkPath, &pk, curveID); err != nil {
+			fmt.Println("can't load proving key")
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s\n", "loaded proving key", pkPath)
+
+		// compute proof
+		start := time.Now()
+		proof, err := plonk_bls377.Prove(&r1cs, &pk, r1csInput)
+		if err != nil {
+			fmt.Println("Error proof generation", err)
+			os.Exit(-1)
+		}
+		for i := uint(1); i < fCount; i++ {
+			_, _ = plonk_bls377.Prove(&r1cs, &pk, r1csInput)
+		}
+		duration := time.Since(start)
+		if fCount > 1 {
+			duration = time.Duration(int64(duration) / int64(fCount))
+		}
+
+		if err := gob.Write(proofPath, proof, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+
+		fmt.Printf("%-30s %-30s %-30s\n", "generated proof", proofPath, duration)
+	case gurvy.BLS381:
+		r1cs := backend_bls381.CastSparseR1CS(bigIntR1cs.ToSparseR1CS())
+		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
+		var pk plonk_bls381.ProvingKey
+		if err := gob.Read(pkPath, &pk, curveID); err != nil {
+			fmt.Println("can't load proving key")
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s\n", "loaded proving key", pkPath)
+
+		// compute proof
+		start := time.Now()
+		proof, err := plonk_bls381.Prove(&r1cs, &pk, r1csInput)
+		if err != nil {
+			fmt.Println("Error proof generation", err)
+			os.Exit(-1)
+		}
+		for i := uint(1); i < fCount; i++ {
+			_, _ = plonk_bls381.Prove(&r1cs, &pk, r1csInput)
+		}
+		duration := time.Since(start)
+		if fCount > 1 {
+			duration = time.Duration(int64(duration) / int64(fCount))
+		}
+
+		if err := gob.Write(proofPath, proof, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+
+		fmt.Printf("%-30s %-30s %-30s\n", "generated proof", proofPath, duration)
+	case gurvy.BN256:
+		r1cs := backend_bn256.CastSparseR1CS(bigIntR1cs.ToSparseR1CS())
+		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
+		var pk plonk_bn256.ProvingKey
+		if err := gob.Read(pkPath, &pk, curveID); err != nil {
+			fmt.Println("can't load proving key")
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s\n", "loaded proving key", pkPath)
+
+		// compute proof
+		start := time.Now()
+		proof, err := plonk_bn256.Prove(&r1cs, &pk, r1csInput)
+		if err != nil {
+			fmt.Println("Error proof generation", err)
+			os.Exit(-1)
+		}
+		for i := uint(1); i < fCount; i++ {
+			_, _ = plonk_bn256.Prove(&r1cs, &pk, r1csInput)
+		}
+		duration := time.Since(start)
+		if fCount > 1 {
+			duration = time.Duration(int64(duration) / int64(fCount))
+		}
+
+		if err := gob.Write(proofPath, proof, curveID); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(-1)
+		}
+
+		fmt.Printf("%-30s %-30s %-30s\n", "generated proof", proofPath, duration)
+	default:
+		fmt.Println("error:", errUnknownCurve)
+		os.Exit(-1)
+	}
+}
+
+func plonkVerify(proofPath, vkPath string, curveID gurvy.ID) {
+	// parse input file
+	r1csInput := backend.NewAssignment()
+	if err := r1csInput.ReadFile(fInputPath); err != nil {
+		fmt.Println("can't parse input", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
+
+	// TODO clean that up with interfaces and type casts
+	switch curveID {
+	case gurvy.BLS377:
+		var vk plonk_bls377.VerifyingKey
+		if err := gob.Read(vkPath, &vk, curveID); err != nil {
+			fmt.Println("can't load verifying key")
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s\n", "loaded verifying key", vkPath)
+
+		// load proof
+		var proof plonk_bls377.Proof
+		if err := gob.Read(proofPath, &proof, curveID); err != nil {
+			fmt.Println("can't parse proof", err)
+			os.Exit(-1)
+		}
+
+		// verify proof
+		start := time.Now()
+		result, err := plonk_bls377.Verify(&proof, &vk, r1csInput)
+		if err != nil || !result {
+			fmt.Printf("%-30s %-30s %-30s\n", "proof is invalid", proofPath, time.Since(start))
+			if err != nil {
+				fmt.Println(err)
+			}
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s %-30s\n", "proof is valid", proofPath, time.Since(start))
+	case gurvy.BLS381:
+		var vk plonk_bls381.VerifyingKey
+		if err := gob.Read(vkPath, &vk, curveID); err != nil {
+			fmt.Println("can't load verifying key")
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s\n", "loaded verifying key", vkPath)
+
+		// load proof
+		var proof plonk_bls381.Proof
+		if err := gob.Read(proofPath, &proof, curveID); err != nil {
+			fmt.Println("can't parse proof", err)
+			os.Exit(-1)
+		}
+
+		// verify proof
+		start := time.Now()
+		result, err := plonk_bls381.Verify(&proof, &vk, r1csInput)
+		if err != nil || !result {
+			fmt.Printf("%-30s %-30s %-30s\n", "proof is invalid", proofPath, time.Since(start))
+			if err != nil {
+				fmt.Println(err)
+			}
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s %-30s\n", "proof is valid", proofPath, time.Since(start))
+	case gurvy.BN256:
+		var vk plonk_bn256.VerifyingKey
+		if err := gob.Read(vkPath, &vk, curveID); err != nil {
+			fmt.Println("can't load verifying key")
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s\n", "loaded verifying key", vkPath)
+
+		// load proof
+		var proof plonk_bn256.Proof
+		if err := gob.Read(proofPath, &proof, curveID); err != nil {
+			fmt.Println("can't parse proof", err)
+			os.Exit(-1)
+		}
+
+		// verify proof
+		start := time.Now()
+		result, err := plonk_bn256.Verify(&proof, &vk, r1csInput)
+		if err != nil || !result {
+			fmt.Printf("%-30s %-30s %-30s\n", "proof is invalid", proofPath, time.Since(start))
+			if err != nil {
+				fmt.Println(err)
+			}
+			os.Exit(-1)
+		}
+		fmt.Printf("%-30s %-30s %-30s\n", "proof is valid", proofPath, time.Since(start))
+	default:
+		fmt.Println("error:", errUnknownCurve)
+		os.Exit(-1)
+	}
+}
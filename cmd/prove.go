@@ -23,15 +23,9 @@ import (
 	"time"
 
 	"github.com/consensys/gnark/backend"
-	backend_bls377 "github.com/consensys/gnark/backend/bls377"
-	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
-	backend_bls381 "github.com/consensys/gnark/backend/bls381"
-	groth16_bls381 "github.com/consensys/gnark/backend/bls381/groth16"
-	backend_bn256 "github.com/consensys/gnark/backend/bn256"
-	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/encoding/gob"
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gurvy"
 	"github.com/spf13/cobra"
 )
 
@@ -54,7 +48,7 @@ func init() {
 	rootCmd.AddCommand(proveCmd)
 	proveCmd.PersistentFlags().StringVar(&fProofPath, "proof", "", "specifies full path for proof -- default is ./[circuit].proof")
 	proveCmd.PersistentFlags().StringVar(&fPkPath, "pk", "", "specifies full path for proving key")
-	proveCmd.PersistentFlags().StringVar(&fInputPath, "input", "", "specifies full path for input file")
+	proveCmd.PersistentFlags().StringVar(&fInputPath, "input", "", "specifies full path for input file (csv or json, detected from its content)")
 	proveCmd.PersistentFlags().UintVar(&fCount, "count", 1, "specifies number of times the prover algorithm is ran (benchmarking purposes)")
 	_ = proveCmd.MarkPersistentFlagRequired("pk")
 	_ = proveCmd.MarkPersistentFlagRequired("input")
@@ -104,168 +98,81 @@ func cmdProve(cmd *cobra.Command, args []string) {
 		fmt.Println("error:", err)
 		os.Exit(-1)
 	}
-	// TODO clean that up with interfaces and type casts
-	var bigIntR1cs frontend.R1CS
-	switch curveID {
-	case gurvy.BLS377:
-		if err := gob.Read(circuitPath, &bigIntR1cs, curveID); err != nil {
-			fmt.Println("error:", err)
-			os.Exit(-1)
-		}
-		r1cs := backend_bls377.Cast(&bigIntR1cs)
-		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
-		// run setup
-		var pk groth16_bls377.ProvingKey
-		if err := gob.Read(fPkPath, &pk, curveID); err != nil {
-			fmt.Println("can't load proving key")
-			fmt.Println(err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s\n", "loaded proving key", fPkPath)
-
-		// parse input file
-		r1csInput := backend.NewAssignment()
-		err = r1csInput.ReadFile(fInputPath)
-		if err != nil {
-			fmt.Println("can't parse input", err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
-
-		// compute proof
-		start := time.Now()
-		proof, err := groth16_bls377.Prove(&r1cs, &pk, r1csInput)
-		if err != nil {
-			fmt.Println("Error proof generation", err)
-			os.Exit(-1)
-		}
-		for i := uint(1); i < fCount; i++ {
-			_, _ = groth16_bls377.Prove(&r1cs, &pk, r1csInput)
-		}
-		duration := time.Since(start)
-		if fCount > 1 {
-			duration = time.Duration(int64(duration) / int64(fCount))
-		}
-
-		// default proof path
-		proofPath := filepath.Join(".", circuitName+".proof")
-		if fProofPath != "" {
-			proofPath = fProofPath
-		}
-
-		if err := gob.Write(proofPath, proof, curveID); err != nil {
-			fmt.Println("error:", err)
-			os.Exit(-1)
-		}
-
-		fmt.Printf("%-30s %-30s %-30s\n", "generated proof", proofPath, duration)
-	case gurvy.BLS381:
-		if err := gob.Read(circuitPath, &bigIntR1cs, curveID); err != nil {
-			fmt.Println("error:", err)
-			os.Exit(-1)
-		}
-		r1cs := backend_bls381.Cast(&bigIntR1cs)
-		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
-		// run setup
-		var pk groth16_bls381.ProvingKey
-		if err := gob.Read(fPkPath, &pk, curveID); err != nil {
-			fmt.Println("can't load proving key")
-			fmt.Println(err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s\n", "loaded proving key", fPkPath)
-
-		// parse input file
-		r1csInput := backend.NewAssignment()
-		err = r1csInput.ReadFile(fInputPath)
-		if err != nil {
-			fmt.Println("can't parse input", err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
-
-		// compute proof
-		start := time.Now()
-		proof, err := groth16_bls381.Prove(&r1cs, &pk, r1csInput)
-		if err != nil {
-			fmt.Println("Error proof generation", err)
-			os.Exit(-1)
-		}
-		for i := uint(1); i < fCount; i++ {
-			_, _ = groth16_bls381.Prove(&r1cs, &pk, r1csInput)
-		}
-		duration := time.Since(start)
-		if fCount > 1 {
-			duration = time.Duration(int64(duration) / int64(fCount))
-		}
-
+	switch fScheme {
+	case schemeGroth16:
+	case schemePLONK:
 		// default proof path
 		proofPath := filepath.Join(".", circuitName+".proof")
 		if fProofPath != "" {
 			proofPath = fProofPath
 		}
+		plonkProve(circuitPath, fPkPath, proofPath, curveID)
+		return
+	default:
+		fmt.Println("error:", errUnknownScheme)
+		os.Exit(-1)
+	}
 
-		if err := gob.Write(proofPath, proof, curveID); err != nil {
-			fmt.Println("error:", err)
-			os.Exit(-1)
-		}
-
-		fmt.Printf("%-30s %-30s %-30s\n", "generated proof", proofPath, duration)
-	case gurvy.BN256:
-		if err := gob.Read(circuitPath, &bigIntR1cs, curveID); err != nil {
-			fmt.Println("error:", err)
-			os.Exit(-1)
-		}
-		r1cs := backend_bn256.Cast(&bigIntR1cs)
-		fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
-		// run setup
-		var pk groth16_bn256.ProvingKey
-		if err := gob.Read(fPkPath, &pk, curveID); err != nil {
-			fmt.Println("can't load proving key")
-			fmt.Println(err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s\n", "loaded proving key", fPkPath)
+	var r1cs frontend.R1CS
+	if err := gob.Read(circuitPath, &r1cs, curveID); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, r1cs.NbConstraints)
 
-		// parse input file
-		r1csInput := backend.NewAssignment()
-		err = r1csInput.ReadFile(fInputPath)
-		if err != nil {
-			fmt.Println("can't parse input", err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
+	// load proving key
+	pk, err := groth16.NewProvingKey(curveID)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	if err := gob.Read(fPkPath, pk, curveID); err != nil {
+		fmt.Println("can't load proving key")
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s\n", "loaded proving key", fPkPath)
 
-		// compute proof
-		start := time.Now()
-		proof, err := groth16_bn256.Prove(&r1cs, &pk, r1csInput)
-		if err != nil {
-			fmt.Println("Error proof generation", err)
-			os.Exit(-1)
-		}
-		for i := uint(1); i < fCount; i++ {
-			_, _ = groth16_bn256.Prove(&r1cs, &pk, r1csInput)
-		}
-		duration := time.Since(start)
-		if fCount > 1 {
-			duration = time.Duration(int64(duration) / int64(fCount))
-		}
+	// parse input file
+	r1csInput := backend.NewAssignment()
+	err = r1csInput.ReadFile(fInputPath)
+	if err != nil {
+		fmt.Println("can't parse input", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
 
-		// default proof path
-		proofPath := filepath.Join(".", circuitName+".proof")
-		if fProofPath != "" {
-			proofPath = fProofPath
-		}
+	// compute proof, the R1CS being converted to the curve before the timer starts
+	prove, err := groth16.NewProver(&r1cs, pk)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	start := time.Now()
+	proof, err := prove(r1csInput)
+	if err != nil {
+		fmt.Println("Error proof generation", err)
+		os.Exit(-1)
+	}
+	for i := uint(1); i < fCount; i++ {
+		_, _ = prove(r1csInput)
+	}
+	duration := time.Since(start)
+	if fCount > 1 {
+		duration = time.Duration(int64(duration) / int64(fCount))
+	}
 
-		if err := gob.Write(proofPath, proof, curveID); err != nil {
-			fmt.Println("error:", err)
-			os.Exit(-1)
-		}
+	// default proof path
+	proofPath := filepath.Join(".", circuitName+".proof")
+	if fProofPath != "" {
+		proofPath = fProofPath
+	}
 
-		fmt.Printf("%-30s %-30s %-30s\n", "generated proof", proofPath, duration)
-	default:
-		fmt.Println("error:", errUnknownCurve)
+	if err := gob.Write(proofPath, proof, curveID); err != nil {
+		fmt.Println("error:", err)
 		os.Exit(-1)
 	}
 
+	fmt.Printf("%-30s %-30s %-30s\n", "generated proof", proofPath, duration)
+
 }
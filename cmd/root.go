@@ -42,8 +42,17 @@ func Execute() {
 	}
 }
 
+// proving schemes supported by the setup, prove and verify commands
+const (
+	schemeGroth16 = "groth16"
+	schemePLONK   = "plonk"
+)
+
+var fScheme string
+
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentFlags().StringVar(&fScheme, "scheme", schemeGroth16, "specifies the proving scheme -- groth16 or plonk")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -23,11 +23,8 @@ import (
 	"time"
 
 	"github.com/consensys/gnark/backend"
-	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
-	groth16_bls381 "github.com/consensys/gnark/backend/bls381/groth16"
-	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/encoding/gob"
-	"github.com/consensys/gurvy"
 	"github.com/spf13/cobra"
 )
 
@@ -42,7 +39,7 @@ var verifyCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(verifyCmd)
 	verifyCmd.PersistentFlags().StringVar(&fVkPath, "vk", "", "specifies full path for verifying key")
-	verifyCmd.PersistentFlags().StringVar(&fInputPath, "input", "", "specifies full path for input file")
+	verifyCmd.PersistentFlags().StringVar(&fInputPath, "input", "", "specifies full path for input file (csv or json, detected from its content)")
 
 	_ = verifyCmd.MarkPersistentFlagRequired("vk")
 	_ = verifyCmd.MarkPersistentFlagRequired("input")
@@ -90,119 +87,57 @@ func cmdVerify(cmd *cobra.Command, args []string) {
 		os.Exit(-1)
 	}
 
-	// TODO clean that up with interfaces and type casts
-	switch curveID {
-	case gurvy.BLS377:
-		var vk groth16_bls377.VerifyingKey
-		if err := gob.Read(fVkPath, &vk, curveID); err != nil {
-			fmt.Println("can't load verifying key")
-			fmt.Println(err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s\n", "loaded verifying key", fVkPath)
+	switch fScheme {
+	case schemeGroth16:
+	case schemePLONK:
+		plonkVerify(proofPath, fVkPath, curveID)
+		return
+	default:
+		fmt.Println("error:", errUnknownScheme)
+		os.Exit(-1)
+	}
 
-		// parse input file
-		r1csInput := backend.NewAssignment()
-		err := r1csInput.ReadFile(fInputPath)
-		if err != nil {
-			fmt.Println("can't parse input", err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
+	vk, err := groth16.NewVerifyingKey(curveID)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	if err := gob.Read(fVkPath, vk, curveID); err != nil {
+		fmt.Println("can't load verifying key")
+		fmt.Println(err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s\n", "loaded verifying key", fVkPath)
 
-		// load proof
-		var proof groth16_bls377.Proof
-		if err := gob.Read(proofPath, &proof, curveID); err != nil {
-			fmt.Println("can't parse proof", err)
-			os.Exit(-1)
-		}
+	// parse input file
+	r1csInput := backend.NewAssignment()
+	if err := r1csInput.ReadFile(fInputPath); err != nil {
+		fmt.Println("can't parse input", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
 
-		// verify proof
-		start := time.Now()
-		result, err := groth16_bls377.Verify(&proof, &vk, r1csInput)
-		if err != nil || !result {
-			fmt.Printf("%-30s %-30s %-30s\n", "proof is invalid", proofPath, time.Since(start))
-			if err != nil {
-				fmt.Println(err)
-			}
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-30s\n", "proof is valid", proofPath, time.Since(start))
-	case gurvy.BLS381:
-		var vk groth16_bls381.VerifyingKey
-		if err := gob.Read(fVkPath, &vk, curveID); err != nil {
-			fmt.Println("can't load verifying key")
-			fmt.Println(err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s\n", "loaded verifying key", fVkPath)
+	// load proof
+	proof, err := groth16.NewProof(curveID)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	if err := gob.Read(proofPath, proof, curveID); err != nil {
+		fmt.Println("can't parse proof", err)
+		os.Exit(-1)
+	}
 
-		// parse input file
-		r1csInput := backend.NewAssignment()
-		err := r1csInput.ReadFile(fInputPath)
+	// verify proof
+	start := time.Now()
+	result, err := groth16.Verify(proof, vk, r1csInput)
+	if err != nil || !result {
+		fmt.Printf("%-30s %-30s %-30s\n", "proof is invalid", proofPath, time.Since(start))
 		if err != nil {
-			fmt.Println("can't parse input", err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
-
-		// load proof
-		var proof groth16_bls381.Proof
-		if err := gob.Read(proofPath, &proof, curveID); err != nil {
-			fmt.Println("can't parse proof", err)
-			os.Exit(-1)
-		}
-
-		// verify proof
-		start := time.Now()
-		result, err := groth16_bls381.Verify(&proof, &vk, r1csInput)
-		if err != nil || !result {
-			fmt.Printf("%-30s %-30s %-30s\n", "proof is invalid", proofPath, time.Since(start))
-			if err != nil {
-				fmt.Println(err)
-			}
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-30s\n", "proof is valid", proofPath, time.Since(start))
-	case gurvy.BN256:
-		var vk groth16_bn256.VerifyingKey
-		if err := gob.Read(fVkPath, &vk, curveID); err != nil {
-			fmt.Println("can't load verifying key")
 			fmt.Println(err)
-			os.Exit(-1)
 		}
-		fmt.Printf("%-30s %-30s\n", "loaded verifying key", fVkPath)
-
-		// parse input file
-		r1csInput := backend.NewAssignment()
-		err := r1csInput.ReadFile(fInputPath)
-		if err != nil {
-			fmt.Println("can't parse input", err)
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-d inputs\n", "loaded input", fInputPath, len(r1csInput))
-
-		// load proof
-		var proof groth16_bn256.Proof
-		if err := gob.Read(proofPath, &proof, curveID); err != nil {
-			fmt.Println("can't parse proof", err)
-			os.Exit(-1)
-		}
-
-		// verify proof
-		start := time.Now()
-		result, err := groth16_bn256.Verify(&proof, &vk, r1csInput)
-		if err != nil || !result {
-			fmt.Printf("%-30s %-30s %-30s\n", "proof is invalid", proofPath, time.Since(start))
-			if err != nil {
-				fmt.Println(err)
-			}
-			os.Exit(-1)
-		}
-		fmt.Printf("%-30s %-30s %-30s\n", "proof is valid", proofPath, time.Since(start))
-	default:
-		fmt.Println("error:", errUnknownCurve)
 		os.Exit(-1)
 	}
+	fmt.Printf("%-30s %-30s %-30s\n", "proof is valid", proofPath, time.Since(start))
 
 }
@@ -0,0 +1,243 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package smt implements a sparse Merkle tree, mapping keys of depth bits to leaves.
+//
+// The leaves are digests (eg the hash of an account), a leaf made of zeros being empty:
+// the tree initially contains only empty leaves, and a proof for an empty leaf is a proof
+// of non-inclusion of its key. A node is the hash of its two children, H(left || right),
+// the node at height i on the path of a key being the left child if the i-th bit of the key is 0.
+package smt
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+)
+
+// MaxDepth maximum depth of a tree, the keys being uint64
+const MaxDepth = 64
+
+var (
+	ErrDepth       = errors.New("the depth of a sparse merkle tree must be between 1 and 64")
+	ErrKeyTooLarge = errors.New("the key doesn't fit on depth bits")
+	ErrLeafSize    = errors.New("a leaf must be of the size of a digest")
+)
+
+// Tree sparse Merkle tree, only the non empty nodes are stored
+type Tree struct {
+	h     hash.Hash
+	depth int
+	empty [][]byte            // empty[i] is the root of an empty subtree of height i
+	nodes []map[uint64][]byte // nodes[i][k] is the non empty node at height i and index k
+}
+
+// Proof proves that Leaf is the leaf of Key, Siblings being the siblings
+// of the nodes on the path from the leaf (Siblings[0]) to the root.
+// A proof for an empty leaf is a proof of non-inclusion.
+type Proof struct {
+	Key      uint64
+	Leaf     []byte
+	Siblings [][]byte
+}
+
+// UpdateProof proves that the leaf of Key is changed from OldLeaf to NewLeaf,
+// Siblings being shared by the paths of the old and the new leaf.
+type UpdateProof struct {
+	Key              uint64
+	OldLeaf, NewLeaf []byte
+	Siblings         [][]byte
+}
+
+// New returns an empty sparse Merkle tree of depth depth, h being used to compute the nodes
+func New(h hash.Hash, depth int) (*Tree, error) {
+	if depth < 1 || depth > MaxDepth {
+		return nil, ErrDepth
+	}
+	t := &Tree{
+		h:     h,
+		depth: depth,
+		empty: make([][]byte, depth+1),
+		nodes: make([]map[uint64][]byte, depth+1),
+	}
+	t.empty[0] = make([]byte, h.Size())
+	for i := 0; i < depth; i++ {
+		t.empty[i+1] = nodeSum(h, t.empty[i], t.empty[i])
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make(map[uint64][]byte)
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree, ie the number of bits of the keys and the number of siblings in a proof
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Root returns the Merkle root of the tree
+func (t *Tree) Root() []byte {
+	return t.node(t.depth, 0)
+}
+
+// Get returns the leaf of key
+func (t *Tree) Get(key uint64) ([]byte, error) {
+	if err := t.checkKey(key); err != nil {
+		return nil, err
+	}
+	return t.node(0, key), nil
+}
+
+// Set sets the leaf of key, an empty leaf (only zeros) removing key from the tree
+func (t *Tree) Set(key uint64, leaf []byte) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	if len(leaf) != t.h.Size() {
+		return ErrLeafSize
+	}
+
+	t.setNode(0, key, leaf)
+	index := key
+	for i := 0; i < t.depth; i++ {
+		var parent []byte
+		if index&1 == 0 {
+			parent = nodeSum(t.h, t.node(i, index), t.node(i, index^1))
+		} else {
+			parent = nodeSum(t.h, t.node(i, index^1), t.node(i, index))
+		}
+		index >>= 1
+		t.setNode(i+1, index, parent)
+	}
+	return nil
+}
+
+// Prove returns a proof of inclusion of the leaf of key, or of non-inclusion if key is not set
+func (t *Tree) Prove(key uint64) (Proof, error) {
+	if err := t.checkKey(key); err != nil {
+		return Proof{}, err
+	}
+	return Proof{
+		Key:      key,
+		Leaf:     t.node(0, key),
+		Siblings: t.siblings(key),
+	}, nil
+}
+
+// Update sets the leaf of key and returns the proof of the update, from the root before
+// the update to the root after the update
+func (t *Tree) Update(key uint64, leaf []byte) (UpdateProof, error) {
+	if err := t.checkKey(key); err != nil {
+		return UpdateProof{}, err
+	}
+	res := UpdateProof{
+		Key:      key,
+		OldLeaf:  t.node(0, key),
+		NewLeaf:  leaf,
+		Siblings: t.siblings(key),
+	}
+	if err := t.Set(key, leaf); err != nil {
+		return UpdateProof{}, err
+	}
+	return res, nil
+}
+
+// VerifyProof returns true if proof.Leaf is the leaf of proof.Key in the tree of root root
+func VerifyProof(h hash.Hash, root []byte, proof Proof) bool {
+	return checkRoot(computeRoot(h, proof.Key, proof.Leaf, proof.Siblings), root)
+}
+
+// VerifyUpdate returns true if setting the leaf of proof.Key from proof.OldLeaf to proof.NewLeaf
+// changes the root from oldRoot to newRoot
+func VerifyUpdate(h hash.Hash, oldRoot, newRoot []byte, proof UpdateProof) bool {
+	return checkRoot(computeRoot(h, proof.Key, proof.OldLeaf, proof.Siblings), oldRoot) &&
+		checkRoot(computeRoot(h, proof.Key, proof.NewLeaf, proof.Siblings), newRoot)
+}
+
+// IsEmpty returns true if leaf is the empty leaf
+func IsEmpty(leaf []byte) bool {
+	for _, b := range leaf {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Tree) checkKey(key uint64) error {
+	if t.depth < MaxDepth && key>>uint(t.depth) != 0 {
+		return ErrKeyTooLarge
+	}
+	return nil
+}
+
+// node returns the node at height i and index k
+func (t *Tree) node(i int, k uint64) []byte {
+	if n, ok := t.nodes[i][k]; ok {
+		return n
+	}
+	return t.empty[i]
+}
+
+// setNode sets the node at height i and index k, the empty nodes being removed
+func (t *Tree) setNode(i int, k uint64, n []byte) {
+	if bytes.Equal(n, t.empty[i]) {
+		delete(t.nodes[i], k)
+		return
+	}
+	t.nodes[i][k] = append([]byte{}, n...)
+}
+
+// siblings returns the siblings of the path of key, from the leaf to the root
+func (t *Tree) siblings(key uint64) [][]byte {
+	res := make([][]byte, t.depth)
+	index := key
+	for i := 0; i < t.depth; i++ {
+		res[i] = t.node(i, index^1)
+		index >>= 1
+	}
+	return res
+}
+
+// computeRoot returns the root of the tree in which key has the leaf leaf, siblings being the siblings of its path
+func computeRoot(h hash.Hash, key uint64, leaf []byte, siblings [][]byte) []byte {
+	if len(siblings) == 0 || len(siblings) > MaxDepth || (len(siblings) < MaxDepth && key>>uint(len(siblings)) != 0) {
+		return nil
+	}
+	res := leaf
+	for i := range siblings {
+		if (key>>uint(i))&1 == 0 {
+			res = nodeSum(h, res, siblings[i])
+		} else {
+			res = nodeSum(h, siblings[i], res)
+		}
+	}
+	return res
+}
+
+// checkRoot returns true if the computed root is the expected one, nil meaning the proof is malformed
+func checkRoot(computed, expected []byte) bool {
+	return computed != nil && bytes.Equal(computed, expected)
+}
+
+// nodeSum returns the hash of two sibling nodes, H(a || b)
+func nodeSum(h hash.Hash, a, b []byte) []byte {
+	h.Reset()
+	// the Hash interface specifies that Write never returns an error
+	_, _ = h.Write(a)
+	_, _ = h.Write(b)
+	return h.Sum(nil)
+}
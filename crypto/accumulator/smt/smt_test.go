@@ -0,0 +1,180 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+)
+
+// leaf returns a non empty leaf
+func leaf(v byte) []byte {
+	res := make([]byte, 32)
+	res[31] = v
+	return res
+}
+
+func TestEmptyTree(t *testing.T) {
+	h := sha256.New()
+	tree, err := New(h, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// root of 8 empty leaves
+	empty := make([]byte, 32)
+	n1 := nodeSum(h, empty, empty)
+	n2 := nodeSum(h, n1, n1)
+	if !bytes.Equal(tree.Root(), nodeSum(h, n2, n2)) {
+		t.Fatal("wrong root for an empty tree")
+	}
+
+	// setting and removing a leaf gives back the empty tree
+	root := tree.Root()
+	if err := tree.Set(5, leaf(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Set(5, empty); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tree.Root(), root) {
+		t.Fatal("removing the only leaf should give back the empty tree")
+	}
+	for i := range tree.nodes {
+		if len(tree.nodes[i]) != 0 {
+			t.Fatal("the empty nodes should not be stored")
+		}
+	}
+}
+
+func TestRoot(t *testing.T) {
+	h := sha256.New()
+	tree, err := New(h, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := uint64(0); k < 4; k++ {
+		if err := tree.Set(k, leaf(byte(k+1))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// same as a dense tree: H(H(l0 || l1) || H(l2 || l3))
+	expected := nodeSum(h, nodeSum(h, leaf(1), leaf(2)), nodeSum(h, leaf(3), leaf(4)))
+	if !bytes.Equal(tree.Root(), expected) {
+		t.Fatal("wrong root")
+	}
+}
+
+func TestProofs(t *testing.T) {
+	h := bn256.NewMiMC("seed")
+	tree, err := New(h, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []uint64{0, 1, 42, 65535} {
+		if err := tree.Set(k, leaf(byte(k))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root := tree.Root()
+
+	// inclusion
+	proof, err := tree.Prove(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyProof(h, root, proof) {
+		t.Fatal("the proof of inclusion should be valid")
+	}
+	proof.Leaf = leaf(43)
+	if VerifyProof(h, root, proof) {
+		t.Fatal("the proof of inclusion of a wrong leaf should be invalid")
+	}
+
+	// non-inclusion
+	proof, err = tree.Prove(43)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEmpty(proof.Leaf) || !VerifyProof(h, root, proof) {
+		t.Fatal("the proof of non-inclusion should be valid")
+	}
+	proof.Key = 42
+	if VerifyProof(h, root, proof) {
+		t.Fatal("the proof of non-inclusion of a set key should be invalid")
+	}
+
+	// update
+	update, err := tree.Update(42, leaf(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRoot := tree.Root()
+	if !VerifyUpdate(h, root, newRoot, update) {
+		t.Fatal("the update proof should be valid")
+	}
+	if VerifyUpdate(h, newRoot, root, update) {
+		t.Fatal("the update proof should be invalid for swapped roots")
+	}
+	if v, _ := tree.Get(42); !bytes.Equal(v, leaf(7)) {
+		t.Fatal("the leaf should be updated")
+	}
+
+	// the insertion of a key is an update from the empty leaf
+	update, err = tree.Update(43, leaf(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEmpty(update.OldLeaf) || !VerifyUpdate(h, newRoot, tree.Root(), update) {
+		t.Fatal("the insertion proof should be valid")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	h := sha256.New()
+	if _, err := New(h, 0); err != ErrDepth {
+		t.Fatal("expected ErrDepth")
+	}
+	if _, err := New(h, MaxDepth+1); err != ErrDepth {
+		t.Fatal("expected ErrDepth")
+	}
+	tree, err := New(h, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Set(16, leaf(1)); err != ErrKeyTooLarge {
+		t.Fatal("expected ErrKeyTooLarge")
+	}
+	if err := tree.Set(15, []byte{1}); err != ErrLeafSize {
+		t.Fatal("expected ErrLeafSize")
+	}
+	proof, err := tree.Prove(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof.Siblings = proof.Siblings[:2]
+	if VerifyProof(h, tree.Root(), proof) {
+		t.Fatal("a truncated proof should be invalid")
+	}
+	if VerifyProof(h, nil, Proof{Key: 0, Leaf: leaf(0)}) {
+		t.Fatal("an empty proof should be invalid")
+	}
+}
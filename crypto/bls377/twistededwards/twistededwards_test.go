@@ -0,0 +1,169 @@
+package twistededwards
+
+import (
+	"github.com/consensys/gurvy/bls377/fr"
+
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+
+	var p1, p2 Point
+
+	p1.X.SetString("1490281172193597134870773664562581324122086672386932174218220602380812543268")
+	p1.Y.SetString("3822677140472726719333476739906330499980736871317261908858656724370476702974")
+
+	p2.X.SetString("3278205758608378614374100185740604362954248734321125104413425219351023226305")
+	p2.Y.SetString("3628856423268184594041764103635620127618957382313033993783785285174035700616")
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString("6609442570318230577166368301403390428262875936124739971838360622565207894865")
+	expectedY.SetString("5870605845835110234521242893705077846977436298081884118506506446707410988550")
+
+	p1.Add(&p1, &p2)
+
+	if !p1.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p1.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
+func TestAddProj(t *testing.T) {
+
+	var p1, p2 Point
+	var p1proj, p2proj PointProj
+
+	p1.X.SetString("1490281172193597134870773664562581324122086672386932174218220602380812543268")
+	p1.Y.SetString("3822677140472726719333476739906330499980736871317261908858656724370476702974")
+
+	p2.X.SetString("3278205758608378614374100185740604362954248734321125104413425219351023226305")
+	p2.Y.SetString("3628856423268184594041764103635620127618957382313033993783785285174035700616")
+
+	p1proj.FromAffine(&p1)
+	p2proj.FromAffine(&p2)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString("6609442570318230577166368301403390428262875936124739971838360622565207894865")
+	expectedY.SetString("5870605845835110234521242893705077846977436298081884118506506446707410988550")
+
+	p1proj.Add(&p1proj, &p2proj)
+	p1.FromProj(&p1proj)
+
+	if !p1.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p1.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
+func TestAddProjNonAffine(t *testing.T) {
+
+	var p1, p2 Point
+	var p1proj, p2proj PointProj
+
+	p1.X.SetString("1490281172193597134870773664562581324122086672386932174218220602380812543268")
+	p1.Y.SetString("3822677140472726719333476739906330499980736871317261908858656724370476702974")
+
+	p2.X.SetString("3278205758608378614374100185740604362954248734321125104413425219351023226305")
+	p2.Y.SetString("3628856423268184594041764103635620127618957382313033993783785285174035700616")
+
+	// 2*p1 + 2*p2, both operands having Z != 1
+	p1proj.FromAffine(&p1).Double(&p1proj)
+	p2proj.FromAffine(&p2).Double(&p2proj)
+	p1proj.Add(&p1proj, &p2proj)
+	p1.FromProj(&p1proj)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString("1332245487968150839384591693279022718621370728204309624370818117161029111477")
+	expectedY.SetString("273083964362869875899422157129781008475223381902205835058396615944477865402")
+
+	if !p1.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p1.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
+func TestDouble(t *testing.T) {
+
+	var p Point
+
+	p.X.SetString("1490281172193597134870773664562581324122086672386932174218220602380812543268")
+	p.Y.SetString("3822677140472726719333476739906330499980736871317261908858656724370476702974")
+
+	p.Double(&p)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString("1000797517546649182453184217210779841379368113449734072336971977387567618593")
+	expectedY.SetString("2915616322039988340870416878618419147914713421226302674538428382721094958207")
+
+	if !p.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
+func TestDoubleProj(t *testing.T) {
+
+	var p Point
+	var pproj PointProj
+
+	p.X.SetString("1490281172193597134870773664562581324122086672386932174218220602380812543268")
+	p.Y.SetString("3822677140472726719333476739906330499980736871317261908858656724370476702974")
+
+	pproj.FromAffine(&p).Double(&pproj)
+
+	p.FromProj(&pproj)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString("1000797517546649182453184217210779841379368113449734072336971977387567618593")
+	expectedY.SetString("2915616322039988340870416878618419147914713421226302674538428382721094958207")
+
+	if !p.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
+func TestScalarMul(t *testing.T) {
+
+	// set curve parameters
+	ed := GetEdwardsCurve()
+
+	var scalar fr.Element
+	scalar.SetUint64(23902374).FromMont()
+
+	var p Point
+	p.ScalarMul(&ed.Base, scalar)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString("3325318589486882180368597836061279041613850994206039496744772927680069206357")
+	expectedY.SetString("1346375924217781592879811475536412101049343472231217752819745489083157050050")
+
+	if !expectedX.Equal(&p.X) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !expectedY.Equal(&p.Y) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
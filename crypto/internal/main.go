@@ -42,6 +42,21 @@ func main() {
 		Package:  "eddsa",
 	}
 
+	eddsabls377 := generator.Data{
+		Curve:    "BLS377",
+		Path:     "../signature/eddsa/bls377/",
+		FileName: "eddsa.go",
+		Src:      []string{template.EddsaTemplate},
+		Package:  "eddsa",
+	}
+	eddsabls377Test := generator.Data{
+		Curve:    "BLS377",
+		Path:     "../signature/eddsa/bls377/",
+		FileName: "eddsa_test.go",
+		Src:      []string{template.EddsaTest},
+		Package:  "eddsa",
+	}
+
 	// -----------------------------------------------------
 	// mimc files
 	mimcbn256 := generator.Data{
@@ -68,14 +83,98 @@ func main() {
 		Package:  "bls377",
 	}
 
+	// -----------------------------------------------------
+	// poseidon files
+	poseidonbn256 := generator.Data{
+		Curve:    "BN256",
+		Path:     "../hash/poseidon/bn256/",
+		FileName: "poseidon_bn256.go",
+		Src:      []string{template.PoseidonTemplate},
+		Package:  "bn256",
+	}
+
+	poseidonbls381 := generator.Data{
+		Curve:    "BLS381",
+		Path:     "../hash/poseidon/bls381/",
+		FileName: "poseidon_bls381.go",
+		Src:      []string{template.PoseidonTemplate},
+		Package:  "bls381",
+	}
+
+	poseidonbls377 := generator.Data{
+		Curve:    "BLS377",
+		Path:     "../hash/poseidon/bls377/",
+		FileName: "poseidon_bls377.go",
+		Src:      []string{template.PoseidonTemplate},
+		Package:  "bls377",
+	}
+
+	// -----------------------------------------------------
+	// pedersen files
+	pedersenbn256 := generator.Data{
+		Curve:    "BN256",
+		Path:     "../commitment/pedersen/bn256/",
+		FileName: "pedersen.go",
+		Src:      []string{template.PedersenTemplate},
+		Package:  "pedersen",
+	}
+	pedersenbn256Test := generator.Data{
+		Curve:    "BN256",
+		Path:     "../commitment/pedersen/bn256/",
+		FileName: "pedersen_test.go",
+		Src:      []string{template.PedersenTest},
+		Package:  "pedersen",
+	}
+
+	pedersenbls381 := generator.Data{
+		Curve:    "BLS381",
+		Path:     "../commitment/pedersen/bls381/",
+		FileName: "pedersen.go",
+		Src:      []string{template.PedersenTemplate},
+		Package:  "pedersen",
+	}
+	pedersenbls381Test := generator.Data{
+		Curve:    "BLS381",
+		Path:     "../commitment/pedersen/bls381/",
+		FileName: "pedersen_test.go",
+		Src:      []string{template.PedersenTest},
+		Package:  "pedersen",
+	}
+
+	pedersenbls377 := generator.Data{
+		Curve:    "BLS377",
+		Path:     "../commitment/pedersen/bls377/",
+		FileName: "pedersen.go",
+		Src:      []string{template.PedersenTemplate},
+		Package:  "pedersen",
+	}
+	pedersenbls377Test := generator.Data{
+		Curve:    "BLS377",
+		Path:     "../commitment/pedersen/bls377/",
+		FileName: "pedersen_test.go",
+		Src:      []string{template.PedersenTest},
+		Package:  "pedersen",
+	}
+
 	data := []generator.Data{
 		eddsabls381,
 		eddsabls381Test,
 		eddsabn256,
 		eddsabn256Test,
+		eddsabls377,
+		eddsabls377Test,
 		mimcbn256,
 		mimcbls381,
 		mimcbls377,
+		poseidonbn256,
+		poseidonbls381,
+		poseidonbls377,
+		pedersenbn256,
+		pedersenbn256Test,
+		pedersenbls381,
+		pedersenbls381Test,
+		pedersenbls377,
+		pedersenbls377Test,
 	}
 
 	for _, d := range data {
@@ -10,7 +10,11 @@ import (
 	"math/big"
 
 	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	{{- if eq .Curve "BLS377"}}
+	"github.com/consensys/gnark/crypto/bls377/twistededwards"
+	{{- else}}
 	"github.com/consensys/gurvy/{{toLower .Curve}}/twistededwards"
+	{{- end}}
 	"golang.org/x/crypto/blake2b"
 )
 
@@ -0,0 +1,123 @@
+package template
+
+const PedersenTemplate = `
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	{{- if eq .Curve "BLS377"}}
+	"github.com/consensys/gnark/crypto/bls377/twistededwards"
+	{{- else}}
+	"github.com/consensys/gurvy/{{toLower .Curve}}/twistededwards"
+	{{- end}}
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrNbGenerators = errors.New("the number of generators must be at least 1")
+	ErrMessageSize  = errors.New("the message has more elements than there are generators")
+)
+
+// Params generators of the Pedersen hash and commitment, on the twisted Edwards curve of eddsa
+//
+// The generators are points of the prime order subgroup derived from a seed by hashing to
+// the curve, so that no discrete logarithm relation between them is known
+type Params struct {
+	Generators []twistededwards.Point // Generators[i] is multiplied by the i-th element of a message
+	H          twistededwards.Point   // blinding generator, multiplied by the randomness of a commitment
+}
+
+// NewParams derives nbGenerators generators and the blinding generator from seed
+func NewParams(seed string, nbGenerators int) (Params, error) {
+	if nbGenerators < 1 {
+		return Params{}, ErrNbGenerators
+	}
+
+	c := twistededwards.GetEdwardsCurve()
+
+	res := Params{
+		Generators: make([]twistededwards.Point, nbGenerators),
+	}
+	for i := 0; i < nbGenerators; i++ {
+		res.Generators[i] = hashToCurve([]byte(seed+"_"+strconv.Itoa(i)), c)
+	}
+	res.H = hashToCurve([]byte(seed+"_h"), c)
+
+	return res, nil
+}
+
+// Hash returns the Pedersen hash of m, sum(m[i]*Generators[i]),
+// the elements of m being in Montgomery form
+func (p *Params) Hash(m ...fr.Element) (twistededwards.Point, error) {
+	if len(m) > len(p.Generators) {
+		return twistededwards.Point{}, ErrMessageSize
+	}
+
+	var res, tmp twistededwards.Point
+	res.Y.SetOne()
+	for i := 0; i < len(m); i++ {
+		tmp.ScalarMul(&p.Generators[i], fromMont(m[i]))
+		res.Add(&res, &tmp)
+	}
+
+	return res, nil
+}
+
+// Commit returns the Pedersen commitment to m with the randomness r, sum(m[i]*Generators[i]) + r*H,
+// r and the elements of m being in Montgomery form
+func (p *Params) Commit(r fr.Element, m ...fr.Element) (twistededwards.Point, error) {
+	res, err := p.Hash(m...)
+	if err != nil {
+		return res, err
+	}
+
+	var tmp twistededwards.Point
+	tmp.ScalarMul(&p.H, fromMont(r))
+	res.Add(&res, &tmp)
+
+	return res, nil
+}
+
+// fromMont returns e in regular form, as expected by the scalar multiplication
+func fromMont(e fr.Element) fr.Element {
+	return *e.FromMont()
+}
+
+// hashToCurve returns a point of the prime order subgroup derived from data:
+// y is hashed until 1-y^2 / (a-d*y^2) has a square root x, and (x, y) is multiplied by the cofactor
+func hashToCurve(data []byte, c twistededwards.CurveParams) twistededwards.Point {
+
+	var res twistededwards.Point
+	var one, y, yy, num, den fr.Element
+	one.SetOne()
+
+	rnd := sha3.Sum256(data)
+	for {
+		y.SetBytes(rnd[:])
+		rnd = sha3.Sum256(rnd[:])
+
+		yy.Mul(&y, &y)
+		num.Sub(&one, &yy)
+		den.Mul(&c.D, &yy).Sub(&c.A, &den)
+		if den.IsZero() {
+			continue
+		}
+		num.Div(&num, &den)
+		if num.Legendre() != 1 {
+			continue
+		}
+
+		res.X.Sqrt(&num)
+		res.Y.Set(&y)
+		res.ScalarMul(&res, c.Cofactor)
+
+		// the point is of small order if the multiplication by the cofactor is the neutral element
+		if !res.X.IsZero() {
+			return res
+		}
+	}
+}
+
+`
@@ -0,0 +1,228 @@
+package template
+
+const PoseidonTemplate = `
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	"golang.org/x/crypto/sha3"
+)
+
+{{ if eq .Curve "BLS377" }}
+// Alpha exponent of the S-box x -> x^Alpha, smallest integer such that gcd(Alpha, r-1) = 1
+const Alpha = 11
+{{ else }}
+// Alpha exponent of the S-box x -> x^Alpha, smallest integer such that gcd(Alpha, r-1) = 1
+const Alpha = 5
+{{ end }}
+
+// BlockSize size that poseidon consumes
+const BlockSize = 32
+
+// number of full rounds, half of them are done before the partial rounds
+const nbFullRounds = 8
+
+// number of partial rounds, indexed by the width of the permutation. The values are the ones
+// of https://eprint.iacr.org/2019/458.pdf for Alpha = 5 and 128 bits of security (conservative
+// for larger Alpha)
+var nbPartialRounds = map[int]int{2: 56, 3: 57, 4: 56, 5: 60, 6: 60, 7: 63, 8: 64, 9: 63}
+
+// ErrUnsupportedWidth is returned when no parameter set exists for the requested width
+var ErrUnsupportedWidth = errors.New("unsupported poseidon width, must be between 2 and 9")
+
+// Params constants of the Poseidon permutation
+type Params struct {
+	Width           int            // number of elements of the state, the sponge absorbs Width-1 elements per permutation
+	NbFullRounds    int
+	NbPartialRounds int
+	RoundConstants  [][]fr.Element // Width constants per round
+	MDS             [][]fr.Element // Width x Width Cauchy matrix, MDS[i][j] = 1 / (i + Width + j)
+}
+
+// NewParams creates the constants of a Poseidon permutation on width elements
+func NewParams(seed string, width int) (Params, error) {
+	nbPartial, ok := nbPartialRounds[width]
+	if !ok {
+		return Params{}, ErrUnsupportedWidth
+	}
+
+	res := Params{
+		Width:           width,
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartial,
+	}
+
+	// round constants
+	rnd := sha3.Sum256([]byte(seed))
+	value := new(big.Int).SetBytes(rnd[:])
+
+	res.RoundConstants = make([][]fr.Element, nbFullRounds+nbPartial)
+	for i := 0; i < len(res.RoundConstants); i++ {
+		res.RoundConstants[i] = make([]fr.Element, width)
+		for j := 0; j < width; j++ {
+			rnd = sha3.Sum256(value.Bytes())
+			value.SetBytes(rnd[:])
+			res.RoundConstants[i][j].SetBigInt(value)
+		}
+	}
+
+	// mds matrix
+	res.MDS = make([][]fr.Element, width)
+	for i := 0; i < width; i++ {
+		res.MDS[i] = make([]fr.Element, width)
+		for j := 0; j < width; j++ {
+			res.MDS[i][j].SetUint64(uint64(i + width + j)).Inverse(&res.MDS[i][j])
+		}
+	}
+
+	return res, nil
+}
+
+// Permutation applies the Poseidon permutation to state, which must contain Width elements
+func (p *Params) Permutation(state []fr.Element) {
+	half := p.NbFullRounds / 2
+	tmp := make([]fr.Element, p.Width)
+
+	for r := 0; r < p.NbFullRounds+p.NbPartialRounds; r++ {
+
+		// add round constants
+		for i := 0; i < p.Width; i++ {
+			state[i].Add(&state[i], &p.RoundConstants[r][i])
+		}
+
+		// S-box on the whole state for full rounds, on the first element for partial rounds
+		if r < half || r >= half+p.NbPartialRounds {
+			for i := 0; i < p.Width; i++ {
+				state[i].Exp(state[i], Alpha)
+			}
+		} else {
+			state[0].Exp(state[0], Alpha)
+		}
+
+		// mix
+		for i := 0; i < p.Width; i++ {
+			tmp[i].SetZero()
+			for j := 0; j < p.Width; j++ {
+				var t fr.Element
+				t.Mul(&p.MDS[i][j], &state[j])
+				tmp[i].Add(&tmp[i], &t)
+			}
+		}
+		copy(state, tmp)
+	}
+}
+
+// digest represents the partial evaluation of the checksum
+// along with the params of the poseidon function
+type digest struct {
+	Params Params
+	data   []byte // data to hash
+}
+
+// NewPoseidon returns a Poseidon hash, pure-go reference implementation
+func NewPoseidon(seed string, width int) (hash.Hash, error) {
+	params, err := NewParams(seed, width)
+	if err != nil {
+		return nil, err
+	}
+	d := new(digest)
+	d.Params = params
+	d.Reset()
+	return d, nil
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	buffer := d.checksum()
+	d.data = nil // flush the data already hashed
+	hash := buffer.Bytes()
+	b = append(b, hash[:]...)
+	return b
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return BlockSize
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+// It never returns an error.
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.data = append(d.data, p...)
+	return
+}
+
+// checksum hashes the data with a sponge: the first element of the state (capacity) is initialized
+// with the number of field elements to hash, the others (rate) absorb the field elements,
+// Width-1 at a time, and the result is the second element of the state
+func (d *digest) checksum() fr.Element {
+
+	// if data size is not multiple of BlockSizes we padd:
+	// .. || 0xaf8 -> .. || 0x0000...0af8
+	if len(d.data)%BlockSize != 0 {
+		q := len(d.data) / BlockSize
+		r := len(d.data) % BlockSize
+		sliceq := make([]byte, q*BlockSize)
+		copy(sliceq, d.data)
+		slicer := make([]byte, r)
+		copy(slicer, d.data[q*BlockSize:])
+		sliceremainder := make([]byte, BlockSize-r)
+		d.data = append(sliceq, sliceremainder...)
+		d.data = append(d.data, slicer...)
+	}
+
+	nbElements := len(d.data) / BlockSize
+	rate := d.Params.Width - 1
+
+	state := make([]fr.Element, d.Params.Width)
+	state[0].SetUint64(uint64(nbElements))
+
+	nbBlocks := (nbElements + rate - 1) / rate
+	if nbBlocks == 0 {
+		nbBlocks = 1
+	}
+
+	var x fr.Element
+	for b := 0; b < nbBlocks; b++ {
+		for i := 0; i < rate && b*rate+i < nbElements; i++ {
+			k := b*rate + i
+			x.SetBytes(d.data[k*BlockSize : (k+1)*BlockSize])
+			state[1+i].Add(&state[1+i], &x)
+		}
+		d.Params.Permutation(state)
+	}
+
+	return state[1]
+}
+
+// Sum computes the poseidon hash of msg from seed, with a permutation on width elements
+func Sum(seed string, width int, msg []byte) ([]byte, error) {
+	params, err := NewParams(seed, width)
+	if err != nil {
+		return nil, err
+	}
+	var d digest
+	d.Params = params
+	d.Write(msg)
+	h := d.checksum()
+	return h.Bytes(), nil
+}
+`
@@ -0,0 +1,85 @@
+package template
+
+const PedersenTest = `
+
+import (
+	"testing"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+func TestPedersen(t *testing.T) {
+
+	params, err := NewParams("seed", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the generators are distinct points of the curve
+	generators := append(params.Generators, params.H)
+	for i := range generators {
+		if !generators[i].IsOnCurve() {
+			t.Fatal("generator not on curve")
+		}
+		for j := 0; j < i; j++ {
+			if generators[i].X.Equal(&generators[j].X) && generators[i].Y.Equal(&generators[j].Y) {
+				t.Fatal("generators should be distinct")
+			}
+		}
+	}
+
+	m := make([]fr.Element, 3)
+	for i := range m {
+		m[i].SetRandom()
+	}
+	var r fr.Element
+	r.SetRandom()
+
+	// the commitment is the hash plus r*H
+	h, err := params.Hash(m...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := params.Commit(r, m...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, tmp := h, params.H
+	tmp.ScalarMul(&params.H, fromMont(r))
+	expected.Add(&expected, &tmp)
+	if !c.X.Equal(&expected.X) || !c.Y.Equal(&expected.Y) {
+		t.Fatal("commitment should be the hash plus r*H")
+	}
+	if !c.IsOnCurve() {
+		t.Fatal("commitment not on curve")
+	}
+
+	// changing the message or the randomness changes the commitment
+	var r2 fr.Element
+	r2.SetOne().Add(&r2, &r)
+	c2, err := params.Commit(r2, m...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.X.Equal(&c2.X) && c.Y.Equal(&c2.Y) {
+		t.Fatal("commitments with different randomness should differ")
+	}
+	m[0].Add(&m[0], &r)
+	c2, err = params.Commit(r, m...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.X.Equal(&c2.X) && c.Y.Equal(&c2.Y) {
+		t.Fatal("commitments to different messages should differ")
+	}
+
+	// a message can't have more elements than there are generators
+	if _, err := params.Hash(append(m, r)...); err != ErrMessageSize {
+		t.Fatal("expected ErrMessageSize")
+	}
+	if _, err := NewParams("seed", 0); err != ErrNbGenerators {
+		t.Fatal("expected ErrNbGenerators")
+	}
+}
+
+`
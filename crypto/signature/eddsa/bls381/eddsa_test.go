@@ -20,7 +20,6 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark/crypto/hash/mimc/bls381"
-	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
 	"github.com/consensys/gurvy/bls381/fr"
 )
 
@@ -74,7 +73,7 @@ func BenchmarkVerify(b *testing.B) {
 		seed[i] = v
 	}
 
-	hFunc := bn256.NewMiMC("seed")
+	hFunc := bls381.NewMiMC("seed")
 
 	// create eddsa obj and sign a message
 	pubKey, privKey := New(seed, hFunc)
@@ -11,21 +11,34 @@ func main() {
 	gob.Write("circuit.r1cs", circuit, gurvy.BN256)
 }
 
-// New return the circuit implementing
-//  x**3 + x + 5 == y
-func New() *frontend.R1CS {
-	// create root constraint system
-	circuit := frontend.New()
-
-	// declare secret and public inputs
-	x := circuit.SECRET_INPUT("x")
-	y := circuit.PUBLIC_INPUT("y")
+// CubicCircuit defines a simple circuit
+//
+//	x**3 + x + 5 == y
+type CubicCircuit struct {
+	// struct tags on a variable is optional
+	// default uses variable name and secret visibility.
+	X frontend.Variable `gnark:"x"`
+	Y frontend.Variable `gnark:"y,public"`
+}
 
-	// specify constraints
-	x3 := circuit.MUL(x, x, x)
+// Define declares the circuit constraints
+//
+//	x**3 + x + 5 == y
+func (circuit *CubicCircuit) Define(cs *frontend.CS) error {
+	x3 := cs.MUL(circuit.X, circuit.X, circuit.X)
 	x3.Tag("x^3") // we can tag a variable for testing and / or debugging purposes, it has no impact on performances
-	circuit.MUSTBE_EQ(y, circuit.ADD(x3, x, 5))
+	cs.MUSTBE_EQ(circuit.Y, cs.ADD(x3, circuit.X, 5))
+	return nil
+}
 
-	r1cs := circuit.ToR1CS()
+// New return the circuit implementing
+//
+//	x**3 + x + 5 == y
+func New() *frontend.R1CS {
+	var circuit CubicCircuit
+	r1cs, err := frontend.Compile(&circuit)
+	if err != nil {
+		panic(err)
+	}
 	return r1cs
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/consensys/gnark/backend"
 	backend_bn256 "github.com/consensys/gnark/backend/bn256"
 	"github.com/consensys/gnark/backend/bn256/groth16"
+	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy/bn256/fr"
 )
 
@@ -32,9 +33,13 @@ func TestCubicEquation(t *testing.T) {
 	}
 
 	{
-		good := backend.NewAssignment()
-		good.Assign(backend.Secret, "x", 3)
-		good.Assign(backend.Public, "y", 35)
+		var witness CubicCircuit
+		witness.X.Assign(3)
+		witness.Y.Assign(35)
+		good, err := frontend.ToAssignment(&witness)
+		if err != nil {
+			t.Fatal(err)
+		}
 		expectedValues := make(map[string]fr.Element)
 		var x, xcube fr.Element
 		xcube.SetUint64(27)
@@ -20,7 +20,7 @@ import (
 	"strconv"
 
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/gadgets/accumulator/merkle"
+	"github.com/consensys/gnark/gadgets/accumulator/smt"
 	twistededwards_gadget "github.com/consensys/gnark/gadgets/algebra/twistededwards"
 	"github.com/consensys/gnark/gadgets/hash/mimc"
 	"github.com/consensys/gnark/gadgets/signature/eddsa"
@@ -28,19 +28,14 @@ import (
 )
 
 var (
-	// basename of the inputs for the proofs before update
-	baseNameSenderMerkleBefore        = "merkle_sender_proof_before_"
-	baseNameSenderProofHelperBefore   = "merkle_sender_proof_helper_before"
-	baseNameReceiverMerkleBefore      = "merkle_receiver_proof_before_"
-	baseNameReceiverProofHelperBefore = "merkle_receiver_proof_helper_before"
-	baseNameRootHashBefore            = "merkle_rh_before_"
-
-	// basename of the inputs for the proofs after update
-	baseNameSenderMerkleAfter        = "merkle_sender_proof_after_"
-	baseNameSenderProofHelperAfter   = "merkle_sender_proof_helper_after"
-	baseNameReceiverMerkleAfter      = "merkle_receiver_proof_after_"
-	baseNameReceiverProofHelperAfter = "merkle_receiver_proof_helper_after"
-	baseNameRootHashAfter            = "merkle_rh_after_"
+	// basename of the inputs for the update proofs of the sender and the receiver accounts
+	baseNameSenderMerkle   = "merkle_sender_proof_"
+	baseNameReceiverMerkle = "merkle_receiver_proof_"
+
+	// basename of the root hashes before the update, after the update of the sender, and after the update
+	baseNameRootHashBefore       = "merkle_rh_before_"
+	baseNameRootHashIntermediate = "merkle_rh_intermediate_"
+	baseNameRootHashAfter        = "merkle_rh_after_"
 
 	// basename sender account pubkey
 	baseNameSenderAccountPubkeyx = "a_sender_pubkeyx_"
@@ -51,8 +46,7 @@ var (
 	baseNameSenderAccountNonceBefore   = "a_sender_nonce_before_"
 	baseNameSenderAccountBalanceBefore = "a_sender_balance_before_"
 
-	// basename of the sender account input adter update
-	baseNameSenderAccountIndexAfter   = "a_sender_index_before_after_"
+	// basename of the sender account input after update (the index doesn't change)
 	baseNameSenderAccountNonceAfter   = "a_sender_nonce_before_after_"
 	baseNameSenderAccountBalanceAfter = "a_sender_balance_before_after_"
 
@@ -65,8 +59,7 @@ var (
 	baseNameReceiverAccountNonceBefore   = "a_receiver_nonce_before_"
 	baseNameReceiverAccountBalanceBefore = "a_receiver_balance_before_"
 
-	// basename of the receiver account input after update
-	baseNameReceiverAccountIndexAfter   = "a_receiver_index_after_"
+	// basename of the receiver account input after update (the index doesn't change)
 	baseNameReceiverAccountNonceAfter   = "a_receiver_nonce_after_"
 	baseNameReceiverAccountBalanceAfter = "a_receiver_balance_after_"
 
@@ -107,14 +100,12 @@ func verifySignatureTransfer(circuit *frontend.CS, t TransferCircuit, hFunc mimc
 	return nil
 }
 
-// checkCorrectLeaf checks if hacc = hFunc(acc)
-func ensureCorrectLeaf(circuit *frontend.CS, hFunc mimc.MiMCGadget, acc AccountCircuit, hacc *frontend.Constraint) {
+// accountLeaf returns the leaf of acc in the state tree, hFunc(acc)
+func accountLeaf(circuit *frontend.CS, hFunc mimc.MiMCGadget, acc AccountCircuit) *frontend.Constraint {
 
 	// compute the hash of the account, serialized like this:
 	// index || nonce || balance || pubkeyX || pubkeyY
-	haccount := hFunc.Hash(circuit, acc.index, acc.nonce, acc.balance, acc.pubKey.A.X, acc.pubKey.A.Y)
-
-	circuit.MUSTBE_EQ(haccount, hacc)
+	return hFunc.Hash(circuit, acc.index, acc.nonce, acc.balance, acc.pubKey.A.X, acc.pubKey.A.Y)
 
 }
 
@@ -141,7 +132,7 @@ func verifyUpdateAccountGadget(circuit *frontend.CS, from, to, fromUpdated, toUp
 
 // rollupCircuit createsa full rollup circuit
 // batchSize size of a batch of transaction
-// depth size of the merkle proofs
+// depth depth of the state tree, ie the size of the merkle proofs
 // nbAccounts number of accounts managed by the operator
 // indices list of indices for the proofs
 func rollupCircuit(circuit *frontend.CS, batchSize int, depth int, nbAccounts int) error {
@@ -174,53 +165,31 @@ func rollupCircuit(circuit *frontend.CS, batchSize int, depth int, nbAccounts in
 	// list of transactions
 	transfers := make([]TransferCircuit, batchSize)
 
-	// list of proofs corresponding to sender account
-	merkleProofsSenderBefore := make([][]*frontend.Constraint, batchSize)
-	merkleProofsSenderAfter := make([][]*frontend.Constraint, batchSize)
-	merkleProofHelperSenderBefore := make([][]*frontend.Constraint, batchSize)
-	merkleProofHelperSenderAfter := make([][]*frontend.Constraint, batchSize)
+	// list of the siblings of the update proofs of the sender and receiver accounts
+	merkleProofsSender := make([][]*frontend.Constraint, batchSize)
+	merkleProofsReceiver := make([][]*frontend.Constraint, batchSize)
 
-	// list of proofs corresponding to receiver account
-	merkleProofsReceiverBefore := make([][]*frontend.Constraint, batchSize)
-	merkleProofsReceiverAfter := make([][]*frontend.Constraint, batchSize)
-	merkleProofHelperReceiverBefore := make([][]*frontend.Constraint, batchSize)
-	merkleProofHelperReceiverAfter := make([][]*frontend.Constraint, batchSize)
-
-	// list of root hashes
+	// list of root hashes, the intermediate root being the root after the update of the sender account
 	rootHashesBefore := make([]*frontend.Constraint, batchSize)
+	rootHashesIntermediate := make([]*frontend.Constraint, batchSize)
 	rootHashesAfter := make([]*frontend.Constraint, batchSize)
 
 	for i := 0; i < batchSize; i++ {
 
 		// setting the root hashes
 		rootHashesBefore[i] = circuit.PUBLIC_INPUT(baseNameRootHashBefore + strconv.Itoa(i))
+		rootHashesIntermediate[i] = circuit.SECRET_INPUT(baseNameRootHashIntermediate + strconv.Itoa(i))
 		rootHashesAfter[i] = circuit.PUBLIC_INPUT(baseNameRootHashAfter + strconv.Itoa(i))
 
 		// setting the sender/receiver proofs elmts
-		merkleProofsSenderBefore[i] = make([]*frontend.Constraint, depth)
-		merkleProofsReceiverBefore[i] = make([]*frontend.Constraint, depth)
-		merkleProofHelperSenderBefore[i] = make([]*frontend.Constraint, depth-1)
-		merkleProofHelperSenderAfter[i] = make([]*frontend.Constraint, depth-1)
-
-		merkleProofsSenderAfter[i] = make([]*frontend.Constraint, depth)
-		merkleProofsReceiverAfter[i] = make([]*frontend.Constraint, depth)
-		merkleProofHelperReceiverBefore[i] = make([]*frontend.Constraint, depth-1)
-		merkleProofHelperReceiverAfter[i] = make([]*frontend.Constraint, depth-1)
+		merkleProofsSender[i] = make([]*frontend.Constraint, depth)
+		merkleProofsReceiver[i] = make([]*frontend.Constraint, depth)
 
 		for j := 0; j < depth; j++ {
 			ext := strconv.Itoa(i) + strconv.Itoa(j)
 
-			merkleProofsSenderBefore[i][j] = circuit.SECRET_INPUT(baseNameSenderMerkleBefore + ext)
-			merkleProofsSenderAfter[i][j] = circuit.SECRET_INPUT(baseNameSenderMerkleAfter + ext)
-			merkleProofsReceiverBefore[i][j] = circuit.SECRET_INPUT(baseNameReceiverMerkleBefore + ext)
-			merkleProofsReceiverAfter[i][j] = circuit.SECRET_INPUT(baseNameReceiverMerkleAfter + ext)
-
-			if j < depth-1 {
-				merkleProofHelperSenderBefore[i][j] = circuit.SECRET_INPUT(baseNameSenderProofHelperBefore + ext)
-				merkleProofHelperSenderAfter[i][j] = circuit.SECRET_INPUT(baseNameSenderProofHelperAfter + ext)
-				merkleProofHelperReceiverBefore[i][j] = circuit.SECRET_INPUT(baseNameReceiverProofHelperBefore + ext)
-				merkleProofHelperReceiverAfter[i][j] = circuit.SECRET_INPUT(baseNameReceiverProofHelperAfter + ext)
-			}
+			merkleProofsSender[i][j] = circuit.SECRET_INPUT(baseNameSenderMerkle + ext)
+			merkleProofsReceiver[i][j] = circuit.SECRET_INPUT(baseNameReceiverMerkle + ext)
 		}
 
 		// setting sender public key
@@ -240,7 +209,7 @@ func rollupCircuit(circuit *frontend.CS, batchSize int, depth int, nbAccounts in
 		senderAccountsBefore[i].pubKey = publicKeysSender[i]
 
 		// setting the sender accounts after update
-		senderAccountsAfter[i].index = circuit.SECRET_INPUT(baseNameSenderAccountIndexAfter + strconv.Itoa(i))
+		senderAccountsAfter[i].index = senderAccountsBefore[i].index
 		senderAccountsAfter[i].nonce = circuit.SECRET_INPUT(baseNameSenderAccountNonceAfter + strconv.Itoa(i))
 		senderAccountsAfter[i].balance = circuit.SECRET_INPUT(baseNameSenderAccountBalanceAfter + strconv.Itoa(i))
 		senderAccountsAfter[i].pubKey = publicKeysSender[i]
@@ -252,7 +221,7 @@ func rollupCircuit(circuit *frontend.CS, batchSize int, depth int, nbAccounts in
 		receiverAccountsBefore[i].pubKey = publicKeysReceiver[i]
 
 		// setting the receiver accounts after update
-		receiverAccountsAfter[i].index = circuit.SECRET_INPUT(baseNameReceiverAccountIndexAfter + strconv.Itoa(i))
+		receiverAccountsAfter[i].index = receiverAccountsBefore[i].index
 		receiverAccountsAfter[i].nonce = circuit.SECRET_INPUT(baseNameReceiverAccountNonceAfter + strconv.Itoa(i))
 		receiverAccountsAfter[i].balance = circuit.SECRET_INPUT(baseNameReceiverAccountBalanceAfter + strconv.Itoa(i))
 		receiverAccountsAfter[i].pubKey = publicKeysReceiver[i]
@@ -271,13 +240,13 @@ func rollupCircuit(circuit *frontend.CS, batchSize int, depth int, nbAccounts in
 	// creation of the circuit
 	for i := 0; i < batchSize; i++ {
 
-		// verify the sender and receiver accounts exist before the update
-		merkle.VerifyProof(circuit, hFunc, rootHashesBefore[i], merkleProofsSenderBefore[i], merkleProofHelperSenderBefore[i])
-		merkle.VerifyProof(circuit, hFunc, rootHashesBefore[i], merkleProofsReceiverBefore[i], merkleProofHelperReceiverBefore[i])
+		// verify the update of the sender account, from the root before to the intermediate root
+		smt.VerifyUpdate(circuit, hFunc, rootHashesBefore[i], rootHashesIntermediate[i], senderAccountsBefore[i].index,
+			accountLeaf(circuit, hFunc, senderAccountsBefore[i]), accountLeaf(circuit, hFunc, senderAccountsAfter[i]), merkleProofsSender[i])
 
-		// verify the sender and receiver accounts exist after the update
-		merkle.VerifyProof(circuit, hFunc, rootHashesAfter[i], merkleProofsSenderAfter[i], merkleProofHelperSenderAfter[i])
-		merkle.VerifyProof(circuit, hFunc, rootHashesAfter[i], merkleProofsReceiverAfter[i], merkleProofHelperReceiverAfter[i])
+		// verify the update of the receiver account, from the intermediate root to the root after
+		smt.VerifyUpdate(circuit, hFunc, rootHashesIntermediate[i], rootHashesAfter[i], receiverAccountsBefore[i].index,
+			accountLeaf(circuit, hFunc, receiverAccountsBefore[i]), accountLeaf(circuit, hFunc, receiverAccountsAfter[i]), merkleProofsReceiver[i])
 
 		// verify the transaction transfer
 		err := verifySignatureTransfer(circuit, transfers[i], hFunc)
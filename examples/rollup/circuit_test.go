@@ -23,11 +23,12 @@ import (
 	backend_bn256 "github.com/consensys/gnark/backend/bn256"
 	"github.com/consensys/gnark/backend/bn256/groth16"
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/gadgets/accumulator/merkle"
+	"github.com/consensys/gnark/gadgets/accumulator/smt"
 	twistededwards_gadget "github.com/consensys/gnark/gadgets/algebra/twistededwards"
 	"github.com/consensys/gnark/gadgets/hash/mimc"
 	"github.com/consensys/gnark/gadgets/signature/eddsa"
 	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256/fr"
 )
 
 func TestCircuitSignature(t *testing.T) {
@@ -36,7 +37,7 @@ func TestCircuitSignature(t *testing.T) {
 
 	nbAccounts := 10
 
-	operator, users := createOperator(nbAccounts)
+	operator, users := createOperator(t, nbAccounts)
 
 	// read accounts involved in the transfer
 	sender, err := operator.readAccount(0)
@@ -100,7 +101,7 @@ func TestCircuitSignature(t *testing.T) {
 	}
 
 	// verifies the signature of the transfer
-	circuit := frontend.New()
+	circuit := frontend.NewTestEngine(fr.ElementModulus(), operator.witnesses)
 
 	paramsGadget, err := twistededwards_gadget.NewEdCurveGadget(gurvy.BN256)
 	if err != nil {
@@ -132,10 +133,9 @@ func TestCircuitSignature(t *testing.T) {
 
 	verifySignatureTransfer(&circuit, gTransfer, hFunc)
 
-	r1cs := backend_bn256.New(&circuit)
-
-	assert := groth16.NewAssert(t)
-	assert.Solved(&r1cs, operator.witnesses, nil)
+	if _, err := circuit.Inspect(false); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func TestCircuitInclusionProof(t *testing.T) {
@@ -146,10 +146,10 @@ func TestCircuitInclusionProof(t *testing.T) {
 
 	notInInpuList := " is not in the input list"
 
-	// 16 accounts so we know that the proof length is 5
+	// 16 accounts so we know that the proof length is 4
 	nbAccounts := 16
 
-	operator, users := createOperator(nbAccounts)
+	operator, users := createOperator(t, nbAccounts)
 
 	// read accounts involved in the transfer
 	sender, err := operator.readAccount(0)
@@ -181,86 +181,70 @@ func TestCircuitInclusionProof(t *testing.T) {
 
 	// check the inputs for the proofs of the sender/receiver are instantiated
 	ext := "0"
-	for i := 0; i < 2; i++ {
-		if _, ok := operator.witnesses[baseNameSenderMerkleBefore+ext+strconv.Itoa(i)]; !ok {
-			t.Fatal(baseNameSenderMerkleBefore + ext + strconv.Itoa(i) + notInInpuList)
-		}
-		if _, ok := operator.witnesses[baseNameSenderMerkleAfter+ext+strconv.Itoa(i)]; !ok {
-			t.Fatal(baseNameSenderMerkleAfter + ext + strconv.Itoa(i) + notInInpuList)
-		}
-		if _, ok := operator.witnesses[baseNameReceiverMerkleBefore+ext+strconv.Itoa(i)]; !ok {
-			t.Fatal(baseNameReceiverMerkleBefore + ext + strconv.Itoa(i) + notInInpuList)
+	depth := treeDepth(nbAccounts)
+	for i := 0; i < depth; i++ {
+		if _, ok := operator.witnesses[baseNameSenderMerkle+ext+strconv.Itoa(i)]; !ok {
+			t.Fatal(baseNameSenderMerkle + ext + strconv.Itoa(i) + notInInpuList)
 		}
-		if _, ok := operator.witnesses[baseNameReceiverMerkleAfter+ext+strconv.Itoa(i)]; !ok {
-			t.Fatal(baseNameReceiverMerkleAfter + ext + strconv.Itoa(i) + notInInpuList)
-		}
-		if _, ok := operator.witnesses[baseNameRootHashBefore+ext]; !ok {
-			t.Fatal(baseNameRootHashBefore + notInInpuList)
-		}
-		if _, ok := operator.witnesses[baseNameRootHashAfter+ext]; !ok {
-			t.Fatal(baseNameRootHashAfter + notInInpuList)
+		if _, ok := operator.witnesses[baseNameReceiverMerkle+ext+strconv.Itoa(i)]; !ok {
+			t.Fatal(baseNameReceiverMerkle + ext + strconv.Itoa(i) + notInInpuList)
 		}
 	}
-	// check that the proofs helpers of the sender/receivers are instantiated
-	for i := 0; i < 1; i++ {
-		if _, ok := operator.witnesses[baseNameSenderProofHelperBefore+ext+strconv.Itoa(i)]; !ok {
-			t.Fatal(baseNameSenderProofHelperBefore + ext + strconv.Itoa(i) + notInInpuList)
-		}
-		if _, ok := operator.witnesses[baseNameSenderProofHelperAfter+ext+strconv.Itoa(i)]; !ok {
-			t.Fatal(baseNameSenderProofHelperAfter + ext + strconv.Itoa(i) + notInInpuList)
-		}
-		if _, ok := operator.witnesses[baseNameReceiverProofHelperBefore+ext+strconv.Itoa(i)]; !ok {
-			t.Fatal(baseNameReceiverProofHelperBefore + ext + strconv.Itoa(i) + notInInpuList)
-		}
-		if _, ok := operator.witnesses[baseNameReceiverProofHelperAfter+ext+strconv.Itoa(i)]; !ok {
-			t.Fatal(baseNameReceiverProofHelperAfter + ext + strconv.Itoa(i) + notInInpuList)
+	for _, name := range []string{baseNameRootHashBefore, baseNameRootHashIntermediate, baseNameRootHashAfter} {
+		if _, ok := operator.witnesses[name+ext]; !ok {
+			t.Fatal(name + notInInpuList)
 		}
 	}
 
-	// verifies the proofs of inclusion of the transfer
-	circuit := frontend.New()
+	// verifies the proofs of update of the transfer
+	circuit := frontend.NewTestEngine(fr.ElementModulus(), operator.witnesses)
 
-	merkleProofSenderBefore := make([]*frontend.Constraint, 5)
-	merkleProofSenderAfter := make([]*frontend.Constraint, 5)
-	merkleProofReceiverBefore := make([]*frontend.Constraint, 5)
-	merkleProofReceiverAfter := make([]*frontend.Constraint, 5)
-
-	merkleHelperSenderBefore := make([]*frontend.Constraint, 4)
-	merkleHelperSenderAfter := make([]*frontend.Constraint, 4)
-	merkleHelperReceiverBefore := make([]*frontend.Constraint, 4)
-	merkleHelperReceiverAfter := make([]*frontend.Constraint, 4)
-
-	for i := 0; i < 5; i++ {
-		merkleProofSenderBefore[i] = circuit.SECRET_INPUT(baseNameSenderMerkleBefore + ext + strconv.Itoa(i))
-		merkleProofSenderAfter[i] = circuit.SECRET_INPUT(baseNameSenderMerkleAfter + ext + strconv.Itoa(i))
-		merkleProofReceiverBefore[i] = circuit.SECRET_INPUT(baseNameReceiverMerkleBefore + ext + strconv.Itoa(i))
-		merkleProofReceiverAfter[i] = circuit.SECRET_INPUT(baseNameReceiverMerkleAfter + ext + strconv.Itoa(i))
-	}
-	for i := 0; i < 4; i++ {
-		merkleHelperSenderBefore[i] = circuit.SECRET_INPUT(baseNameSenderProofHelperBefore + ext + strconv.Itoa(i))
-		merkleHelperSenderAfter[i] = circuit.SECRET_INPUT(baseNameSenderProofHelperAfter + ext + strconv.Itoa(i))
-		merkleHelperReceiverBefore[i] = circuit.SECRET_INPUT(baseNameReceiverProofHelperBefore + ext + strconv.Itoa(i))
-		merkleHelperReceiverAfter[i] = circuit.SECRET_INPUT(baseNameReceiverProofHelperAfter + ext + strconv.Itoa(i))
+	merkleProofSender := make([]*frontend.Constraint, depth)
+	merkleProofReceiver := make([]*frontend.Constraint, depth)
+	for i := 0; i < depth; i++ {
+		merkleProofSender[i] = circuit.SECRET_INPUT(baseNameSenderMerkle + ext + strconv.Itoa(i))
+		merkleProofReceiver[i] = circuit.SECRET_INPUT(baseNameReceiverMerkle + ext + strconv.Itoa(i))
 	}
 
 	merkleRootBefore := circuit.PUBLIC_INPUT(baseNameRootHashBefore + ext)
+	merkleRootIntermediate := circuit.SECRET_INPUT(baseNameRootHashIntermediate + ext)
 	merkleRootAfter := circuit.PUBLIC_INPUT(baseNameRootHashAfter + ext)
 
+	var fromBefore, fromAfter, toBefore, toAfter AccountCircuit
+
+	fromBefore.index = circuit.SECRET_INPUT(baseNameSenderAccountIndexBefore + ext)
+	fromBefore.nonce = circuit.SECRET_INPUT(baseNameSenderAccountNonceBefore + ext)
+	fromBefore.balance = circuit.SECRET_INPUT(baseNameSenderAccountBalanceBefore + ext)
+	fromBefore.pubKey.A.X = circuit.SECRET_INPUT(baseNameSenderAccountPubkeyx + ext)
+	fromBefore.pubKey.A.Y = circuit.SECRET_INPUT(baseNameSenderAccountPubkeyy + ext)
+
+	fromAfter = fromBefore
+	fromAfter.nonce = circuit.SECRET_INPUT(baseNameSenderAccountNonceAfter + ext)
+	fromAfter.balance = circuit.SECRET_INPUT(baseNameSenderAccountBalanceAfter + ext)
+
+	toBefore.index = circuit.SECRET_INPUT(baseNameReceiverAccountIndexBefore + ext)
+	toBefore.nonce = circuit.SECRET_INPUT(baseNameReceiverAccountNonceBefore + ext)
+	toBefore.balance = circuit.SECRET_INPUT(baseNameReceiverAccountBalanceBefore + ext)
+	toBefore.pubKey.A.X = circuit.SECRET_INPUT(baseNameReceiverAccountPubkeyx + ext)
+	toBefore.pubKey.A.Y = circuit.SECRET_INPUT(baseNameReceiverAccountPubkeyy + ext)
+
+	toAfter = toBefore
+	toAfter.nonce = circuit.SECRET_INPUT(baseNameReceiverAccountNonceAfter + ext)
+	toAfter.balance = circuit.SECRET_INPUT(baseNameReceiverAccountBalanceAfter + ext)
+
 	hFunc, err := mimc.NewMiMCGadget("seed", gurvy.BN256)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	merkle.VerifyProof(&circuit, hFunc, merkleRootBefore, merkleProofSenderBefore, merkleHelperSenderBefore)
-	merkle.VerifyProof(&circuit, hFunc, merkleRootBefore, merkleProofReceiverBefore, merkleHelperReceiverBefore)
-
-	merkle.VerifyProof(&circuit, hFunc, merkleRootAfter, merkleProofSenderAfter, merkleHelperSenderAfter)
-	merkle.VerifyProof(&circuit, hFunc, merkleRootAfter, merkleProofReceiverAfter, merkleHelperReceiverAfter)
-
-	r1cs := backend_bn256.New(&circuit)
+	smt.VerifyUpdate(&circuit, hFunc, merkleRootBefore, merkleRootIntermediate, fromBefore.index,
+		accountLeaf(&circuit, hFunc, fromBefore), accountLeaf(&circuit, hFunc, fromAfter), merkleProofSender)
+	smt.VerifyUpdate(&circuit, hFunc, merkleRootIntermediate, merkleRootAfter, toBefore.index,
+		accountLeaf(&circuit, hFunc, toBefore), accountLeaf(&circuit, hFunc, toAfter), merkleProofReceiver)
 
-	assert := groth16.NewAssert(t)
-	assert.Solved(&r1cs, operator.witnesses, nil)
+	if _, err := circuit.Inspect(false); err != nil {
+		t.Fatal(err)
+	}
 
 }
 
@@ -272,10 +256,10 @@ func TestCircuitUpdateAccount(t *testing.T) {
 
 	notInInpuList := " is not in the input list"
 
-	// 16 accounts so we know that the proof length is 5
+	// 16 accounts so we know that the proof length is 4
 	nbAccounts := 16
 
-	operator, users := createOperator(nbAccounts)
+	operator, users := createOperator(t, nbAccounts)
 
 	// read accounts involved in the transfer
 	sender, err := operator.readAccount(0)
@@ -318,9 +302,6 @@ func TestCircuitUpdateAccount(t *testing.T) {
 			t.Fatal(baseNameSenderAccountBalanceBefore + notInInpuList)
 		}
 
-		if _, ok := operator.witnesses[baseNameSenderAccountIndexAfter+ext]; !ok {
-			t.Fatal(baseNameSenderAccountIndexAfter + notInInpuList)
-		}
 		if _, ok := operator.witnesses[baseNameSenderAccountNonceAfter+ext]; !ok {
 			t.Fatal(baseNameSenderAccountNonceAfter + notInInpuList)
 		}
@@ -338,9 +319,6 @@ func TestCircuitUpdateAccount(t *testing.T) {
 			t.Fatal(baseNameReceiverAccountBalanceBefore + notInInpuList)
 		}
 
-		if _, ok := operator.witnesses[baseNameReceiverAccountIndexAfter+ext]; !ok {
-			t.Fatal(baseNameReceiverAccountIndexAfter + notInInpuList)
-		}
 		if _, ok := operator.witnesses[baseNameReceiverAccountNonceAfter+ext]; !ok {
 			t.Fatal(baseNameReceiverAccountNonceAfter + notInInpuList)
 		}
@@ -350,7 +328,7 @@ func TestCircuitUpdateAccount(t *testing.T) {
 	}
 
 	// verifies the proofs of inclusion of the transfer
-	circuit := frontend.New()
+	circuit := frontend.NewTestEngine(fr.ElementModulus(), operator.witnesses)
 
 	transferAmount := circuit.SECRET_INPUT(baseNameTransferAmount + ext)
 
@@ -360,7 +338,7 @@ func TestCircuitUpdateAccount(t *testing.T) {
 	fromBefore.nonce = circuit.SECRET_INPUT(baseNameSenderAccountNonceBefore + ext)
 	fromBefore.balance = circuit.SECRET_INPUT(baseNameSenderAccountBalanceBefore + ext)
 
-	fromAfter.index = circuit.SECRET_INPUT(baseNameSenderAccountIndexAfter + ext)
+	fromAfter.index = fromBefore.index
 	fromAfter.nonce = circuit.SECRET_INPUT(baseNameSenderAccountNonceAfter + ext)
 	fromAfter.balance = circuit.SECRET_INPUT(baseNameSenderAccountBalanceAfter + ext)
 
@@ -368,16 +346,15 @@ func TestCircuitUpdateAccount(t *testing.T) {
 	toBefore.nonce = circuit.SECRET_INPUT(baseNameReceiverAccountNonceBefore + ext)
 	toBefore.balance = circuit.SECRET_INPUT(baseNameReceiverAccountBalanceBefore + ext)
 
-	toAfter.index = circuit.SECRET_INPUT(baseNameReceiverAccountIndexAfter + ext)
+	toAfter.index = toBefore.index
 	toAfter.nonce = circuit.SECRET_INPUT(baseNameReceiverAccountNonceAfter + ext)
 	toAfter.balance = circuit.SECRET_INPUT(baseNameReceiverAccountBalanceAfter + ext)
 
 	verifyUpdateAccountGadget(&circuit, fromBefore, toBefore, fromAfter, toAfter, transferAmount)
 
-	r1cs := backend_bn256.New(&circuit)
-
-	assert := groth16.NewAssert(t)
-	assert.Solved(&r1cs, operator.witnesses, nil)
+	if _, err := circuit.Inspect(false); err != nil {
+		t.Fatal(err)
+	}
 
 }
 
@@ -387,11 +364,11 @@ func TestCircuitFull(t *testing.T) {
 		t.Skip("skipping rollup tests for circleCI")
 	}
 
-	nbAccounts := 16 // 16 accounts so we know that the proof length is 5
-	depth := 5       // size fo the inclusion proofs
-	batchSize := 1   // nbTranfers to batch in a proof
+	nbAccounts := 16               // 16 accounts so we know that the proof length is 4
+	depth := treeDepth(nbAccounts) // size fo the update proofs
+	batchSize := 1                 // nbTranfers to batch in a proof
 
-	operator, users := createOperator(nbAccounts)
+	operator, users := createOperator(t, nbAccounts)
 
 	// read accounts involved in the transfer
 	sender, err := operator.readAccount(0)
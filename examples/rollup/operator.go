@@ -17,14 +17,13 @@ limitations under the License.
 package rollup
 
 import (
-	"bytes"
 	"hash"
 	"math/big"
+	"math/bits"
 	"strconv"
 
 	"github.com/consensys/gnark/backend"
-	"github.com/consensys/gnark/crypto/accumulator/merkletree"
-	"github.com/consensys/gnark/gadgets/accumulator/merkle"
+	"github.com/consensys/gnark/crypto/accumulator/smt"
 )
 
 // BatchSize size of a batch of transactions to put in a snark
@@ -57,7 +56,7 @@ func (q *Queue) Pop() Transfer {
 // Operator represents a rollup operator
 type Operator struct {
 	State      []byte              // list of accounts: index || nonce || balance || pubkeyX || pubkeyY, each chunk is 256 bits
-	HashState  []byte              // Hashed version of the state, each chunk is 256bits: ... || H(index || nonce || balance || pubkeyX || pubkeyY)) || ...
+	StateTree  *smt.Tree           // sparse Merkle tree of the state, the leaf of key i being H(index || nonce || balance || pubkeyX || pubkeyY) of the i-th account
 	AccountMap map[string]uint64   // hashmap of all available accounts (the key is the account.pubkey.X), the value is the index of the account in the state
 	nbAccounts int                 // number of accounts managed by this operator
 	h          hash.Hash           // hash function used to build the Merkle Tree
@@ -66,30 +65,51 @@ type Operator struct {
 	witnesses  backend.Assignments // witnesses for the snark cicruit
 }
 
+// treeDepth returns the depth of the state tree of an operator managing nbAccounts accounts
+func treeDepth(nbAccounts int) int {
+	if nbAccounts < 2 {
+		return 1
+	}
+	return bits.Len(uint(nbAccounts - 1))
+}
+
 // NewOperator creates a new operator.
 // nbAccounts is the number of accounts managed by this operator, h is the hash function for the merkle proofs
-func NewOperator(nbAccounts int, h hash.Hash) Operator {
+func NewOperator(nbAccounts int, h hash.Hash) (Operator, error) {
 	res := Operator{}
 
 	// create a list of empty accounts
 	res.State = make([]byte, SizeAccount*nbAccounts)
 
-	// initialize hash of the state
-	res.HashState = make([]byte, h.Size()*nbAccounts)
+	// initialize the state tree
+	stateTree, err := smt.New(h, treeDepth(nbAccounts))
+	if err != nil {
+		return res, err
+	}
+	res.StateTree = stateTree
+	res.h = h
 	for i := 0; i < nbAccounts; i++ {
-		h.Reset()
-		h.Write(res.State[i*SizeAccount : i*SizeAccount+SizeAccount])
-		s := h.Sum([]byte{})
-		copy(res.HashState[i*h.Size():(i+1)*h.Size()], s)
+		if err := res.setLeaf(uint64(i), res.State[i*SizeAccount:i*SizeAccount+SizeAccount]); err != nil {
+			return res, err
+		}
 	}
 
 	res.AccountMap = make(map[string]uint64)
 	res.nbAccounts = nbAccounts
-	res.h = h
 	res.q = NewQueue(BatchSize)
 	res.batch = 0
 	res.witnesses = backend.NewAssignment()
-	return res
+	return res, nil
+}
+
+// setLeaf sets the leaf of the i-th account in the state tree to the hash of the serialized account
+func (o *Operator) setLeaf(i uint64, account []byte) error {
+	o.h.Reset()
+	_, err := o.h.Write(account)
+	if err != nil {
+		return err
+	}
+	return o.StateTree.Set(i, o.h.Sum([]byte{}))
 }
 
 // readAccount reads the account located at index i
@@ -112,7 +132,6 @@ func (o *Operator) updateState(t Transfer, numTransfer int) error {
 	var ok bool
 
 	ext := strconv.Itoa(numTransfer)
-	segmentSize := o.h.Size()
 
 	// read sender's account
 	if posSender, ok = o.AccountMap[string(t.senderPubKey.A.X.Bytes())]; !ok {
@@ -147,40 +166,6 @@ func (o *Operator) updateState(t Transfer, numTransfer int) error {
 	o.witnesses.Assign(backend.Secret, baseNameReceiverAccountNonceBefore+ext, receiverAccount.nonce)
 	o.witnesses.Assign(backend.Secret, baseNameReceiverAccountBalanceBefore+ext, receiverAccount.balance)
 
-	//  Set witnesses for the proof of inclusion of sender and receivers account before update
-	var buf bytes.Buffer
-	_, err = buf.Write(o.HashState)
-	if err != nil {
-		return err
-	}
-	merkleRootBefore, proofInclusionSenderBefore, numLeaves, err := merkletree.BuildReaderProof(&buf, o.h, segmentSize, posSender)
-	if err != nil {
-		return err
-	}
-	merkletree.VerifyProof(o.h, merkleRootBefore, proofInclusionSenderBefore, posSender, numLeaves)
-	merkleProofHelperSenderBefore := merkle.GenerateProofHelper(proofInclusionSenderBefore, posSender, numLeaves)
-
-	buf.Reset() // the buffer needs to be reset
-	_, err = buf.Write(o.HashState)
-	if err != nil {
-		return err
-	}
-	_, proofInclusionReceiverBefore, _, err := merkletree.BuildReaderProof(&buf, o.h, segmentSize, posReceiver)
-	if err != nil {
-		return err
-	}
-	merkleProofHelperReceiverBefore := merkle.GenerateProofHelper(proofInclusionReceiverBefore, posReceiver, numLeaves)
-	o.witnesses.Assign(backend.Public, baseNameRootHashBefore+ext, merkleRootBefore)
-	for i := 0; i < len(proofInclusionSenderBefore); i++ {
-		o.witnesses.Assign(backend.Secret, baseNameSenderMerkleBefore+ext+strconv.Itoa(i), proofInclusionSenderBefore[i])
-		o.witnesses.Assign(backend.Secret, baseNameReceiverMerkleBefore+ext+strconv.Itoa(i), proofInclusionReceiverBefore[i])
-
-		if i < len(proofInclusionReceiverBefore)-1 {
-			o.witnesses.Assign(backend.Secret, baseNameSenderProofHelperBefore+ext+strconv.Itoa(i), merkleProofHelperSenderBefore[i])
-			o.witnesses.Assign(backend.Secret, baseNameReceiverProofHelperBefore+ext+strconv.Itoa(i), merkleProofHelperReceiverBefore[i])
-		}
-	}
-
 	// set witnesses for the transfer
 	o.witnesses.Assign(backend.Secret, baseNameTransferAmount+ext, t.amount)
 	o.witnesses.Assign(backend.Secret, baseNameTransferSigRx+ext, t.signature.R.X)
@@ -220,60 +205,45 @@ func (o *Operator) updateState(t Transfer, numTransfer int) error {
 	senderAccount.nonce++
 
 	// set the witnesses for the account after update
-	o.witnesses.Assign(backend.Secret, baseNameSenderAccountIndexAfter+ext, senderAccount.index)
 	o.witnesses.Assign(backend.Secret, baseNameSenderAccountNonceAfter+ext, senderAccount.nonce)
 	o.witnesses.Assign(backend.Secret, baseNameSenderAccountBalanceAfter+ext, senderAccount.balance)
 
-	o.witnesses.Assign(backend.Secret, baseNameReceiverAccountIndexAfter+ext, receiverAccount.index)
 	o.witnesses.Assign(backend.Secret, baseNameReceiverAccountNonceAfter+ext, receiverAccount.nonce)
 	o.witnesses.Assign(backend.Secret, baseNameReceiverAccountBalanceAfter+ext, receiverAccount.balance)
 
-	// update the state of the operator
-	copy(o.State[int(posSender)*SizeAccount:], senderAccount.Serialize())
-	o.h.Reset()
-	o.h.Write(senderAccount.Serialize())
-	bufSender := o.h.Sum([]byte{})
-	copy(o.HashState[int(posSender)*o.h.Size():(int(posSender)+1)*o.h.Size()], bufSender)
-
-	copy(o.State[int(posReceiver)*SizeAccount:], receiverAccount.Serialize())
-	o.h.Reset()
-	o.h.Write(receiverAccount.Serialize())
-	bufReceiver := o.h.Sum([]byte{})
-	copy(o.HashState[int(posReceiver)*o.h.Size():(int(posReceiver)+1)*o.h.Size()], bufReceiver)
+	// update the state of the operator, the sender account then the receiver account,
+	// and set the witnesses for the proofs of the two updates
+	o.witnesses.Assign(backend.Public, baseNameRootHashBefore+ext, o.StateTree.Root())
 
-	//  Set witnesses for the proof of inclusion of sender and receivers account after update
-	buf.Reset()
-	_, err = buf.Write(o.HashState)
-	if err != nil {
-		return err
-	}
-	merkleRootAfer, proofInclusionSenderAfter, _, err := merkletree.BuildReaderProof(&buf, o.h, segmentSize, posSender)
+	copy(o.State[int(posSender)*SizeAccount:], senderAccount.Serialize())
+	proofSender, err := o.updateLeaf(posSender, senderAccount)
 	if err != nil {
 		return err
 	}
-	merkleProofHelperSenderAfter := merkle.GenerateProofHelper(proofInclusionSenderAfter, posSender, numLeaves)
+	o.witnesses.Assign(backend.Secret, baseNameRootHashIntermediate+ext, o.StateTree.Root())
 
-	buf.Reset() // the buffer needs to be reset
-	_, err = buf.Write(o.HashState)
-	if err != nil {
-		return err
-	}
-	_, proofInclusionReceiverAfter, _, err := merkletree.BuildReaderProof(&buf, o.h, segmentSize, posReceiver)
+	copy(o.State[int(posReceiver)*SizeAccount:], receiverAccount.Serialize())
+	proofReceiver, err := o.updateLeaf(posReceiver, receiverAccount)
 	if err != nil {
 		return err
 	}
-	merkleProofHelperReceiverAfter := merkle.GenerateProofHelper(proofInclusionReceiverAfter, posReceiver, numLeaves)
-
-	o.witnesses.Assign(backend.Public, baseNameRootHashAfter+ext, merkleRootAfer)
-	for i := 0; i < len(proofInclusionSenderAfter); i++ {
-		o.witnesses.Assign(backend.Secret, baseNameSenderMerkleAfter+ext+strconv.Itoa(i), proofInclusionSenderAfter[i])
-		o.witnesses.Assign(backend.Secret, baseNameReceiverMerkleAfter+ext+strconv.Itoa(i), proofInclusionReceiverAfter[i])
+	o.witnesses.Assign(backend.Public, baseNameRootHashAfter+ext, o.StateTree.Root())
 
-		if i < len(proofInclusionSenderAfter)-1 {
-			o.witnesses.Assign(backend.Secret, baseNameSenderProofHelperAfter+ext+strconv.Itoa(i), merkleProofHelperSenderAfter[i])
-			o.witnesses.Assign(backend.Secret, baseNameReceiverProofHelperAfter+ext+strconv.Itoa(i), merkleProofHelperReceiverAfter[i])
-		}
+	for i := 0; i < len(proofSender.Siblings); i++ {
+		o.witnesses.Assign(backend.Secret, baseNameSenderMerkle+ext+strconv.Itoa(i), proofSender.Siblings[i])
+		o.witnesses.Assign(backend.Secret, baseNameReceiverMerkle+ext+strconv.Itoa(i), proofReceiver.Siblings[i])
 	}
 
 	return nil
 }
+
+// updateLeaf sets the leaf of the i-th account in the state tree to the hash of account,
+// and returns the proof of the update
+func (o *Operator) updateLeaf(i uint64, account Account) (smt.UpdateProof, error) {
+	o.h.Reset()
+	_, err := o.h.Write(account.Serialize())
+	if err != nil {
+		return smt.UpdateProof{}, err
+	}
+	return o.StateTree.Update(i, o.h.Sum([]byte{}))
+}
@@ -28,7 +28,7 @@ func TestOperatorReadAccount(t *testing.T) {
 	hFunc := mimc.NewMiMC("seed")
 
 	// create operator with 10 accounts
-	operator, _ := createOperator(10)
+	operator, _ := createOperator(t, 10)
 
 	// check if the account read from the operator are correct
 	for i := 0; i < 10; i++ {
@@ -49,7 +49,7 @@ func TestSignTransfer(t *testing.T) {
 	var amount uint64
 
 	// create operator with 10 accounts
-	operator, userKeys := createOperator(10)
+	operator, userKeys := createOperator(t, 10)
 
 	sender, err := operator.readAccount(0)
 	if err != nil {
@@ -94,7 +94,7 @@ func TestOperatorUpdateAccount(t *testing.T) {
 	var amount uint64
 
 	// create operator with 10 accounts
-	operator, userKeys := createOperator(10)
+	operator, userKeys := createOperator(t, 10)
 
 	// get info on the parties
 	sender, err := operator.readAccount(0)
@@ -134,8 +134,16 @@ func TestOperatorUpdateAccount(t *testing.T) {
 	receiver.balance.Add(&sender.balance, &frAmount)
 
 	compareAccount(t, newSender, sender)
-	compareHashAccount(t, operator.HashState[0:operator.h.Size()], newSender, operator.h)
+	leafSender, err := operator.StateTree.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareHashAccount(t, leafSender, newSender, operator.h)
 
 	compareAccount(t, newReceiver, receiver)
-	compareHashAccount(t, operator.HashState[operator.h.Size():2*operator.h.Size()], newReceiver, operator.h)
+	leafReceiver, err := operator.StateTree.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareHashAccount(t, leafReceiver, newReceiver, operator.h)
 }
@@ -44,11 +44,14 @@ func createAccount(i int, h hash.Hash) (Account, eddsa.PrivateKey) {
 }
 
 // Returns a newly created operator and tha private keys of the associated accounts
-func createOperator(nbAccounts int) (Operator, []eddsa.PrivateKey) {
+func createOperator(t *testing.T, nbAccounts int) (Operator, []eddsa.PrivateKey) {
 
 	hFunc := mimc.NewMiMC("seed")
 
-	operator := NewOperator(nbAccounts, hFunc)
+	operator, err := NewOperator(nbAccounts, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	userAccounts := make([]eddsa.PrivateKey, nbAccounts)
 
@@ -66,11 +69,10 @@ func createOperator(nbAccounts int) (Operator, []eddsa.PrivateKey) {
 
 		copy(operator.State[SizeAccount*i:], baccount)
 
-		// create the list of hashes of account
-		operator.h.Reset()
-		operator.h.Write(acc.Serialize())
-		buf := operator.h.Sum([]byte{})
-		copy(operator.HashState[operator.h.Size()*i:], buf)
+		// set the leaf of the account in the state tree
+		if err := operator.setLeaf(acc.index, baccount); err != nil {
+			t.Fatal(err)
+		}
 	}
 
 	return operator, userAccounts
@@ -0,0 +1,82 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// resolvedPCs caches the location (file:line) of the program counters met by callSite,
+// or "" if the program counter is inside the frontend package
+var resolvedPCs sync.Map
+
+// callSite returns the location (file:line) of the first caller outside the frontend package.
+// The file is given by the import path of its package (eg github.com/consensys/gnark/examples/cubic/cubic.go:42),
+// such that the call sites recorded in a R1CS don't depend on where its sources were built.
+// The stack is unwound a few frames at a time, the API being shallow
+func callSite() string {
+	var pcs [8]uintptr
+	for skip := 2; ; skip += len(pcs) {
+		n := runtime.Callers(skip, pcs[:])
+		for _, pc := range pcs[:n] {
+			site, ok := resolvedPCs.Load(pc)
+			if !ok {
+				site = resolvePC(pc)
+				resolvedPCs.Store(pc, site)
+			}
+			if site != "" {
+				return site.(string)
+			}
+		}
+		if n < len(pcs) {
+			return "unknown"
+		}
+	}
+}
+
+// resolvePC returns the location of pc, or "" if it is inside the frontend package.
+// pc may expand into several frames if functions were inlined
+func resolvePC(pc uintptr) string {
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.Function, "gnark/frontend.") || strings.HasSuffix(frame.File, "_test.go") {
+			return file(frame) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// file returns the file of frame prefixed by the import path of its package, or its absolute path if
+// the function of the frame is unknown
+func file(frame runtime.Frame) string {
+	// the package is the function name up to the first dot after the last slash (eg github.com/a/b.(*T).f)
+	pkg := frame.Function
+	slash := strings.LastIndex(pkg, "/")
+	dot := strings.Index(pkg[slash+1:], ".")
+	if dot == -1 {
+		return frame.File
+	}
+	pkg = strings.TrimSuffix(pkg[:slash+1+dot], "_test")
+	return pkg + "/" + path.Base(frame.File)
+}
@@ -0,0 +1,243 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+)
+
+var (
+	ErrInvalidCircuit = errors.New("circuit must be a pointer to a struct implementing frontend.Circuit")
+	ErrInvalidTag     = errors.New("invalid gnark struct tag")
+)
+
+// Circuit must be implemented by user-defined circuits
+//
+// the circuit inputs are declared as Variable fields of the struct implementing Circuit,
+// and their name and visibility are specified through a struct tag:
+//
+//	X Variable `gnark:"x,secret"`
+//	Y Variable `gnark:",public"` // the name defaults to the field name
+//	Z Variable `gnark:"-"`       // ignored
+//
+// nested structs, arrays and slices of Variable are supported; the input names are then
+// prefixed by the parent name, and suffixed by the index (ie "parent_child_2").
+// embedded structs are flattened in their parent
+type Circuit interface {
+	// Define declares the circuit's Constraints
+	Define(cs *CS) error
+}
+
+// Variable is a circuit input declared as a field of a Circuit
+//
+// when the circuit is compiled, the embedded Constraint is set to the allocated input;
+// when building a witness, Assign sets the value of the input
+type Variable struct {
+	*Constraint
+	val        interface{}
+	isAssigned bool
+}
+
+// Assign sets the value of the Variable, used when building a witness from a Circuit
+func (v *Variable) Assign(value interface{}) {
+	if v.isAssigned {
+		panic("variable already assigned")
+	}
+	v.val = value
+	v.isAssigned = true
+}
+
+// CompileOption configures Compile
+type CompileOption func(*compileConfig)
+
+type compileConfig struct {
+	optimizationModulus *big.Int
+}
+
+// WithOptimization makes Compile return the optimized R1CS (see R1CS.Optimize), modulus being
+// the modulus of the scalar field of the curve the R1CS will be used with
+func WithOptimization(modulus *big.Int) CompileOption {
+	return func(config *compileConfig) {
+		config.optimizationModulus = modulus
+	}
+}
+
+// Compile allocates the inputs of the circuit, calls circuit.Define and returns the resulting R1CS
+// the constraint system doesn't panic on API errors, they are returned as an ErrorList (see NewSafe)
+func Compile(circuit Circuit, opts ...CompileOption) (*R1CS, error) {
+	var config compileConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	cs := NewSafe()
+
+	if err := cs.allocateInputs(circuit); err != nil {
+		return nil, err
+	}
+
+	if err := circuit.Define(&cs); err != nil {
+		return nil, err
+	}
+
+	r1cs, err := cs.Compile()
+	if err != nil || config.optimizationModulus == nil {
+		return r1cs, err
+	}
+	optimized, _ := r1cs.Optimize(config.optimizationModulus)
+	return optimized, nil
+}
+
+// allocateInputs sets the Constraint of each Variable of the circuit to a newly declared input
+func (cs *CS) allocateInputs(circuit Circuit) error {
+	return parseCircuit(circuit, func(name string, visibility backend.Visibility, v *Variable) error {
+		if !cs.registerNamedInput(cs.scoped(name)) {
+			return fmt.Errorf("input %q already declared", cs.scoped(name))
+		}
+		switch visibility {
+		case backend.Secret:
+			v.Constraint = cs.SECRET_INPUT(name)
+		case backend.Public:
+			v.Constraint = cs.PUBLIC_INPUT(name)
+		}
+		return nil
+	})
+}
+
+// ToAssignment returns the backend.Assignments built from the assigned Variable of the circuit
+// all inputs must have been assigned
+func ToAssignment(circuit Circuit) (backend.Assignments, error) {
+	toReturn := backend.NewAssignment()
+
+	err := parseCircuit(circuit, func(name string, visibility backend.Visibility, v *Variable) error {
+		if !v.isAssigned {
+			return fmt.Errorf("%q: %w", name, backend.ErrInputNotSet)
+		}
+		if _, ok := toReturn[name]; ok {
+			return fmt.Errorf("input %q already declared", name)
+		}
+		toReturn.Assign(visibility, name, v.val)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReturn, nil
+}
+
+// inputHandler is called by parseCircuit on each Variable found in the circuit
+type inputHandler func(name string, visibility backend.Visibility, v *Variable) error
+
+var tVariable = reflect.TypeOf(Variable{})
+
+// parseCircuit walks through the circuit struct and calls handler on each Variable
+func parseCircuit(circuit Circuit, handler inputHandler) error {
+	value := reflect.ValueOf(circuit)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return ErrInvalidCircuit
+	}
+	return parseValue("", backend.Secret, value.Elem(), handler)
+}
+
+func parseValue(name string, visibility backend.Visibility, value reflect.Value, handler inputHandler) error {
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() == tVariable {
+			if name == "" {
+				return fmt.Errorf("%w: a Variable must have a name", ErrInvalidTag)
+			}
+			return handler(name, visibility, value.Addr().Interface().(*Variable))
+		}
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			fieldName, fieldVisibility, skip, err := parseTag(field, visibility)
+			if err != nil {
+				return err
+			}
+			if skip {
+				continue
+			}
+			switch {
+			case fieldName == "":
+				fieldName = name
+			case name != "":
+				fieldName = name + "_" + fieldName
+			}
+			if err := parseValue(fieldName, fieldVisibility, value.Field(i), handler); err != nil {
+				return err
+			}
+		}
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if err := parseValue(name+"_"+strconv.Itoa(i), visibility, value.Index(i), handler); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		if !value.IsNil() {
+			return parseValue(name, visibility, value.Elem(), handler)
+		}
+	}
+	return nil
+}
+
+// parseTag returns the name and visibility of a struct field, from its gnark tag
+// fields without gnark tag are named after the field and inherit the visibility of their parent
+func parseTag(field reflect.StructField, parentVisibility backend.Visibility) (name string, visibility backend.Visibility, skip bool, err error) {
+	name, visibility = field.Name, parentVisibility
+
+	tag, ok := field.Tag.Lookup("gnark")
+	if !ok {
+		if field.Anonymous {
+			// embedded structs are flattened in their parent
+			return "", visibility, false, nil
+		}
+		// unexported fields without tag are ignored
+		return name, visibility, field.PkgPath != "", nil
+	}
+	if tag == "-" {
+		return "", "", true, nil
+	}
+	if field.PkgPath != "" {
+		return "", "", false, fmt.Errorf("%w: field %s is not exported", ErrInvalidTag, field.Name)
+	}
+
+	opts := strings.Split(tag, ",")
+	if n := strings.TrimSpace(opts[0]); n != "" {
+		name = n
+	}
+	for _, opt := range opts[1:] {
+		switch backend.Visibility(strings.ToLower(strings.TrimSpace(opt))) {
+		case backend.Secret:
+			visibility = backend.Secret
+		case backend.Public:
+			visibility = backend.Public
+		default:
+			return "", "", false, fmt.Errorf("%w: %q on field %s", ErrInvalidTag, tag, field.Name)
+		}
+	}
+
+	return name, visibility, false, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/stretchr/testify/require"
+)
+
+type pointCircuit struct {
+	X, Y Variable
+}
+
+type testCircuit struct {
+	A       Variable        `gnark:"a,secret"`
+	B       Variable        `gnark:",public"`
+	Points  [2]pointCircuit `gnark:"p,public"`
+	Ignored Variable        `gnark:"-"`
+	Secrets []Variable
+}
+
+func (circuit *testCircuit) Define(cs *CS) error {
+	sum := cs.ADD(circuit.A, circuit.Points[0].X, circuit.Points[1].Y)
+	for i := 0; i < len(circuit.Secrets); i++ {
+		sum = cs.ADD(sum, circuit.Secrets[i])
+	}
+	cs.MUSTBE_EQ(circuit.B, cs.MUL(sum, circuit.Points[0].Y))
+	return nil
+}
+
+func TestCompileCircuit(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit testCircuit
+	circuit.Secrets = make([]Variable, 3)
+
+	r1cs, err := Compile(&circuit)
+	assert.NoError(err)
+
+	assert.ElementsMatch([]string{"a", "Secrets_0", "Secrets_1", "Secrets_2"}, r1cs.PrivateWires)
+	assert.ElementsMatch([]string{backend.OneWire, "B", "p_0_X", "p_0_Y", "p_1_X", "p_1_Y"}, r1cs.PublicWires)
+	assert.Nil(circuit.Ignored.Constraint, "field tagged with - should not be allocated")
+}
+
+func TestToAssignment(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit testCircuit
+	circuit.A.Assign(1)
+	circuit.B.Assign(2)
+	for i := 0; i < len(circuit.Points); i++ {
+		circuit.Points[i].X.Assign(3)
+		circuit.Points[i].Y.Assign(4)
+	}
+	circuit.Secrets = make([]Variable, 1)
+
+	_, err := ToAssignment(&circuit)
+	assert.True(errors.Is(err, backend.ErrInputNotSet), "Secrets_0 is not assigned")
+
+	circuit.Secrets[0].Assign("42")
+	assignment, err := ToAssignment(&circuit)
+	assert.NoError(err, "Ignored is not assigned but should not be parsed")
+	assert.Len(assignment, 7)
+	assert.False(assignment["a"].IsPublic)
+	assert.True(assignment["p_1_Y"].IsPublic)
+	v := assignment["Secrets_0"].Value
+	assert.Equal("42", v.String())
+}
+
+func TestInvalidCircuit(t *testing.T) {
+	assert := require.New(t)
+
+	type badTag struct {
+		testCircuit
+		X Variable `gnark:"x,private"`
+	}
+	_, err := parseCircuitNames(&badTag{})
+	assert.True(errors.Is(err, ErrInvalidTag))
+
+	type duplicate struct {
+		testCircuit
+		X Variable `gnark:"a"`
+	}
+	_, err = Compile(&duplicate{})
+	assert.Error(err)
+}
+
+// parseCircuitNames returns the names of the inputs declared in a circuit
+func parseCircuitNames(circuit Circuit) ([]string, error) {
+	var names []string
+	err := parseCircuit(circuit, func(name string, _ backend.Visibility, _ *Variable) error {
+		names = append(names, name)
+		return nil
+	})
+	return names, err
+}
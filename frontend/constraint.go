@@ -18,6 +18,8 @@ package frontend
 
 import (
 	"math/big"
+
+	"github.com/consensys/gnark/backend"
 )
 
 // Constraint list of expressions that must be equal+an output wire, that can be computed out of the inputs wire.
@@ -31,6 +33,7 @@ type Constraint struct {
 	expressions  []expression
 	outputWire   *wire
 	constraintID uint64 // key in CS.Constraints[] map
+	namespace    string // path of the namespace in which the constraint was created (see CS.Namespace)
 }
 
 // Term coeff*constraint
@@ -51,17 +54,47 @@ func newConstraint(cs *CS, expressions ...expression) *Constraint {
 			WireID:       -1,
 		},
 		expressions: expressions,
+		namespace:   cs.namespace,
 	}
 
 	cs.addConstraint(toReturn)
 
+	if len(expressions) > 0 {
+		site := cs.callSite()
+		for _, e := range expressions {
+			cs.record(e, site)
+		}
+	}
+
+	if cs.engine != nil && len(expressions) > 0 {
+		cs.engine.compute(toReturn.outputWire, expressions[0])
+	}
+
 	return toReturn
 }
 
+// constantValue returns the value of c if it is set to a constant: the ONE_WIRE, or a Constraint whose
+// first expression is a constant (eg created by ALLOCATE, or by an operation on constants)
+func (c *Constraint) constantValue() (big.Int, bool) {
+	if c.outputWire.Name == backend.OneWire {
+		return bigOne(), true
+	}
+	if len(c.expressions) > 0 {
+		if e, ok := c.expressions[0].(*eqConstantExpression); ok {
+			return e.v, true
+		}
+	}
+	return big.Int{}, false
+}
+
 // Tag adds a tag to the constraint's singleWire
 // once the R1CS system is solved
 // r1cs.Inspect() may return a map[string]value of constraints with Tags
+// the tag is prefixed by the namespace in which the constraint was created (see CS.Namespace)
 func (c *Constraint) Tag(tag string) {
+	if c.namespace != "" {
+		tag = c.namespace + backend.NamespaceSeparator + tag
+	}
 	for i := 0; i < len(c.outputWire.Tags); i++ {
 		if c.outputWire.Tags[i] == tag {
 			return
@@ -70,13 +103,15 @@ func (c *Constraint) Tag(tag string) {
 	c.outputWire.Tags = append(c.outputWire.Tags, tag)
 }
 
-func (c *Constraint) toR1CS(s *CS) []R1C {
+func (c *Constraint) toR1CS(s *CS) ([]R1C, []string) {
 	oneWire := s.Constraints[0].outputWire
 
 	toReturn := make([]R1C, len(c.expressions))
+	callSites := make([]string, len(c.expressions))
 	for i := 0; i < len(c.expressions); i++ {
 		toReturn[i] = c.expressions[i].toR1CS(oneWire, c.outputWire)
+		callSites[i] = s.callSites[c.expressions[i]]
 	}
 
-	return toReturn
+	return toReturn, callSites
 }
@@ -45,6 +45,21 @@ type CS struct {
 
 	// keep track of the number of constraints (ensure each constraint has a unique ID)
 	nbConstraints uint64
+
+	// call site (file:line) of the API call which created each expression -- debug info
+	callSites map[expression]string
+
+	// path of the current namespace (see Namespace), and the namespace of the expressions defined
+	// out of the root namespace -- debug info
+	namespace  string
+	namespaces map[expression]string
+
+	// if set, the constraints are evaluated as they are defined (see NewTestEngine)
+	engine *testEngine
+
+	// if set, the errors of the API calls are recorded in errs instead of panicking (see NewSafe)
+	safe bool
+	errs []error
 }
 
 // New returns a new constraint system
@@ -52,6 +67,8 @@ func New() CS {
 	// initialize constraint system
 	cs := CS{
 		Constraints: make(map[uint64]*Constraint),
+		callSites:   make(map[expression]string),
+		namespaces:  make(map[expression]string),
 	}
 
 	// The first constraint corresponds to the declaration of
@@ -78,6 +95,24 @@ func (cs *CS) addConstraint(c *Constraint) {
 	cs.nbConstraints++
 }
 
+// addMOConstraint adds a constraint yielding multiple outputs, its output wires must be set
+func (cs *CS) addMOConstraint(e moExpression) {
+	cs.record(e, cs.callSite())
+	cs.MOConstraints = append(cs.MOConstraints, e)
+	if cs.engine != nil {
+		cs.engine.solve(e)
+	}
+}
+
+// addNOConstraint adds a constraint yielding no output
+func (cs *CS) addNOConstraint(e expression) {
+	cs.record(e, cs.callSite())
+	cs.NOConstraints = append(cs.NOConstraints, e)
+	if cs.engine != nil {
+		cs.engine.check(e)
+	}
+}
+
 // MUL multiplies two constraints
 func (cs *CS) mul(c1, c2 *Constraint) *Constraint {
 
@@ -266,6 +301,10 @@ func (cs *CS) equal(c1, c2 *Constraint) error {
 		}
 	}
 
+	if cs.engine != nil {
+		cs.engine.checkEqual(c1.outputWire, c2.outputWire)
+	}
+
 	// Since we copy c2's single wire into c1's, the order matters:
 	// if there is an input constraint, make sure it's c2's
 	if c2.outputWire != nil && c1.outputWire != nil {
@@ -274,6 +313,17 @@ func (cs *CS) equal(c1, c2 *Constraint) error {
 		}
 	}
 
+	// the expression computing the wire which is replaced becomes an assertion,
+	// it is reported at the MUSTBE_EQ call site
+	site := cs.callSite()
+	if c2.outputWire != nil && c2.outputWire.isUserInput() {
+		if len(c1.expressions) > 0 {
+			cs.record(c1.expressions[0], site)
+		}
+	} else if len(c2.expressions) > 0 {
+		cs.record(c2.expressions[0], site)
+	}
+
 	// Merge C1 constraints with C2's into C1
 	c1.expressions = append(c1.expressions, c2.expressions...)
 
@@ -282,6 +332,7 @@ func (cs *CS) equal(c1, c2 *Constraint) error {
 		wireToReplace := c1.outputWire
 
 		c2.outputWire.Tags = append(c2.outputWire.Tags, c1.outputWire.Tags...)
+		c2.outputWire.isBoolean = c2.outputWire.isBoolean || c1.outputWire.isBoolean
 		c1.outputWire = c2.outputWire
 
 		// replace all occurences of c1's single wire in all expressions by c2's single wire
@@ -317,30 +368,27 @@ func (cs *CS) equalConstant(c *Constraint, constant big.Int) error {
 		return fmt.Errorf("%w: %q", ErrInconsistantConstraint, "(user input == VALUE) is invalid")
 	}
 
-	c.expressions = append(c.expressions, &eqConstantExpression{v: constant})
+	if cs.engine != nil {
+		cs.engine.checkEqualConstant(c.outputWire, constant)
+	}
+
+	e := &eqConstantExpression{v: constant}
+	cs.record(e, cs.callSite())
+	c.expressions = append(c.expressions, e)
 
 	return nil
 }
 
 func (cs *CS) mustBeLessOrEqConstant(a *Constraint, constant big.Int, nbBits int) error {
 
-	// TODO assumes fr is alaws 256 bit long, should this elsewhere
-	ci := make([]int, nbBits)
-
-	// query the decomposition of constant, ensuring it's 256 bits long (this constant should set elsewhere)
-	words := constant.Bits()
-	if len(words) < 4 {
-		for i := 0; i < 4-len(words); i++ {
-			words = append(words, big.Word(0))
-		}
+	if constant.Sign() < 0 || constant.BitLen() > nbBits {
+		return fmt.Errorf("%w: %s on %d bits", ErrBoundTooLarge, constant.String(), nbBits)
 	}
-	nbWords := len(words)
 
-	for i := 0; i < nbWords; i++ {
-		for j := 0; j < 64; j++ {
-			// TODO fix me assumes big.Int.Word is 64 bits
-			ci[i*64+j] = int(uint64(words[i]) >> uint64(j) & uint64(1))
-		}
+	// decomposition of the constant on nbBits bits
+	ci := make([]int, nbBits)
+	for i := 0; i < nbBits; i++ {
+		ci[i] = int(constant.Bit(i))
 	}
 
 	// unpacking the Constraint c
@@ -363,7 +411,7 @@ func (cs *CS) mustBeLessOrEqConstant(a *Constraint, constant big.Int, nbBits int
 	for i := nbBits - 1; i >= 0; i-- {
 		if ci[i] == 0 {
 			constraintRes := &implyExpression{b: pi[i+1].outputWire, a: ai[i].outputWire}
-			cs.NOConstraints = append(cs.NOConstraints, constraintRes)
+			cs.addNOConstraint(constraintRes)
 		} else {
 			cs.MUSTBE_BOOLEAN(ai[i])
 		}
@@ -460,6 +508,55 @@ func (cs *CS) constVar(i1 interface{}) *Constraint {
 	return newConstraint(cs, &eqConstantExpression{v: constant})
 }
 
+// allocate returns the Constraint standing for i1, a Constraint or a constant, op being the API call using it
+func (cs *CS) allocate(op string, i1 interface{}) *Constraint {
+	switch x := cs.unwrap(op, i1).(type) {
+	case *Constraint:
+		return x
+	case Constraint:
+		return &x
+	default:
+		v, err := backend.ParseInterface(x)
+		if err != nil {
+			return cs.fail(op, err, x)
+		}
+		return cs.constVar(v)
+	}
+}
+
+// operand returns the value of i1 if it is a constant, that is a value handled by backend.FromInterface or a
+// Constraint set to a constant (eg by ALLOCATE), in which case the Constraint is also returned;
+// otherwise it returns the Constraint i1 stands for. operands are the operands of the API call op, for errors
+func (cs *CS) operand(op string, i1 interface{}, operands ...interface{}) (*Constraint, big.Int, bool) {
+	switch x := cs.unwrap(op, i1).(type) {
+	case *Constraint:
+		v, ok := x.constantValue()
+		return x, v, ok
+	case Constraint:
+		v, ok := x.constantValue()
+		return &x, v, ok
+	default:
+		v, err := backend.ParseInterface(x)
+		if err != nil {
+			return cs.fail(op, err, operands...), v, false
+		}
+		return nil, v, true
+	}
+}
+
+// bit returns the value of i1 if it is the constant 0 or 1, and the Constraint it stands for otherwise
+// (allocating the other constants, which can't be boolean constrained)
+func (cs *CS) bit(op string, i1 interface{}, operands ...interface{}) (*Constraint, int, bool) {
+	c, v, ok := cs.operand(op, i1, operands...)
+	if ok && v.IsInt64() && (v.Int64() == 0 || v.Int64() == 1) {
+		return nil, int(v.Int64()), true
+	}
+	if c == nil {
+		c = cs.constVar(v)
+	}
+	return c, 0, false
+}
+
 // util function to count the wires of a constraint system
 func (cs *CS) countWires() int {
 
@@ -17,180 +17,188 @@ limitations under the License.
 package frontend
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/consensys/gnark/backend"
 )
 
 // ADD Adds 2+ inputs and returns resulting Constraint
+// the constant operands are added at compile time, if all of them are constants the result is a constant
 func (cs *CS) ADD(i1, i2 interface{}, in ...interface{}) *Constraint {
-
-	// can add constraint and constants
-	add := func(_i1, _i2 interface{}) *Constraint {
-		switch c1 := _i1.(type) {
-		case *Constraint:
-			switch c2 := _i2.(type) {
-			case *Constraint:
-				return cs.add(c1, c2)
-			default:
-				return cs.addConstant(c1, backend.FromInterface(c2))
-			}
+	operands := append([]interface{}{i1, i2}, in...)
+
+	res, k, isConstant := cs.operand("ADD", i1, operands...)
+	for _, i := range operands[1:] {
+		c, v, ok := cs.operand("ADD", i, operands...)
+		switch {
+		case isConstant && ok:
+			var sum big.Int
+			k = *sum.Add(&k, &v)
+		case isConstant:
+			res, isConstant = cs.addConstant(c, k), false
+		case ok:
+			res = cs.addConstant(res, v)
 		default:
-			switch c2 := _i2.(type) {
-			case *Constraint:
-				return cs.addConstant(c2, backend.FromInterface(c1))
-			default:
-				panic("invalid type")
-			}
+			res = cs.add(res, c)
 		}
 	}
 
-	res := add(i1, i2)
-
-	for i := 0; i < len(in); i++ {
-		res = add(res, in[i])
+	if isConstant {
+		return cs.constVar(k)
 	}
-
 	return res
 }
 
 // SUB Adds two constraints
+// if both operands are constants the result is a constant
 func (cs *CS) SUB(i1, i2 interface{}) *Constraint {
-	switch c1 := i1.(type) {
-	case *Constraint:
-		switch c2 := i2.(type) {
-		case *Constraint:
-			return cs.sub(c1, c2)
-		case big.Int:
-			return cs.subConstant(c1, c2)
-		}
+	c1, v1, ok1 := cs.operand("SUB", i1, i1, i2)
+	c2, v2, ok2 := cs.operand("SUB", i2, i1, i2)
+	switch {
+	case ok1 && ok2:
+		var diff big.Int
+		return cs.constVar(*diff.Sub(&v1, &v2))
+	case ok1:
+		return cs.subConstraint(v1, c2)
+	case ok2:
+		return cs.subConstant(c1, v2)
 	default:
-		_c1 := backend.FromInterface(c1)
-		switch c2 := i2.(type) {
-		case *Constraint:
-			return cs.subConstraint(_c1, c2)
-		}
+		return cs.sub(c1, c2)
 	}
-	panic("invalid type")
 }
 
 // MUL Multiplies 2+ constraints together
+// the constant operands are multiplied at compile time, if all of them are constants the result is a constant
 func (cs *CS) MUL(i1, i2 interface{}, in ...interface{}) *Constraint {
 
-	// multiplies 2 terms (constraints, Elements, uint64, int, String)
-	mul := func(_i1, _i2 interface{}) *Constraint {
-		switch c1 := _i1.(type) {
-		case LinearCombination:
-			switch c2 := _i2.(type) {
-			case LinearCombination:
-				return cs.mullc(c1, c2)
-			default:
-				panic("invalid type; only support linear expression MUL linear expression")
-			}
-		case *Constraint:
-			switch c2 := _i2.(type) {
-			case *Constraint:
-				return cs.mul(c1, c2)
-			default:
-				return cs.mulConstant(c1, backend.FromInterface(c2))
-			}
-		default: // i1 is not a Constraint type, so c2 must be
-			switch c2 := _i2.(type) {
-			case *Constraint:
-				return cs.mulConstant(c2, backend.FromInterface(c1))
-			default:
-				panic("invalid type")
-			}
-
+	// only linear expression MUL linear expression is supported
+	if lc1, ok := i1.(LinearCombination); ok {
+		if lc2, ok := i2.(LinearCombination); ok && len(in) == 0 {
+			return cs.mullc(lc1, lc2)
 		}
+		return cs.fail("MUL", ErrInvalidOperand, append([]interface{}{i1, i2}, in...)...)
+	}
+	if _, ok := i2.(LinearCombination); ok {
+		return cs.fail("MUL", ErrInvalidOperand, append([]interface{}{i1, i2}, in...)...)
 	}
 
-	res := mul(i1, i2)
-
-	for i := 0; i < len(in); i++ {
-		res = mul(res, in[i])
+	operands := append([]interface{}{i1, i2}, in...)
+
+	res, k, isConstant := cs.operand("MUL", i1, operands...)
+	for _, i := range operands[1:] {
+		c, v, ok := cs.operand("MUL", i, operands...)
+		switch {
+		case isConstant && ok:
+			var prod big.Int
+			k = *prod.Mul(&k, &v)
+		case isConstant:
+			res, isConstant = cs.mulConstant(c, k), false
+		case ok:
+			res = cs.mulConstant(res, v)
+		default:
+			res = cs.mul(res, c)
+		}
 	}
 
+	if isConstant {
+		return cs.constVar(k)
+	}
 	return res
 
 }
 
 // DIV divides two constraints (i1/i2)
+// if both operands are constants and i2 divides i1, the result is a constant
 func (cs *CS) DIV(i1, i2 interface{}) *Constraint {
 
-	div := func(_i1, _i2 interface{}) *Constraint {
-		switch c1 := _i1.(type) {
-		case LinearCombination:
-			switch c2 := _i2.(type) {
-			case LinearCombination:
-				return cs.divlc(c1, c2)
-			default:
-				panic("invalid type; only support linear expression DIV linear expression")
-			}
-		case *Constraint:
-			switch c2 := _i2.(type) {
-			case *Constraint:
-				return cs.div(c1, c2)
-			default:
-				tmp := backend.FromInterface(c2)
-				return cs.divConstantRight(c1, tmp)
-			}
-		default: // i1 is not a Constraint type, so c2 must be
-			switch c2 := _i2.(type) {
-			case *Constraint:
-				tmp := backend.FromInterface(c1)
-				return cs.divConstantLeft(tmp, c2)
-			default:
-				panic("invalid type")
-			}
+	// only linear expression DIV linear expression is supported
+	lc1, ok1 := i1.(LinearCombination)
+	lc2, ok2 := i2.(LinearCombination)
+	if ok1 && ok2 {
+		return cs.divlc(lc1, lc2)
+	}
+	if ok1 || ok2 {
+		return cs.fail("DIV", ErrInvalidOperand, i1, i2)
+	}
 
+	c1, v1, ok1 := cs.operand("DIV", i1, i1, i2)
+	c2, v2, ok2 := cs.operand("DIV", i2, i1, i2)
+	switch {
+	case ok1 && ok2:
+		// the modulus being unknown, only an exact division can be computed at compile time
+		var q, r big.Int
+		if v2.Sign() != 0 {
+			if q.QuoRem(&v1, &v2, &r); r.Sign() == 0 {
+				return cs.constVar(q)
+			}
 		}
+		return cs.divConstantRight(cs.constVar(v1), v2)
+	case ok1:
+		return cs.divConstantLeft(v1, c2)
+	case ok2:
+		return cs.divConstantRight(c1, v2)
+	default:
+		return cs.div(c1, c2)
 	}
 
-	res := div(i1, i2)
-
-	return res
-
 }
 
 // MUSTBE_EQ equalizes two constraints
+// two constants are compared at compile time modulo the modulus of the test engine, or when solving the R1CS
 func (cs *CS) MUSTBE_EQ(i1, i2 interface{}) {
-
-	switch c1 := i1.(type) {
-	case *Constraint:
-		switch c2 := i2.(type) {
-		case *Constraint:
-			if err := cs.equal(c1, c2); err != nil {
-				panic(err)
-			}
-			return
-		case big.Int: // TODO handle *big.Int ?
-			if err := cs.equalConstant(c1, c2); err != nil {
-				panic(err)
-			}
-			return
+	c1, v1, ok1 := cs.operand("MUSTBE_EQ", i1, i1, i2)
+	c2, v2, ok2 := cs.operand("MUSTBE_EQ", i2, i1, i2)
+
+	var err error
+	switch {
+	case ok1 && ok2 && cs.engine != nil:
+		// the constants are compared in the field of the test engine (eg -1 == p-1)
+		v1.Mod(&v1, &cs.engine.modulus)
+		v2.Mod(&v2, &cs.engine.modulus)
+		if v1.Cmp(&v2) != 0 {
+			err = fmt.Errorf("%w: %q", ErrInconsistantConstraint, v1.String()+" == "+v2.String()+" is invalid")
 		}
-	case big.Int: // TODO handle *big.Int ?
-		switch c2 := i2.(type) {
-		case *Constraint:
-			if err := cs.equalConstant(c2, c1); err != nil {
-				panic(err)
-			}
-			return
+	case ok1 && ok2:
+		// the modulus being unknown, constants which differ may still be equal in the field (eg -1 and p-1):
+		// the assertion is checked when solving the R1CS
+		if v1.Cmp(&v2) != 0 {
+			err = cs.equalConstant(newConstraint(cs, &eqConstantExpression{v: v1}), v2)
 		}
+	case ok1:
+		// a Constraint set to a constant (eg the ONE wire) is not merged, it is folded as the constant
+		err = cs.equalConstant(c2, v1)
+	case ok2:
+		err = cs.equalConstant(c1, v2)
+	default:
+		err = cs.equal(c1, c2)
+	}
+	if err != nil {
+		cs.fail("MUSTBE_EQ", err, i1, i2)
 	}
-
-	panic("invalid type")
 
 }
 
 // INV inverse a Constraint
-func (cs *CS) INV(c1 *Constraint) *Constraint {
-	return cs.inv(c1, bigOne())
+func (cs *CS) INV(i1 interface{}) *Constraint {
+	return cs.inv(cs.allocate("INV", i1), bigOne())
 }
 
 // XOR compute the xor between two constraints
-func (cs *CS) XOR(c1, c2 *Constraint) *Constraint {
+// if both operands are constants the result is a constant
+func (cs *CS) XOR(i1, i2 interface{}) *Constraint {
+	c1, b1, ok1 := cs.bit("XOR", i1, i1, i2)
+	c2, b2, ok2 := cs.bit("XOR", i2, i1, i2)
+	if ok1 && ok2 {
+		return cs.constVar(b1 ^ b2)
+	}
+	if ok1 {
+		c1 = cs.constVar(b1)
+	}
+	if ok2 {
+		c2 = cs.constVar(b2)
+	}
+
 	// ensure c1 and c2 are already boolean constrained
 	cs.MUSTBE_BOOLEAN(c1)
 	cs.MUSTBE_BOOLEAN(c2)
@@ -200,61 +208,122 @@ func (cs *CS) XOR(c1, c2 *Constraint) *Constraint {
 		b: c2.outputWire,
 	}
 
-	return newConstraint(cs, &expression)
+	// a xor b == c --> c is automatically boolean constrained
+	res := newConstraint(cs, &expression)
+	res.outputWire.isBoolean = true
+
+	return res
+}
+
+// AND compute the and between two constraints
+// if both operands are constants the result is a constant
+func (cs *CS) AND(i1, i2 interface{}) *Constraint {
+	c1, b1, ok1 := cs.bit("AND", i1, i1, i2)
+	c2, b2, ok2 := cs.bit("AND", i2, i1, i2)
+	if ok1 && ok2 {
+		return cs.constVar(b1 & b2)
+	}
+	if ok1 {
+		c1 = cs.constVar(b1)
+	}
+	if ok2 {
+		c2 = cs.constVar(b2)
+	}
+
+	// ensure c1 and c2 are already boolean constrained
+	cs.MUSTBE_BOOLEAN(c1)
+	cs.MUSTBE_BOOLEAN(c2)
+
+	// a and b == a * b --> automatically boolean constrained
+	res := cs.mul(c1, c2)
+	res.outputWire.isBoolean = true
+
+	return res
+}
+
+// NOT compute the negation of a constraint
+// if the operand is a constant the result is a constant
+func (cs *CS) NOT(i1 interface{}) *Constraint {
+	c, b, ok := cs.bit("NOT", i1, i1)
+	if ok {
+		return cs.constVar(1 - b)
+	}
+
+	// ensure c is already boolean constrained
+	cs.MUSTBE_BOOLEAN(c)
+
+	// not a == 1 - a --> automatically boolean constrained
+	res := cs.subConstraint(bigOne(), c)
+	res.outputWire.isBoolean = true
+
+	return res
 }
 
 // MUSTBE_BOOLEAN boolean constrains a variable
-func (cs *CS) MUSTBE_BOOLEAN(c *Constraint) {
-	// check if the variable is already boolean constrained
-	for i := 0; i < len(cs.NOConstraints); i++ {
-		if bExpression, ok := cs.NOConstraints[i].(*booleanExpression); ok {
-			if bExpression.b == c.outputWire {
-				// this variable is already boolean constrained
-				return
-			}
-		}
+// it is a no-op on the constants 0 and 1
+func (cs *CS) MUSTBE_BOOLEAN(i1 interface{}) {
+	c, _, ok := cs.bit("MUSTBE_BOOLEAN", i1, i1)
+	if ok {
+		return
 	}
-	// check if the variable is the result of a XOR (a xor b == c --> c is automatically boolean constrained)
-	for _, constraint := range cs.Constraints {
-		if constraint == c {
-			for i := 0; i < len(constraint.expressions); i++ {
-				if _, ok := constraint.expressions[i].(*xorExpression); ok {
-					// constraint is the result of a xor expression and is already boolean constrained as such
-					return
-				}
-			}
-		}
+
+	// check if the variable is already boolean constrained
+	if c.outputWire.isBoolean {
+		return
 	}
-	cs.NOConstraints = append(cs.NOConstraints, &booleanExpression{b: c.outputWire})
+	c.outputWire.isBoolean = true
+	cs.addNOConstraint(&booleanExpression{b: c.outputWire})
 }
 
 // TO_BINARY unpacks a variable in binary, n is the number of bits of the variable
 // The result in in little endian (first bit= lsb)
-func (cs *CS) TO_BINARY(c *Constraint, nbBits int) []*Constraint {
+func (cs *CS) TO_BINARY(i1 interface{}, nbBits int) []*Constraint {
+	c := cs.allocate("TO_BINARY", i1)
 
 	// create the expression ensuring the bit decomposition matches c
 	expression := &unpackExpression{
 		res: c.outputWire,
 	}
-	cs.MOConstraints = append(cs.MOConstraints, expression)
 
 	// create our bits constraints
 	bits := make([]*Constraint, nbBits)
 	for i := 0; i < nbBits; i++ {
 		bits[i] = newConstraint(cs)
-		cs.MUSTBE_BOOLEAN(bits[i]) // (MUSTBE_BOOLEAN check for duplicate constraints)
 		expression.bits = append(expression.bits, bits[i].outputWire)
 	}
+	cs.addMOConstraint(expression)
+	for i := 0; i < nbBits; i++ {
+		cs.MUSTBE_BOOLEAN(bits[i]) // (MUSTBE_BOOLEAN check for duplicate constraints)
+	}
 
 	return bits
 }
 
 // FROM_BINARY packs b, seen as a fr.Element in little endian
-func (cs *CS) FROM_BINARY(b ...*Constraint) *Constraint {
+// if all the bits are constants the result is a constant
+func (cs *CS) FROM_BINARY(b ...interface{}) *Constraint {
+	constraints := make([]*Constraint, len(b))
+	var constant big.Int
+	isConstant := true
+	for i := range b {
+		c, v, ok := cs.bit("FROM_BINARY", b[i], b...)
+		if ok {
+			constant.SetBit(&constant, i, uint(v))
+		} else {
+			isConstant = false
+		}
+		constraints[i] = c
+	}
+	if isConstant {
+		return cs.constVar(constant)
+	}
 
 	expression := packExpression{}
 
-	for _, c := range b {
+	for i, c := range constraints {
+		if c == nil {
+			c = cs.constVar(int(constant.Bit(i)))
+		}
 		cs.MUSTBE_BOOLEAN(c) // ensure input is boolean constrained
 		expression.bits = append(expression.bits, c.outputWire)
 	}
@@ -264,51 +333,166 @@ func (cs *CS) FROM_BINARY(b ...*Constraint) *Constraint {
 
 // MUSTBE_LESS_OR_EQ constrains c to be less or equal than e (taken as lifted Integer values from Fr)
 // from https://github.com/zcash/zips/blob/master/protocol/protocol.pdf
-func (cs *CS) MUSTBE_LESS_OR_EQ(c *Constraint, bound interface{}, nbBits int) {
+// c and a constant bound must fit on nbBits bits
+func (cs *CS) MUSTBE_LESS_OR_EQ(i1 interface{}, bound interface{}, nbBits int) {
+	c := cs.allocate("MUSTBE_LESS_OR_EQ", i1)
 
-	switch _bound := bound.(type) {
-	case *Constraint:
+	if _bound, b, ok := cs.operand("MUSTBE_LESS_OR_EQ", bound, i1, bound); ok {
+		if err := cs.mustBeLessOrEqConstant(c, b, nbBits); err != nil {
+			cs.fail("MUSTBE_LESS_OR_EQ", err, i1, bound)
+		}
+	} else {
 		cs.mustBeLessOrEq(c, _bound, nbBits)
-	default:
-		b := backend.FromInterface(bound)
-		cs.mustBeLessOrEqConstant(c, b, nbBits)
 	}
 }
 
+// MUSTBE_IN_RANGE constrains c to fit on nbBits bits (0 <= c < 2**nbBits), by unpacking it
+func (cs *CS) MUSTBE_IN_RANGE(i1 interface{}, nbBits int) {
+	cs.TO_BINARY(i1, nbBits)
+}
+
+// IS_ZERO returns a boolean constraint set to 1 if c is 0, 0 otherwise
+func (cs *CS) IS_ZERO(i1 interface{}) *Constraint {
+	c := cs.allocate("IS_ZERO", i1)
+
+	// c*m = 1-res, where m is the inverse of c (or 0) is a hint computed by the solver,
+	// and c*res = 0 --> res is automatically boolean constrained
+	m := newConstraint(cs)
+	res := newConstraint(cs)
+	res.outputWire.isBoolean = true
+
+	expression := &isZeroExpression{
+		x:   c.outputWire,
+		m:   m.outputWire,
+		res: res.outputWire,
+	}
+	cs.addMOConstraint(expression)
+	cs.addNOConstraint(&zeroProductExpression{a: c.outputWire, b: res.outputWire})
+
+	return res
+}
+
+// IS_EQUAL returns a boolean constraint set to 1 if i1 == i2, 0 otherwise
+func (cs *CS) IS_EQUAL(i1, i2 interface{}) *Constraint {
+	return cs.IS_ZERO(cs.SUB(cs.ALLOCATE(i1), cs.ALLOCATE(i2)))
+}
+
+// IS_LESS returns a boolean constraint set to 1 if i1 < i2, 0 otherwise (taken as lifted Integer values from Fr).
+// i1 and i2 must fit on nbBits bits (see MUSTBE_IN_RANGE), nbBits+1 being less than the size of Fr
+func (cs *CS) IS_LESS(i1, i2 interface{}, nbBits int) *Constraint {
+
+	// i1 - i2 + 2**nbBits fits on nbBits+1 bits, and its most significant bit is set iff i1 >= i2
+	var shift big.Int
+	shift.Lsh(big.NewInt(1), uint(nbBits))
+	lc := LinearCombination{
+		Term{cs.ALLOCATE(i1), bigOne()},
+		Term{cs.ALLOCATE(i2), *big.NewInt(-1)},
+		Term{cs.Constraints[0], shift},
+	}
+	diff := cs.MUL(lc, LinearCombination{Term{cs.Constraints[0], bigOne()}})
+
+	bits := cs.TO_BINARY(diff, nbBits+1)
+
+	return cs.NOT(bits[nbBits])
+}
+
+// CMP returns a constraint set to -1 if i1 < i2, 0 if i1 == i2 and 1 if i1 > i2 (taken as lifted Integer values from Fr).
+// i1 and i2 must fit on nbBits bits (see MUSTBE_IN_RANGE), nbBits+1 being less than the size of Fr
+func (cs *CS) CMP(i1, i2 interface{}, nbBits int) *Constraint {
+
+	isLess := cs.IS_LESS(i1, i2, nbBits)
+	isEqual := cs.IS_EQUAL(i1, i2)
+
+	// 1 - 2*isLess - isEqual
+	lc := LinearCombination{
+		Term{cs.Constraints[0], bigOne()},
+		Term{isLess, *big.NewInt(-2)},
+		Term{isEqual, *big.NewInt(-1)},
+	}
+	return cs.MUL(lc, LinearCombination{Term{cs.Constraints[0], bigOne()}})
+}
+
+// HINT returns nbOutputs new variables, computed when the R1CS is solved by the function registered with
+// the identifier id (see RegisterHint) from the values of inputs. The outputs are not constrained: the circuit
+// must check them, eg the square root s of x computed by a hint with cs.MUSTBE_EQ(cs.MUL(s, s), x)
+func (cs *CS) HINT(id HintID, nbOutputs int, inputs ...interface{}) []*Constraint {
+	outputs := make([]*Constraint, nbOutputs)
+	for i := range outputs {
+		outputs[i] = newConstraint(cs)
+	}
+	if _, ok := LookupHint(id); !ok {
+		cs.fail("HINT", fmt.Errorf("%w: %d", ErrUnknownHint, id), id)
+		return outputs
+	}
+
+	expression := &hintExpression{id: id}
+	for _, input := range inputs {
+		expression.inputs = append(expression.inputs, cs.allocate("HINT", input).outputWire)
+	}
+	for _, c := range outputs {
+		expression.outputs = append(expression.outputs, c.outputWire)
+	}
+	cs.addMOConstraint(expression)
+
+	return outputs
+}
+
 // SELECT if b is true, yields c1 else yields c2
-func (cs *CS) SELECT(b *Constraint, i1, i2 interface{}) *Constraint {
+// if b is a constant, the selected operand is returned
+func (cs *CS) SELECT(b interface{}, i1, i2 interface{}) *Constraint {
+	cb, bv, bok := cs.bit("SELECT", b, b, i1, i2)
+	if bok {
+		if bv == 1 {
+			return cs.allocate("SELECT", i1)
+		}
+		return cs.allocate("SELECT", i2)
+	}
 
 	// ensure b is boolean constrained
-	cs.MUSTBE_BOOLEAN(b)
-
-	switch c1 := i1.(type) {
-	case *Constraint:
-		switch c2 := i2.(type) {
-		case *Constraint:
-			expression := selectExpression{
-				b: b.outputWire,
-				x: c1.outputWire,
-				y: c2.outputWire,
-			}
-			return newConstraint(cs, &expression)
-		default:
-			panic("invalid type")
-		}
-	default:
-		c1Bigint := backend.FromInterface(i1)
-		c2Bigint := backend.FromInterface(i2)
-		c1Bigint.Sub(&c1Bigint, &c2Bigint)
+	cs.MUSTBE_BOOLEAN(cb)
+
+	c1, v1, ok1 := cs.operand("SELECT", i1, b, i1, i2)
+	c2, v2, ok2 := cs.operand("SELECT", i2, b, i1, i2)
+	if ok1 && ok2 {
+		// b*(v1-v2) + v2
+		var diff big.Int
+		diff.Sub(&v1, &v2)
 		expression := linearExpression{
-			term{Wire: b.outputWire, Coeff: c1Bigint, Operation: mul},
-			term{Wire: cs.Constraints[0].outputWire, Coeff: bigOne(), Operation: mul},
+			term{Wire: cb.outputWire, Coeff: diff, Operation: mul},
+			term{Wire: cs.Constraints[0].outputWire, Coeff: v2, Operation: mul},
 		}
 		return newConstraint(cs, &expression)
 	}
+	if c1 == nil {
+		c1 = cs.constVar(v1)
+	}
+	if c2 == nil {
+		c2 = cs.constVar(v2)
+	}
+
+	expression := selectExpression{
+		b: cb.outputWire,
+		x: c1.outputWire,
+		y: c2.outputWire,
+	}
+	return newConstraint(cs, &expression)
 }
 
 // SELECT_LUT select lookuptable[c1*2+c0] where c0 and c1 are boolean constrained
 // cf https://z.cash/technology/jubjub/
-func (cs *CS) SELECT_LUT(c1, c0 *Constraint, lookuptable [4]big.Int) *Constraint {
+// if c0 and c1 are constants, the selected entry is returned as a constant
+func (cs *CS) SELECT_LUT(i1, i0 interface{}, lookuptable [4]big.Int) *Constraint {
+	c1, b1, ok1 := cs.bit("SELECT_LUT", i1, i1, i0)
+	c0, b0, ok0 := cs.bit("SELECT_LUT", i0, i1, i0)
+	if ok1 && ok0 {
+		return cs.constVar(lookuptable[b1*2+b0])
+	}
+	if ok1 {
+		c1 = cs.constVar(b1)
+	}
+	if ok0 {
+		c0 = cs.constVar(b0)
+	}
 
 	// ensure c0 and c1 are boolean constrained
 	cs.MUSTBE_BOOLEAN(c0)
@@ -325,42 +509,53 @@ func (cs *CS) SELECT_LUT(c1, c0 *Constraint, lookuptable [4]big.Int) *Constraint
 }
 
 // SECRET_INPUT creates a Constraint containing an input
+// its name is prefixed by the current namespace (see Namespace)
 func (cs *CS) SECRET_INPUT(name string) *Constraint {
+	name = cs.scoped(name)
+
 	// checks if the name already exists
 	if !cs.registerNamedInput(name) {
-		panic("input " + name + " already declared")
+		cs.fail("SECRET_INPUT", fmt.Errorf("%w: %s", ErrDuplicateInput, name), name)
 	}
 
+	return cs.newInput(name, backend.Secret)
+}
+
+// PUBLIC_INPUT creates a Constraint containing an input
+// its name is prefixed by the current namespace (see Namespace)
+func (cs *CS) PUBLIC_INPUT(name string) *Constraint {
+	name = cs.scoped(name)
+
+	// checks if the name already exists
+	if !cs.registerNamedInput(name) {
+		cs.fail("PUBLIC_INPUT", fmt.Errorf("%w: %s", ErrDuplicateInput, name), name)
+	}
+
+	return cs.newInput(name, backend.Public)
+}
+
+func (cs *CS) newInput(name string, visibility backend.Visibility) *Constraint {
 	toReturn := &Constraint{
 		outputWire: &wire{
 			Name:         name,
 			Tags:         []string{},
-			IsPrivate:    true,
+			IsPrivate:    visibility == backend.Secret,
 			IsConsumed:   true,
 			ConstraintID: -1,
 			WireID:       -1,
-		}}
+		},
+		namespace: cs.namespace,
+	}
 	cs.addConstraint(toReturn)
 
-	return toReturn
-
-}
+	if cs.engine != nil {
+		cs.engine.setInput(toReturn.outputWire, visibility)
+	}
 
-// PUBLIC_INPUT creates a Constraint containing an input
-func (cs *CS) PUBLIC_INPUT(name string) *Constraint {
-	toReturn := cs.SECRET_INPUT(name)
-	toReturn.outputWire.IsPrivate = false
 	return toReturn
 }
 
 // ALLOCATE will return an allocated cs.Constraint from input {Constraint, element, uint64, int, ...}
 func (cs *CS) ALLOCATE(input interface{}) *Constraint {
-	switch x := input.(type) {
-	case *Constraint:
-		return x
-	case Constraint:
-		return &x
-	default:
-		return cs.constVar(x)
-	}
+	return cs.allocate("ALLOCATE", input)
 }
@@ -0,0 +1,141 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidOperand      = errors.New("invalid operand type")
+	ErrDuplicateInput      = errors.New("input already declared")
+	ErrUnallocatedVariable = errors.New("variable is not allocated, circuit must be compiled with frontend.Compile")
+	ErrBoundTooLarge       = errors.New("bound doesn't fit on the number of bits")
+)
+
+// APIError is an error of a call to the API of a constraint system
+//
+// A constraint system created by New panics with it; one created by NewSafe records it, the
+// call returning an unconstrained Constraint so that the circuit definition can go on, and
+// Compile returns all the recorded errors
+type APIError struct {
+	Op        string   // the API method, eg "MUL"
+	Operands  []string // the types of the operands
+	CallSite  string   // file:line of the call
+	Namespace string   // path of the namespace of the call (see CS.Namespace), "" at the root
+	Err       error    // ErrInvalidOperand, ErrDuplicateInput, ErrUnallocatedVariable, ErrInconsistantConstraint, ErrUnknownHint or backend.ErrInvalidType
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s(%s): %v at %s", e.Op, strings.Join(e.Operands, ", "), e.Err, inNamespace(e.CallSite, e.Namespace))
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidOperand)
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList errors recorded by a constraint system created by NewSafe
+type ErrorList []error
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, err := range l {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d error(s) in the constraint system:\n%s", len(l), strings.Join(msgs, "\n"))
+}
+
+// Is returns true if one of the errors of the list is target (as errors.Is)
+func (l ErrorList) Is(target error) bool {
+	for _, err := range l {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSafe returns a new constraint system which records the errors of the API calls instead of
+// panicking (see APIError), they are returned by Compile
+func NewSafe() CS {
+	cs := New()
+	cs.safe = true
+	return cs
+}
+
+// Errors returns the errors recorded by the constraint system (always nil if it was not created by NewSafe)
+func (cs *CS) Errors() []error {
+	return cs.errs
+}
+
+// Compile returns the R1CS of the constraint system, or the errors it recorded as an ErrorList
+func (cs *CS) Compile() (*R1CS, error) {
+	if len(cs.errs) > 0 {
+		return nil, ErrorList(cs.errs)
+	}
+	return cs.ToR1CS(), nil
+}
+
+// fail reports the error err of the API call op: it panics, or records the error if cs was created
+// by NewSafe and returns an unconstrained Constraint standing for the result of the call
+func (cs *CS) fail(op string, err error, operands ...interface{}) *Constraint {
+	apiErr := &APIError{
+		Op:        op,
+		Operands:  make([]string, len(operands)),
+		CallSite:  callSite(),
+		Namespace: cs.namespace,
+		Err:       err,
+	}
+	for i, o := range operands {
+		apiErr.Operands[i] = operandType(o)
+	}
+	if !cs.safe {
+		panic(apiErr)
+	}
+	cs.errs = append(cs.errs, apiErr)
+	return newConstraint(cs)
+}
+
+// operandType returns the type of an operand, for error messages
+func operandType(o interface{}) string {
+	switch v := o.(type) {
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case nil:
+		return "nil"
+	default:
+		return fmt.Sprintf("%T", o)
+	}
+}
+
+// unwrap returns the Constraint embedded in a Variable, or i1 otherwise
+// the Variable must be allocated, op being the API call using it
+func (cs *CS) unwrap(op string, i1 interface{}) interface{} {
+	switch v := i1.(type) {
+	case Variable:
+		if v.Constraint == nil {
+			return cs.fail(op, ErrUnallocatedVariable, i1)
+		}
+		return v.Constraint
+	case *Variable:
+		return cs.unwrap(op, *v)
+	}
+	return i1
+}
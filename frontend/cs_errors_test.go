@@ -0,0 +1,102 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIErrorPanics(t *testing.T) {
+	assert := require.New(t)
+
+	defer func() {
+		r := recover()
+		err, ok := r.(*APIError)
+		assert.True(ok, "expected an *APIError, got %v", r)
+		assert.True(errors.Is(err, ErrInvalidOperand))
+		assert.Equal("MUL", err.Op)
+		assert.Equal([]string{"frontend.LinearCombination", "int"}, err.Operands)
+	}()
+
+	cs := New()
+	cs.MUL(LinearCombination{}, 2)
+}
+
+func TestAPIErrorsRecorded(t *testing.T) {
+	assert := require.New(t)
+
+	cs := NewSafe()
+	x := cs.SECRET_INPUT("x")
+	cs.SECRET_INPUT("x")
+	cs.MUL(LinearCombination{Term{x, bigOne()}}, 2)
+	cs.ADD(x, "not a number")
+	cs.MUSTBE_EQ(x, 0)
+	cs.MUSTBE_EQ(x, x)
+	cs.SELECT(cs.TO_BINARY(x, 2)[0], Variable{}, x)
+	cs.MUSTBE_LESS_OR_EQ(x, 5, 8)
+	cs.MUSTBE_LESS_OR_EQ(x, 256, 8)
+
+	expected := []error{
+		ErrDuplicateInput,
+		ErrInvalidOperand,
+		backend.ErrInvalidType,
+		ErrInconsistantConstraint,
+		ErrInconsistantConstraint,
+		ErrUnallocatedVariable,
+		ErrBoundTooLarge,
+	}
+	assert.Equal(len(expected), len(cs.Errors()))
+	for i, err := range cs.Errors() {
+		assert.True(errors.Is(err, expected[i]), "expected %v, got %v", expected[i], err)
+		assert.True(strings.Contains(err.(*APIError).CallSite, "cs_errors_test.go"), err.Error())
+	}
+
+	r1cs, err := cs.Compile()
+	assert.Nil(r1cs)
+	assert.True(errors.Is(err, ErrUnallocatedVariable))
+	assert.False(errors.Is(err, ErrInvalidCircuit))
+	assert.Equal(len(expected), len(err.(ErrorList)))
+
+	// without errors, the constraint system compiles
+	cs = NewSafe()
+	cs.MUSTBE_EQ(cs.MUL(cs.SECRET_INPUT("x"), 3), cs.PUBLIC_INPUT("y"))
+	r1cs, err = cs.Compile()
+	assert.NoError(err)
+	assert.Equal(3, r1cs.NbPublicWires+r1cs.NbPrivateWires)
+}
+
+type invalidCircuit struct {
+	X Variable
+}
+
+func (circuit *invalidCircuit) Define(cs *CS) error {
+	cs.MUSTBE_EQ(circuit.X, cs.DIV(LinearCombination{}, 3))
+	return nil
+}
+
+func TestCompileAPIErrors(t *testing.T) {
+	var circuit invalidCircuit
+	_, err := Compile(&circuit)
+	if !errors.Is(err, ErrInvalidOperand) {
+		t.Fatal("expected", ErrInvalidOperand, "got", err)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+)
+
+var (
+	ErrInvalidNamespace = errors.New("a namespace must have a name")
+)
+
+// Namespace calls define in the namespace name, nested in the current one: the names of the inputs
+// (SECRET_INPUT, PUBLIC_INPUT) and the tags of the constraints created by define are prefixed by the
+// path of the namespace, eg "transfer/sender/pubkey_x" (see backend.NamespaceSeparator), such that
+// a component can be used several times in a circuit. The call sites of the constraints and of the
+// errors report the namespace, and Stats counts its constraints.
+//
+// It returns the error returned by define, the namespace being closed in any case
+func (cs *CS) Namespace(name string, define func(cs *CS) error) error {
+	if name == "" {
+		return ErrInvalidNamespace
+	}
+
+	parent := cs.namespace
+	cs.setNamespace(cs.scoped(name))
+	defer cs.setNamespace(parent)
+
+	return define(cs)
+}
+
+// SubCircuit allocates the inputs of circuit (see Compile) and calls its Define method, in the namespace
+// name (see Namespace). The witness of the sub-circuit can be assigned with backend.Assignments.AssignNamespace
+func (cs *CS) SubCircuit(name string, circuit Circuit) error {
+	return cs.Namespace(name, func(cs *CS) error {
+		if err := cs.allocateInputs(circuit); err != nil {
+			return err
+		}
+		return circuit.Define(cs)
+	})
+}
+
+// NamespacePath returns the path of the current namespace, "" at the root
+func (cs *CS) NamespacePath() string {
+	return cs.namespace
+}
+
+// Stats returns the number of constraints (the computed values and the assertions, each yielding a R1C)
+// defined in each namespace, nested namespaces included, by namespace path. The root namespace ""
+// counts all the constraints
+func (cs *CS) Stats() map[string]int {
+	stats := make(map[string]int)
+	count := func(e expression) {
+		stats[""]++
+		path := cs.namespaces[e]
+		for path != "" {
+			stats[path]++
+			i := strings.LastIndex(path, backend.NamespaceSeparator)
+			if i < 0 {
+				break
+			}
+			path = path[:i]
+		}
+	}
+	for _, c := range cs.Constraints {
+		for _, e := range c.expressions {
+			count(e)
+		}
+	}
+	for _, e := range cs.MOConstraints {
+		count(e)
+	}
+	for _, e := range cs.NOConstraints {
+		count(e)
+	}
+	return stats
+}
+
+func (cs *CS) setNamespace(path string) {
+	cs.namespace = path
+	if cs.engine != nil {
+		cs.engine.namespace = path
+	}
+}
+
+// scoped returns name prefixed by the path of the current namespace
+func (cs *CS) scoped(name string) string {
+	if cs.namespace == "" {
+		return name
+	}
+	return cs.namespace + backend.NamespaceSeparator + name
+}
+
+// record records the call site and the namespace of the expression e -- debug info
+func (cs *CS) record(e expression, site string) {
+	cs.callSites[e] = site
+	if cs.namespace != "" {
+		cs.namespaces[e] = cs.namespace
+	} else {
+		delete(cs.namespaces, e)
+	}
+}
+
+// callSite returns the call site of the current API call, with the current namespace
+func (cs *CS) callSite() string {
+	return inNamespace(callSite(), cs.namespace)
+}
+
+// inNamespace appends the namespace path to a call site
+func inNamespace(site, namespace string) string {
+	if namespace == "" {
+		return site
+	}
+	return site + " (" + namespace + ")"
+}
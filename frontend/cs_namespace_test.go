@@ -0,0 +1,151 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gurvy/bn256/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// squareCircuit is a component used twice by the tests, x**2 == y
+type squareCircuit struct {
+	X Variable `gnark:"x"`
+	Y Variable `gnark:"y,public"`
+}
+
+func (circuit *squareCircuit) Define(cs *CS) error {
+	square := cs.MUL(circuit.X, circuit.X)
+	square.Tag("square")
+	cs.MUSTBE_EQ(square, circuit.Y)
+	return nil
+}
+
+func defineSquares(cs *CS) error {
+	var a, b squareCircuit
+	if err := cs.SubCircuit("a", &a); err != nil {
+		return err
+	}
+	return cs.Namespace("nested", func(cs *CS) error {
+		if !strings.HasSuffix(cs.NamespacePath(), "nested") {
+			return errors.New("unexpected namespace path " + cs.NamespacePath())
+		}
+		z := cs.SECRET_INPUT("z")
+		if err := cs.SubCircuit("b", &b); err != nil {
+			return err
+		}
+		cs.MUSTBE_BOOLEAN(z)
+		return nil
+	})
+}
+
+func squaresAssignment(z int) backend.Assignments {
+	var a, b squareCircuit
+	a.X.Assign(2)
+	a.Y.Assign(4)
+	b.X.Assign(3)
+	b.Y.Assign(9)
+
+	assignment := backend.NewAssignment()
+	assignment.Assign(backend.Secret, "nested/z", z)
+	for name, circuit := range map[string]*squareCircuit{"a": &a, "nested/b": &b} {
+		witness, err := ToAssignment(circuit)
+		if err != nil {
+			panic(err)
+		}
+		if err := assignment.AssignNamespace(name, witness); err != nil {
+			panic(err)
+		}
+	}
+	return assignment
+}
+
+func TestNamespace(t *testing.T) {
+	assert := require.New(t)
+
+	// the inputs and the tags of the components are prefixed by their namespace
+	cs := NewTestEngine(fr.ElementModulus(), squaresAssignment(1))
+	assert.NoError(defineSquares(&cs))
+	assert.Equal("", cs.NamespacePath())
+
+	values, err := cs.Inspect(true)
+	assert.NoError(err)
+	expected := map[string]int64{
+		"a/x": 2, "a/y": 4, "a/square": 4,
+		"nested/z": 1, "nested/b/x": 3, "nested/b/y": 9, "nested/b/square": 9,
+	}
+	assert.Equal(len(expected), len(values))
+	for name, v := range expected {
+		value := values[name]
+		assert.Equal(0, value.Cmp(big.NewInt(v)), name)
+	}
+
+	// the unsatisfied constraints report their namespace
+	cs = NewTestEngine(fr.ElementModulus(), squaresAssignment(2))
+	assert.NoError(defineSquares(&cs))
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint))
+	assert.Contains(err.Error(), "cs_namespace_test.go")
+	assert.Contains(err.Error(), "(nested)")
+
+	cs = New()
+	assert.NoError(defineSquares(&cs))
+	r1cs := cs.ToR1CS()
+	assert.Equal(3, r1cs.NbPublicWires) // ONE_WIRE, a/y, nested/b/y
+	found := false
+	for _, site := range r1cs.CallSites {
+		found = found || strings.HasSuffix(site, "(nested/b)")
+	}
+	assert.True(found, "the call sites should report the namespaces")
+
+	// a component can be used again in another namespace, not in the same one
+	assert.Equal(ErrInvalidNamespace, cs.Namespace("", defineSquares))
+	assert.NoError(cs.Namespace("c", defineSquares))
+	assert.Contains(defineSquares(&cs).Error(), `"a/x"`)
+}
+
+func TestNamespaceStats(t *testing.T) {
+	assert := require.New(t)
+
+	cs := New()
+	assert.NoError(defineSquares(&cs))
+
+	stats := cs.Stats()
+	assert.Equal(3, stats[""])
+	assert.Equal(1, stats["a"]) // x*x == y
+	assert.Equal(1, stats["nested/b"])
+	assert.Equal(2, stats["nested"]) // the boolean constraint of z, and b
+}
+
+func TestNamespaceAPIError(t *testing.T) {
+	assert := require.New(t)
+
+	cs := NewSafe()
+	assert.NoError(cs.Namespace("component", func(cs *CS) error {
+		cs.ADD(cs.SECRET_INPUT("x"), "not a number")
+		return nil
+	}))
+	assert.Equal(1, len(cs.Errors()))
+	err := cs.Errors()[0].(*APIError)
+	assert.Equal("component", err.Namespace)
+	assert.True(strings.HasSuffix(err.Error(), "(component)"), err.Error())
+}
@@ -42,6 +42,7 @@ func (circuit *CS) ToR1CS() *R1CS {
 	// those are needed to number the wires, before putting them in the wire tracker
 	var wireTracker, publicInputs, privateInputs []*wire
 	var computationalGraph []R1C
+	var computationalCallSites []string
 
 	// we keep track of wire that are "unconstrained" to ignore them at step 2
 	// unconstrained wires can be inputs or wires issued from a MOConstraint (like the i-th bit of a binary decomposition)
@@ -120,25 +121,30 @@ func (circuit *CS) ToR1CS() *R1CS {
 
 		constraint := circuit.Constraints[k]
 
-		batchR1CS := constraint.toR1CS(circuit)
+		batchR1CS, callSites := constraint.toR1CS(circuit)
 
 		if constraint.outputWire.isUserInput() {
 			r1cs.Constraints = append(r1cs.Constraints, batchR1CS...)
+			r1cs.CallSites = append(r1cs.CallSites, callSites...)
 		} else {
 			computationalGraph = append(computationalGraph, batchR1CS[0])
+			computationalCallSites = append(computationalCallSites, callSites[0])
 
 			if len(batchR1CS) > 1 {
 				r1cs.Constraints = append(r1cs.Constraints, batchR1CS[1:]...)
+				r1cs.CallSites = append(r1cs.CallSites, callSites[1:]...)
 			}
 		}
 	}
 	for _, c := range circuit.MOConstraints {
 		batchR1CS := c.toR1CS(circuit.Constraints[0].outputWire)
 		computationalGraph = append(computationalGraph, batchR1CS)
+		computationalCallSites = append(computationalCallSites, circuit.callSites[c])
 	}
 	for _, c := range circuit.NOConstraints {
 		batchR1CS := c.toR1CS(circuit.Constraints[0].outputWire)
 		r1cs.Constraints = append(r1cs.Constraints, batchR1CS)
+		r1cs.CallSites = append(r1cs.CallSites, circuit.callSites[c])
 	}
 
 	// Keeps track of the visited constraints, useful to build the computational graph
@@ -153,10 +159,13 @@ func (circuit *CS) ToR1CS() *R1CS {
 
 	// re-order the constraints
 	constraints := make([]R1C, len(graphOrdering))
+	callSites := make([]string, len(graphOrdering))
 	for i := 0; i < len(graphOrdering); i++ {
 		constraints[i] = computationalGraph[graphOrdering[i]]
+		callSites[i] = computationalCallSites[graphOrdering[i]]
 	}
 	r1cs.Constraints = append(constraints, r1cs.Constraints...)
+	r1cs.CallSites = append(callSites, r1cs.CallSites...)
 
 	// store R1CS nbWires and nbConstraints
 	r1cs.NbWires = len(wireTracker)
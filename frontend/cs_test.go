@@ -17,6 +17,7 @@ limitations under the License.
 package frontend
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -737,6 +738,73 @@ func TestALLOC(t *testing.T) {
 	// assert.Solved(circuit, good, expectedValues)
 }
 
+func TestAND_NOT(t *testing.T) {
+	// test helper
+	assert := NewAssert(t)
+
+	// circuit definition
+	circuit := New()
+
+	x := circuit.PUBLIC_INPUT("x")
+	y := circuit.PUBLIC_INPUT("y")
+
+	r0 := circuit.AND(x, y)
+	r1 := circuit.NOT(x)
+	r2 := circuit.XOR(r0, r1) // r0 and r1 are already boolean constrained
+
+	r0.Tag("r0")
+	r1.Tag("r1")
+	r2.Tag("r2")
+
+	// tests CS
+	assert.csIsCorrect(circuit, expectedCS{
+		nbWires:         6,
+		nbConstraints:   6,
+		nbMOConstraints: 0,
+		nbNOConstraints: 2,
+	})
+
+	// tests solving R1CS
+	assert.r1csIsCorrect(circuit, expectedR1CS{
+		nbWires:                    6,
+		nbComputationalConstraints: 3,
+		nbConstraints:              5,
+		nbPrivateWires:             0,
+		nbPublicWires:              3,
+	})
+}
+
+func TestIS_ZERO(t *testing.T) {
+	// test helper
+	assert := NewAssert(t)
+
+	// circuit definition
+	circuit := New()
+
+	x := circuit.PUBLIC_INPUT("x")
+
+	r0 := circuit.IS_ZERO(x)
+
+	r0.Tag("r0")
+
+	// tests CS
+	assert.csIsCorrect(circuit, expectedCS{
+		nbWires:         4,
+		nbConstraints:   4,
+		nbMOConstraints: 1,
+		nbNOConstraints: 1,
+	})
+
+	// tests solving R1CS
+	assert.r1csIsCorrect(circuit, expectedR1CS{
+		nbWires:                    4,
+		nbComputationalConstraints: 2,
+		nbConstraints:              3,
+		nbPrivateWires:             0,
+		nbPublicWires:              2,
+	})
+}
+
 func TestMUSTBE_BOOL(t *testing.T) {
 	// test helper
 	assert := NewAssert(t)
@@ -1010,3 +1078,157 @@ func TestMergeMoeNoe(t *testing.T) {
 	// assert.NotSolved(circuit, bad)
 	// assert.Solved(circuit, good, expectedValues)
 }
+
+func TestMergeLinearExpression(t *testing.T) {
+
+	// circuit definition
+	circuit := New()
+
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.PUBLIC_INPUT("y")
+
+	// a is consumed by a linear combination before being merged with b
+	a := circuit.MUL(x, x)
+	c := circuit.MUL(LinearCombination{Term{a, *big.NewInt(2)}, Term{x, *big.NewInt(1)}}, LinearCombination{Term{circuit.ALLOCATE(1), *big.NewInt(1)}})
+	b := circuit.MUL(LinearCombination{Term{x, *big.NewInt(1)}}, LinearCombination{Term{x, *big.NewInt(1)}})
+	circuit.MUSTBE_EQ(a, b)
+	circuit.MUSTBE_EQ(c, y)
+
+	r1cs := circuit.ToR1CS()
+	modulus := big.NewInt(1000003)
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x", 3)
+	good.Assign(backend.Public, "y", 21)
+	if err := r1cs.IsSolved(good, modulus); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 3)
+	bad.Assign(backend.Public, "y", 22)
+	if err := r1cs.IsSolved(bad, modulus); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("expected an unsatisfied constraint, got", err)
+	}
+}
+
+func TestConstantFolding(t *testing.T) {
+	assert := require.New(t)
+
+	var minusTwo big.Int
+	minusTwo.SetInt64(-2)
+
+	circuit := NewSafe()
+	x := circuit.SECRET_INPUT("x")
+	nbConstraints := circuit.nbConstraints
+
+	// operations on constants of any type yield constants, without adding constraints for the operations
+	constants := []*Constraint{
+		circuit.ADD(1, "2", *big.NewInt(3), uint64(4)),
+		circuit.SUB(2, minusTwo),
+		circuit.MUL(circuit.ALLOCATE(3), "5", 2),
+		circuit.DIV(12, *big.NewInt(4)),
+		circuit.SELECT(0, x, 7),
+		circuit.NOT(circuit.XOR(1, 0)),
+		circuit.AND(1, circuit.SUB(3, 2)),
+		circuit.FROM_BINARY(1, "0", uint64(1)),
+	}
+	for i, expected := range []int64{10, 4, 30, 3, 7, 0, 1, 5} {
+		value, ok := constants[i].constantValue()
+		assert.True(ok, "expected a constant")
+		assert.Equal(expected, value.Int64())
+	}
+	assert.True(circuit.SELECT(1, x, 7) == x, "SELECT with a constant condition should return the selected operand")
+	circuit.MUSTBE_EQ(circuit.ADD(2, 2), "4")
+	circuit.MUSTBE_BOOLEAN(1)
+
+	// each result is a single constant (1 being the ONE_WIRE), as is ALLOCATE(3)
+	assert.Equal(nbConstraints+9, circuit.nbConstraints)
+	assert.Empty(circuit.Errors())
+
+	// constants are compared in the field: the test engine reports the ones which differ
+	modulus := big.NewInt(1000003)
+	engine := NewTestEngine(modulus, backend.NewAssignment())
+	engine.MUSTBE_EQ(-1, 1000002)
+	_, err := engine.Inspect(false)
+	assert.NoError(err)
+	assert.Panics(func() { engine.MUSTBE_EQ(engine.MUL(2, 3), 7) })
+
+	// without a modulus, they are compared when solving the R1CS
+	for _, c := range []struct {
+		v1, v2 interface{}
+		ok     bool
+	}{{-1, 1000002, true}, {6, 7, false}} {
+		circuit := NewSafe()
+		circuit.MUSTBE_EQ(c.v1, c.v2)
+		r1cs, err := circuit.Compile()
+		assert.NoError(err)
+		err = r1cs.IsSolved(backend.NewAssignment(), modulus)
+		assert.Equal(c.ok, err == nil, "%v == %v: %v", c.v1, c.v2, err)
+	}
+
+	// a Constraint folded to 1 is the ONE_WIRE, which is equalized as the constant 1 in both orders
+	for _, swap := range []bool{false, true} {
+		circuit := NewSafe()
+		x := circuit.SECRET_INPUT("x")
+		operands := []interface{}{circuit.MUL(x, x), circuit.SUB(3, 2)}
+		if swap {
+			operands[0], operands[1] = operands[1], operands[0]
+		}
+		circuit.MUSTBE_EQ(operands[0], operands[1])
+		r1cs, err := circuit.Compile()
+		assert.NoError(err)
+		for x, ok := range map[int]bool{1: true, 2: false} {
+			assignment := backend.NewAssignment()
+			assignment.Assign(backend.Secret, "x", x)
+			err := r1cs.IsSolved(assignment, modulus)
+			assert.Equal(ok, err == nil, "x = %d: %v", x, err)
+		}
+
+		// as with the constant 1, a user input can't be equalized with it
+		circuit = NewSafe()
+		x = circuit.SECRET_INPUT("x")
+		operands = []interface{}{x, circuit.ALLOCATE(1)}
+		if swap {
+			operands[0], operands[1] = operands[1], operands[0]
+		}
+		circuit.MUSTBE_EQ(operands[0], operands[1])
+		assert.Len(circuit.Errors(), 1)
+		assert.True(errors.Is(circuit.Errors()[0], ErrInconsistantConstraint))
+		assert.Contains(circuit.Errors()[0].Error(), "(user input == VALUE) is invalid")
+	}
+}
+
+func TestConstantOperands(t *testing.T) {
+
+	// constants of any type mixed with variables
+	circuit := New()
+
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.PUBLIC_INPUT("y")
+
+	a := circuit.SUB(x, "1")                                   // x - 1
+	b := circuit.MUL(circuit.ADD(a, 1, 2), uint64(2))          // 2x + 4
+	c := circuit.SELECT(circuit.IS_ZERO(a), b, *big.NewInt(5)) // x == 1 ? 2x+4 : 5
+	circuit.MUSTBE_EQ(circuit.ADD(c, circuit.ALLOCATE(3)), y)
+	circuit.MUSTBE_EQ(circuit.FROM_BINARY(circuit.IS_ZERO(a), 0, "1"), circuit.ADD(circuit.IS_ZERO(a), 4)) // z + 4
+
+	r1cs := circuit.ToR1CS()
+	modulus := big.NewInt(1000003)
+
+	for x, y := range map[int]int{1: 9, 2: 8} {
+		good := backend.NewAssignment()
+		good.Assign(backend.Secret, "x", x)
+		good.Assign(backend.Public, "y", y)
+		if err := r1cs.IsSolved(good, modulus); err != nil {
+			t.Fatal(err)
+		}
+
+		bad := backend.NewAssignment()
+		bad.Assign(backend.Secret, "x", x)
+		bad.Assign(backend.Public, "y", y+1)
+		if err := r1cs.IsSolved(bad, modulus); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+			t.Fatal("expected an unsatisfied constraint, got", err)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package frontend
 import (
 	"math/big"
 	"strconv"
+	"strings"
 )
 
 // expression [of constraints] represents the lowest level of circuit design
@@ -139,9 +140,9 @@ func (l *linearExpression) consumeWires() {
 func (l *linearExpression) replaceWire(oldWire, newWire *wire) {
 
 	// replace
-	for _, t := range *l {
-		if t.Wire == oldWire {
-			t.Wire = newWire
+	for i := range *l {
+		if (*l)[i].Wire == oldWire {
+			(*l)[i].Wire = newWire
 		}
 	}
 
@@ -202,16 +203,8 @@ func (q *quadraticExpression) consumeWires() {
 }
 
 func (q *quadraticExpression) replaceWire(oldWire, newWire *wire) {
-	for _, t := range q.left {
-		if t.Wire == oldWire {
-			t.Wire = newWire
-		}
-	}
-	for _, t := range q.right {
-		if t.Wire == oldWire {
-			t.Wire = newWire
-		}
-	}
+	q.left.replaceWire(oldWire, newWire)
+	q.right.replaceWire(oldWire, newWire)
 }
 
 func (q *quadraticExpression) toR1CS(constWire *wire, w ...*wire) R1C {
@@ -690,3 +683,155 @@ func (win *lutExpression) string() string {
 	res = res + "+" + lookuptablereg[2].String() + "*" + win.b1.String()
 	return res
 }
+
+// isZeroExpression expression used to test if a variable is zero: x*m = 1-res, m being
+// the inverse of x (or 0 if x is 0). Along with x*res = 0 (zeroProductExpression), it ensures res = (x == 0)
+type isZeroExpression struct {
+	x, m, res *wire
+}
+
+func (z *isZeroExpression) consumeWires() {
+	z.x.IsConsumed = true
+}
+
+func (z *isZeroExpression) replaceWire(oldWire, newWire *wire) {
+	if z.x == oldWire {
+		z.x = newWire
+	}
+	if z.m == oldWire {
+		z.m = newWire
+	}
+	if z.res == oldWire {
+		z.res = newWire
+	}
+}
+
+func (z *isZeroExpression) toR1CS(constWire *wire, w ...*wire) R1C {
+
+	var minusOne big.Int
+	one := bigOne()
+	minusOne.Neg(&one)
+
+	L := LinearExpression{
+		TermR1cs{ID: z.x.WireID, Coeff: one},
+	}
+
+	// the solver expects the hint m as the first (and only) term of R
+	R := LinearExpression{
+		TermR1cs{ID: z.m.WireID, Coeff: one},
+	}
+
+	O := LinearExpression{
+		TermR1cs{ID: constWire.WireID, Coeff: one},
+		TermR1cs{ID: z.res.WireID, Coeff: minusOne},
+	}
+
+	return R1C{L: L, R: R, O: O, Solver: IsZero}
+}
+
+func (z *isZeroExpression) setConstraintID(n int64) {
+	z.m.ConstraintID = n
+	z.res.ConstraintID = n
+}
+
+func (z *isZeroExpression) string() string {
+	return z.x.String() + "*" + z.m.String() + " = 1 - " + z.res.String()
+}
+
+// hintExpression expression whose outputs are computed by the hint id from the inputs when the R1CS is solved.
+// Its R1C inputs*0 = 0*outputs is always satisfied: the outputs are checked by the constraints of the circuit
+type hintExpression struct {
+	id      HintID
+	inputs  []*wire
+	outputs []*wire
+}
+
+func (h *hintExpression) consumeWires() {
+	for _, w := range h.inputs {
+		w.IsConsumed = true
+	}
+}
+
+func (h *hintExpression) replaceWire(oldWire, newWire *wire) {
+	for _, wires := range [][]*wire{h.inputs, h.outputs} {
+		for i := range wires {
+			if wires[i] == oldWire {
+				wires[i] = newWire
+			}
+		}
+	}
+}
+
+func (h *hintExpression) toR1CS(constWire *wire, w ...*wire) R1C {
+
+	// the solver expects the inputs in L, and the outputs in O
+	L := make(LinearExpression, len(h.inputs))
+	for i, in := range h.inputs {
+		L[i] = TermR1cs{ID: in.WireID, Coeff: bigOne()}
+	}
+
+	O := make(LinearExpression, len(h.outputs))
+	for i, out := range h.outputs {
+		O[i] = TermR1cs{ID: out.WireID}
+	}
+
+	return R1C{L: L, R: LinearExpression{}, O: O, Solver: Hint, HintID: h.id}
+}
+
+func (h *hintExpression) setConstraintID(n int64) {
+	for _, w := range h.outputs {
+		w.ConstraintID = n
+	}
+}
+
+func (h *hintExpression) string() string {
+	inputs := make([]string, len(h.inputs))
+	for i, w := range h.inputs {
+		inputs[i] = w.String()
+	}
+	outputs := make([]string, len(h.outputs))
+	for i, w := range h.outputs {
+		outputs[i] = w.String()
+	}
+	return strings.Join(outputs, ", ") + " = hint_" + strconv.Itoa(int(h.id)) + "(" + strings.Join(inputs, ", ") + ")"
+}
+
+// zeroProductExpression constraint a*b = 0
+type zeroProductExpression struct {
+	a, b *wire
+}
+
+func (z *zeroProductExpression) consumeWires() {
+}
+
+func (z *zeroProductExpression) replaceWire(oldWire, newWire *wire) {
+	if z.a == oldWire {
+		z.a = newWire
+	}
+	if z.b == oldWire {
+		z.b = newWire
+	}
+}
+
+func (z *zeroProductExpression) toR1CS(constWire *wire, w ...*wire) R1C {
+
+	var zero big.Int
+
+	L := LinearExpression{
+		TermR1cs{ID: z.a.WireID, Coeff: bigOne()},
+	}
+
+	R := LinearExpression{
+		TermR1cs{ID: z.b.WireID, Coeff: bigOne()},
+	}
+
+	O := LinearExpression{
+		TermR1cs{ID: constWire.WireID, Coeff: zero},
+	}
+
+	return R1C{L: L, R: R, O: O, Solver: SingleOutput}
+}
+
+func (z *zeroProductExpression) string() string {
+	return z.a.String() + "*" + z.b.String() + "=0"
+}
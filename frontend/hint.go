@@ -0,0 +1,83 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+var (
+	ErrUnknownHint   = errors.New("no hint registered with this identifier")
+	ErrDuplicateHint = errors.New("a hint is already registered with this identifier")
+)
+
+// HintID identifies a hint: the R1CS stores it, and the solver looks up the function registered with it
+type HintID uint32
+
+// HintFunction computes the outputs of a hint from the values of its inputs, in [0, modulus), modulus
+// being the size of the field the R1CS is solved in. The outputs are allocated by the caller, and reduced
+// modulo modulus once the function returns
+type HintFunction func(modulus *big.Int, inputs []*big.Int, outputs []*big.Int) error
+
+// hints registered with RegisterHint
+var hints = struct {
+	sync.RWMutex
+	functions map[HintID]HintFunction
+}{functions: make(map[HintID]HintFunction)}
+
+// RegisterHint registers f with the identifier id, typically in an init function of the package defining
+// the circuit. As the R1CS only stores id, the programs solving it must register the same function
+func RegisterHint(id HintID, f HintFunction) error {
+	hints.Lock()
+	defer hints.Unlock()
+	if _, ok := hints.functions[id]; ok {
+		return fmt.Errorf("%w: %d", ErrDuplicateHint, id)
+	}
+	hints.functions[id] = f
+	return nil
+}
+
+// LookupHint returns the function registered with the identifier id
+func LookupHint(id HintID) (HintFunction, bool) {
+	hints.RLock()
+	defer hints.RUnlock()
+	f, ok := hints.functions[id]
+	return f, ok
+}
+
+// SolveHint calls the hint id on the values of its inputs, and returns the values of its nbOutputs outputs.
+// The solvers call it on the R1C of a HINT (L being the inputs and O the outputs)
+func SolveHint(id HintID, modulus *big.Int, inputs []*big.Int, nbOutputs int) ([]*big.Int, error) {
+	f, ok := LookupHint(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownHint, id)
+	}
+	outputs := make([]*big.Int, nbOutputs)
+	for i := range outputs {
+		outputs[i] = new(big.Int)
+	}
+	if err := f(modulus, inputs, outputs); err != nil {
+		return nil, fmt.Errorf("hint %d: %w", id, err)
+	}
+	for _, o := range outputs {
+		o.Mod(o, modulus)
+	}
+	return outputs, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/stretchr/testify/require"
+)
+
+const hintSqrt HintID = 100
+
+var errNotASquare = errors.New("not a square")
+
+func init() {
+	if err := RegisterHint(hintSqrt, sqrt); err != nil {
+		panic(err)
+	}
+}
+
+// sqrt sets outputs[0] to a square root of inputs[0]
+func sqrt(modulus *big.Int, inputs, outputs []*big.Int) error {
+	if outputs[0].ModSqrt(inputs[0], modulus) == nil {
+		return errNotASquare
+	}
+	return nil
+}
+
+// defineSqrt checks the square root of x computed by a hint
+func defineSqrt(cs *CS) {
+	x := cs.SECRET_INPUT("x")
+	s := cs.HINT(hintSqrt, 1, x)[0]
+	s.Tag("s")
+	cs.MUSTBE_EQ(cs.MUL(s, s), x)
+}
+
+func TestHint(t *testing.T) {
+	assert := require.New(t)
+
+	// a small prime, such that the field has no backend
+	modulus := big.NewInt(1000003)
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x", 16)
+
+	// x is not a square modulo 1000003
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 5)
+
+	// test engine
+	cs := NewTestEngine(modulus, good)
+	defineSqrt(&cs)
+	values, err := cs.Inspect(false)
+	assert.NoError(err)
+	s := values["s"]
+	s.Mul(&s, &s).Mod(&s, modulus)
+	assert.Equal(int64(16), s.Int64())
+
+	cs = NewTestEngine(modulus, bad)
+	defineSqrt(&cs)
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, errNotASquare))
+	assert.Contains(err.Error(), "hint_test.go")
+
+	// R1CS solver
+	cs = New()
+	defineSqrt(&cs)
+	r1cs := cs.ToR1CS()
+	assert.NoError(r1cs.IsSolved(good, modulus))
+	err = r1cs.IsSolved(bad, modulus)
+	assert.True(errors.Is(err, errNotASquare))
+	assert.Contains(err.Error(), "hint_test.go")
+
+	// the R1C of the hint is kept by the optimizer
+	optimized, _ := r1cs.Optimize(modulus)
+	assert.NoError(optimized.IsSolved(good, modulus))
+	assert.True(errors.Is(optimized.IsSolved(bad, modulus), errNotASquare))
+}
+
+func TestHintErrors(t *testing.T) {
+	assert := require.New(t)
+
+	assert.True(errors.Is(RegisterHint(hintSqrt, sqrt), ErrDuplicateHint))
+
+	cs := NewSafe()
+	outputs := cs.HINT(HintID(101), 2, cs.SECRET_INPUT("x"))
+	assert.Equal(2, len(outputs))
+	assert.Equal(1, len(cs.Errors()))
+	assert.True(errors.Is(cs.Errors()[0], ErrUnknownHint))
+
+	// the hint can't be found when the R1CS is solved
+	r1cs := (&R1CS{
+		NbWires:        3,
+		NbPrivateWires: 1,
+		PrivateWires:   []string{"x"},
+		NbPublicWires:  1,
+		PublicWires:    []string{backend.OneWire},
+		Constraints: []R1C{
+			{L: LinearExpression{{ID: 1}}, O: LinearExpression{{ID: 0}}, Solver: Hint, HintID: 101},
+		},
+		NbConstraints:   1,
+		NbCOConstraints: 1,
+	})
+	assignment := backend.NewAssignment()
+	assignment.Assign(backend.Secret, "x", 1)
+	assert.True(errors.Is(r1cs.IsSolved(assignment, big.NewInt(1000003)), ErrUnknownHint))
+}
@@ -34,6 +34,7 @@ type R1CS struct {
 	NbConstraints   int // total number of constraints
 	NbCOConstraints int // number of constraints that need to be solved, the first of the Constraints slice
 	Constraints     []R1C
+	CallSites       []string // optional, file:line of the frontend call which created each constraint -- debug info
 }
 
 // method to solve a r1cs
@@ -42,6 +43,8 @@ type SolvingMethod uint8
 const (
 	SingleOutput SolvingMethod = iota
 	BinaryDec
+	IsZero
+	Hint // the outputs (O) are computed by a HintFunction from the inputs (L), see CS.HINT
 )
 
 // Term ...
@@ -59,6 +62,7 @@ type R1C struct {
 	R      LinearExpression
 	O      LinearExpression
 	Solver SolvingMethod
+	HintID HintID // set if Solver is Hint
 }
 
 func bigOne() big.Int {
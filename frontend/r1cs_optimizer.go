@@ -0,0 +1,540 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+)
+
+// names of the optimization passes, as they appear in the OptimizationReport
+const (
+	PassConstantFolding = "constant folding"
+	PassLinearInlining  = "linear expression inlining"
+	PassDeduplication   = "deduplication"
+	PassDeadWires       = "dead wire elimination"
+)
+
+// OptimizationPass reports the number of constraints removed by an optimization pass
+type OptimizationPass struct {
+	Name                 string
+	NbRemovedConstraints int
+}
+
+// OptimizationReport is returned by R1CS.Optimize
+type OptimizationReport struct {
+	NbConstraintsBefore, NbConstraintsAfter int
+	NbWiresBefore, NbWiresAfter             int
+	Passes                                  []OptimizationPass
+}
+
+// String returns a human readable version of the report
+func (report OptimizationReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-30s %d -> %d\n", "constraints", report.NbConstraintsBefore, report.NbConstraintsAfter)
+	fmt.Fprintf(&sb, "%-30s %d -> %d\n", "wires", report.NbWiresBefore, report.NbWiresAfter)
+	for _, pass := range report.Passes {
+		fmt.Fprintf(&sb, "%-30s -%d constraints\n", pass.Name, pass.NbRemovedConstraints)
+	}
+	return sb.String()
+}
+
+/*
+Optimize returns an equivalent R1CS with fewer constraints, and a report of the
+number of constraints removed by each pass; r1cs is left untouched. The coefficients are
+computed modulo the modulus of the scalar field of the curve the R1CS will be used with.
+
+cs.ToR1CS emits one R1C per expression, so each ADD, SUB or multiplication by a constant
+costs a constraint. The following passes are applied until none of them removes a constraint:
+
+  - constant folding: a wire computed from constants only ((k1⋅ONE)⋅(k2⋅ONE) = w) is replaced by (k1⋅k2)⋅ONE
+  - linear expression inlining: a wire defined by a linear expression ((Σcᵢ⋅xᵢ)⋅(k⋅ONE) = w) is replaced by
+    the expression in the constraints using it
+  - deduplication: a wire computed as another one (same L⋅R) is replaced by it, and duplicate assertions are removed
+  - dead wire elimination: a wire computed by a constraint (L⋅R = w) but not used anywhere else is removed
+
+inputs, tagged wires (debug info) and wires of binary decompositions are never removed. The computational
+constraints remain ordered, such that the backends can still solve them one after the other.
+*/
+func (r1cs *R1CS) Optimize(modulus *big.Int) (*R1CS, OptimizationReport) {
+	report := OptimizationReport{
+		NbConstraintsBefore: r1cs.NbConstraints,
+		NbWiresBefore:       r1cs.NbWires,
+	}
+
+	o := newOptimizer(r1cs, modulus)
+	if o.oneWire != -1 {
+		for {
+			nbRemoved := o.inlineLinearExpressions()
+			nbRemoved += o.deduplicate()
+			nbRemoved += o.eliminateDeadWires()
+			if nbRemoved == 0 {
+				break
+			}
+		}
+	}
+	res := o.toR1CS()
+
+	report.NbConstraintsAfter = res.NbConstraints
+	report.NbWiresAfter = res.NbWires
+	for _, name := range []string{PassConstantFolding, PassLinearInlining, PassDeduplication, PassDeadWires} {
+		report.Passes = append(report.Passes, OptimizationPass{Name: name, NbRemovedConstraints: o.nbRemoved[name]})
+	}
+
+	return res, report
+}
+
+// optimizer works on a copy of the R1CS, constraints and wires are flagged as removed
+// and the R1CS is rebuilt at the end
+type optimizer struct {
+	r1cs        *R1CS
+	constraints []R1C
+	removed     []bool // removed constraints
+	unknown     []int64
+
+	wireRemoved []bool
+	frozen      []bool             // wires which can't be removed
+	occurrences []map[int]struct{} // constraints in which a wire appears
+	oneWire     int64
+	nbRemoved   map[string]int
+	modulus     *big.Int
+}
+
+func newOptimizer(r1cs *R1CS, modulus *big.Int) *optimizer {
+	o := &optimizer{
+		r1cs:        r1cs,
+		modulus:     modulus,
+		constraints: make([]R1C, len(r1cs.Constraints)),
+		removed:     make([]bool, len(r1cs.Constraints)),
+		unknown:     make([]int64, len(r1cs.Constraints)),
+		wireRemoved: make([]bool, r1cs.NbWires),
+		frozen:      make([]bool, r1cs.NbWires),
+		occurrences: make([]map[int]struct{}, r1cs.NbWires),
+		oneWire:     -1,
+		nbRemoved:   make(map[string]int),
+	}
+
+	// copy the constraints, the optimizer modifies them
+	for i, c := range r1cs.Constraints {
+		o.constraints[i] = R1C{L: c.L.clone(modulus), R: c.R.clone(modulus), O: c.O.clone(modulus), Solver: c.Solver, HintID: c.HintID}
+	}
+
+	// inputs and tagged wires must be kept, so are the wires of the constraints which are not
+	// single output (binary decompositions, zero tests, hints), as they are solved from their position in the constraint
+	inputsOffset := r1cs.NbWires - r1cs.NbPublicWires - r1cs.NbPrivateWires
+	for i := inputsOffset; i < r1cs.NbWires; i++ {
+		o.frozen[i] = true
+	}
+	for i, name := range r1cs.PublicWires {
+		if name == backend.OneWire {
+			o.oneWire = int64(r1cs.NbWires - r1cs.NbPublicWires + i)
+		}
+	}
+	for id := range r1cs.WireTags {
+		o.frozen[id] = true
+	}
+	for _, c := range o.constraints {
+		if c.Solver != SingleOutput {
+			for _, l := range []LinearExpression{c.L, c.R, c.O} {
+				for _, t := range l {
+					o.frozen[t.ID] = true
+				}
+			}
+		}
+	}
+
+	// find the wire solved by each computational constraint
+	instantiated := make([]bool, r1cs.NbWires)
+	for i := inputsOffset; i < r1cs.NbWires; i++ {
+		instantiated[i] = true
+	}
+	for i := range o.constraints {
+		o.unknown[i] = -1
+		if i >= r1cs.NbCOConstraints {
+			continue
+		}
+		c := &o.constraints[i]
+		if c.Solver != SingleOutput {
+			for _, l := range []LinearExpression{c.L, c.R, c.O} {
+				for _, t := range l {
+					instantiated[t.ID] = true
+				}
+			}
+			continue
+		}
+		for _, l := range []LinearExpression{c.L, c.R, c.O} {
+			for _, t := range l {
+				if !instantiated[t.ID] {
+					o.unknown[i] = t.ID
+					instantiated[t.ID] = true
+				}
+			}
+		}
+	}
+
+	for i := range o.occurrences {
+		o.occurrences[i] = make(map[int]struct{})
+	}
+	for i := range o.constraints {
+		o.register(i)
+	}
+
+	return o
+}
+
+// inlineLinearExpressions replaces the wires defined by a linear expression (or a constant) by the expression
+func (o *optimizer) inlineLinearExpressions() int {
+	nbRemoved := 0
+	for i := 0; i < o.r1cs.NbCOConstraints; i++ {
+		w, coeff, ok := o.definedWire(i)
+		if !ok {
+			continue
+		}
+		c := &o.constraints[i]
+
+		// w⋅coeff = L⋅R, coeff = ±1
+		lConstant, lValue := o.constantValue(c.L)
+		rConstant, rValue := o.constantValue(c.R)
+		var expression LinearExpression
+		switch {
+		case rConstant:
+			rValue.Mul(&rValue, &coeff)
+			expression = c.L.scale(&rValue, o.modulus)
+		case lConstant:
+			lValue.Mul(&lValue, &coeff)
+			expression = c.R.scale(&lValue, o.modulus)
+		default:
+			continue
+		}
+
+		o.substitute(w, expression, i)
+		o.remove(i)
+		if lConstant && rConstant {
+			o.nbRemoved[PassConstantFolding]++
+		} else {
+			o.nbRemoved[PassLinearInlining]++
+		}
+		nbRemoved++
+	}
+
+	// assertions on constants only, which hold
+	for i := o.r1cs.NbCOConstraints; i < len(o.constraints); i++ {
+		if o.removed[i] {
+			continue
+		}
+		c := &o.constraints[i]
+		lConstant, lValue := o.constantValue(c.L)
+		rConstant, rValue := o.constantValue(c.R)
+		oConstant, oValue := o.constantValue(c.O)
+		if lConstant && rConstant && oConstant && reduce(lValue.Mul(&lValue, &rValue), o.modulus).Cmp(&oValue) == 0 {
+			o.remove(i)
+			o.nbRemoved[PassConstantFolding]++
+			nbRemoved++
+		}
+	}
+
+	return nbRemoved
+}
+
+// deduplicate replaces the wires computed as another wire, and removes duplicate assertions
+func (o *optimizer) deduplicate() int {
+	nbRemoved := 0
+	definitions := make(map[string]int)
+	constraints := make(map[string]struct{})
+	for i := range o.constraints {
+		if o.removed[i] || o.constraints[i].Solver != SingleOutput {
+			continue
+		}
+		c := &o.constraints[i]
+
+		if w, coeff, ok := o.definedWire(i); ok {
+			// w⋅coeff = L⋅R = w'⋅coeff', coeff and coeff' being ±1
+			key := productKey(c.L, c.R)
+			if j, ok := definitions[key]; ok {
+				var wCoeff big.Int
+				other := o.constraints[j].O[0]
+				wCoeff.Mul(&coeff, &other.Coeff)
+				o.substitute(w, LinearExpression{TermR1cs{ID: other.ID, Coeff: wCoeff}}, i)
+				o.remove(i)
+				o.nbRemoved[PassDeduplication]++
+				nbRemoved++
+				continue
+			}
+			definitions[key] = i
+		}
+
+		key := productKey(c.L, c.R) + "=" + c.O.key()
+		if _, ok := constraints[key]; ok && i >= o.r1cs.NbCOConstraints {
+			o.remove(i)
+			o.nbRemoved[PassDeduplication]++
+			nbRemoved++
+			continue
+		}
+		constraints[key] = struct{}{}
+	}
+	return nbRemoved
+}
+
+// eliminateDeadWires removes the constraints computing a wire which is not used anywhere else
+func (o *optimizer) eliminateDeadWires() int {
+	nbRemoved := 0
+	// backward, such that a chain of unused wires is removed in one pass
+	for i := o.r1cs.NbCOConstraints - 1; i >= 0; i-- {
+		w, _, ok := o.definedWire(i)
+		if !ok || len(o.occurrences[w]) != 1 {
+			continue
+		}
+		o.remove(i)
+		o.wireRemoved[w] = true
+		o.nbRemoved[PassDeadWires]++
+		nbRemoved++
+	}
+	return nbRemoved
+}
+
+// definedWire returns w if the i-th constraint is L⋅R = ±w, w being the (removable) wire it solves
+func (o *optimizer) definedWire(i int) (int64, big.Int, bool) {
+	var coeff big.Int
+	if o.removed[i] || o.constraints[i].Solver != SingleOutput {
+		return -1, coeff, false
+	}
+	w := o.unknown[i]
+	if w == -1 || o.frozen[w] || o.wireRemoved[w] {
+		return -1, coeff, false
+	}
+	c := &o.constraints[i]
+	if len(c.O) != 1 || c.O[0].ID != w || !isUnit(&c.O[0].Coeff) || c.L.contains(w) || c.R.contains(w) {
+		return -1, coeff, false
+	}
+	coeff.Set(&c.O[0].Coeff)
+	return w, coeff, true
+}
+
+// constantValue returns true and the value of l if l only involves the ONE wire
+func (o *optimizer) constantValue(l LinearExpression) (bool, big.Int) {
+	var res big.Int
+	for _, t := range l {
+		if t.ID != o.oneWire {
+			return false, res
+		}
+		res.Add(&res, &t.Coeff)
+	}
+	reduce(&res, o.modulus)
+	return true, res
+}
+
+// substitute replaces w by expression in all the constraints except the skip-th one
+func (o *optimizer) substitute(w int64, expression LinearExpression, skip int) {
+	var constraints []int
+	for i := range o.occurrences[w] {
+		if i != skip {
+			constraints = append(constraints, i)
+		}
+	}
+	sort.Ints(constraints)
+	for _, i := range constraints {
+		o.unregister(i)
+		c := &o.constraints[i]
+		c.L = c.L.replace(w, expression, o.modulus)
+		c.R = c.R.replace(w, expression, o.modulus)
+		c.O = c.O.replace(w, expression, o.modulus)
+		o.register(i)
+	}
+	o.wireRemoved[w] = true
+}
+
+func (o *optimizer) remove(i int) {
+	o.unregister(i)
+	o.removed[i] = true
+}
+
+func (o *optimizer) register(i int) {
+	c := &o.constraints[i]
+	for _, l := range []LinearExpression{c.L, c.R, c.O} {
+		for _, t := range l {
+			o.occurrences[t.ID][i] = struct{}{}
+		}
+	}
+}
+
+func (o *optimizer) unregister(i int) {
+	c := &o.constraints[i]
+	for _, l := range []LinearExpression{c.L, c.R, c.O} {
+		for _, t := range l {
+			delete(o.occurrences[t.ID], i)
+		}
+	}
+}
+
+// toR1CS builds the optimized R1CS, the remaining wires are renumbered
+func (o *optimizer) toR1CS() *R1CS {
+	wireIDs := make([]int64, len(o.wireRemoved))
+	nbWires := 0
+	for i, removed := range o.wireRemoved {
+		if !removed {
+			wireIDs[i] = int64(nbWires)
+			nbWires++
+		}
+	}
+	renumber := func(l LinearExpression) LinearExpression {
+		for i := range l {
+			l[i].ID = wireIDs[l[i].ID]
+		}
+		return l
+	}
+
+	res := &R1CS{
+		NbWires:        nbWires,
+		NbPublicWires:  o.r1cs.NbPublicWires,
+		NbPrivateWires: o.r1cs.NbPrivateWires,
+		PrivateWires:   append([]string(nil), o.r1cs.PrivateWires...),
+		PublicWires:    append([]string(nil), o.r1cs.PublicWires...),
+		WireTags:       make(map[int][]string),
+	}
+	for id, tags := range o.r1cs.WireTags {
+		res.WireTags[int(wireIDs[id])] = append([]string(nil), tags...)
+	}
+	for i, c := range o.constraints {
+		if o.removed[i] {
+			continue
+		}
+		if i < o.r1cs.NbCOConstraints {
+			res.NbCOConstraints++
+		}
+		res.Constraints = append(res.Constraints, R1C{L: renumber(c.L), R: renumber(c.R), O: renumber(c.O), Solver: c.Solver, HintID: c.HintID})
+		if i < len(o.r1cs.CallSites) {
+			res.CallSites = append(res.CallSites, o.r1cs.CallSites[i])
+		}
+	}
+	res.NbConstraints = len(res.Constraints)
+
+	return res
+}
+
+// clone returns a copy of l, the coefficients being reduced modulo modulus
+func (l LinearExpression) clone(modulus *big.Int) LinearExpression {
+	res := make(LinearExpression, len(l))
+	for i, t := range l {
+		res[i].ID = t.ID
+		reduce(res[i].Coeff.Set(&t.Coeff), modulus)
+	}
+	return res
+}
+
+// scale returns k⋅l
+func (l LinearExpression) scale(k, modulus *big.Int) LinearExpression {
+	res := make(LinearExpression, len(l))
+	for i, t := range l {
+		res[i].ID = t.ID
+		reduce(res[i].Coeff.Mul(&t.Coeff, k), modulus)
+	}
+	return res
+}
+
+func (l LinearExpression) contains(id int64) bool {
+	for _, t := range l {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// replace returns l where the wire id is replaced by the expression, terms on the same wire are merged
+// (modulo modulus)
+func (l LinearExpression) replace(id int64, expression LinearExpression, modulus *big.Int) LinearExpression {
+	if !l.contains(id) {
+		return l
+	}
+	res := make(LinearExpression, 0, len(l)+len(expression))
+	positions := make(map[int64]int)
+	add := func(id int64, coeff *big.Int) {
+		if i, ok := positions[id]; ok {
+			res[i].Coeff.Add(&res[i].Coeff, coeff)
+			return
+		}
+		positions[id] = len(res)
+		var t TermR1cs
+		t.ID = id
+		t.Coeff.Set(coeff)
+		res = append(res, t)
+	}
+	var tmp big.Int
+	for _, t := range l {
+		if t.ID != id {
+			add(t.ID, &t.Coeff)
+			continue
+		}
+		for _, e := range expression {
+			tmp.Mul(&e.Coeff, &t.Coeff)
+			add(e.ID, &tmp)
+		}
+	}
+
+	// remove the terms which cancelled out
+	n := 0
+	for i := range res {
+		if reduce(&res[i].Coeff, modulus).Sign() != 0 {
+			res[n] = res[i]
+			n++
+		}
+	}
+	return res[:n]
+}
+
+// key returns a canonical string representation of l (terms sorted by wire)
+func (l LinearExpression) key() string {
+	terms := make([]string, 0, len(l))
+	sorted := make(LinearExpression, len(l))
+	copy(sorted, l)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	for _, t := range sorted {
+		if t.Coeff.Sign() != 0 {
+			terms = append(terms, fmt.Sprintf("%d:%s", t.ID, t.Coeff.String()))
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// productKey returns a canonical string representation of l⋅r
+func productKey(l, r LinearExpression) string {
+	lKey, rKey := l.key(), r.key()
+	if lKey > rKey {
+		lKey, rKey = rKey, lKey
+	}
+	return "(" + lKey + ")*(" + rKey + ")"
+}
+
+// reduce sets a to its representative modulo modulus in ]-modulus/2, modulus/2] and returns it,
+// such that the coefficients -1 and p-1 are the same unit
+func reduce(a, modulus *big.Int) *big.Int {
+	var half big.Int
+	half.Rsh(modulus, 1)
+	if a.Mod(a, modulus).Cmp(&half) > 0 {
+		a.Sub(a, modulus)
+	}
+	return a
+}
+
+// isUnit returns true if a is 1 or -1
+func isUnit(a *big.Int) bool {
+	return a.IsInt64() && (a.Int64() == 1 || a.Int64() == -1)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gurvy/bn256/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimize(t *testing.T) {
+	assert := require.New(t)
+
+	circuit := New()
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.PUBLIC_INPUT("y")
+
+	// constants: 3*4 = 12
+	c := circuit.MUL(circuit.ALLOCATE(3), circuit.ALLOCATE(4))
+
+	// linear expressions: ((x + 12) * 2 - x)
+	l := circuit.SUB(circuit.MUL(circuit.ADD(x, c), 2), x)
+
+	// duplicates: x*l computed twice
+	m1 := circuit.MUL(x, l)
+	m2 := circuit.MUL(x, l)
+	circuit.MUSTBE_EQ(circuit.ADD(m1, m2), y)
+
+	// dead wires
+	circuit.MUL(circuit.ADD(x, y), x)
+
+	// tagged wires are kept
+	tagged := circuit.MUL(x, x)
+	tagged.Tag("x^2")
+
+	r1cs := circuit.ToR1CS()
+	optimized, report := r1cs.Optimize(fr.ElementModulus())
+
+	// the original R1CS is untouched
+	assert.Equal(r1cs.NbConstraints, report.NbConstraintsBefore)
+	assert.Equal(r1cs.NbWires, report.NbWiresBefore)
+	assert.Equal(optimized.NbConstraints, report.NbConstraintsAfter)
+	assert.Equal(optimized.NbWires, report.NbWiresAfter)
+
+	removed := make(map[string]int)
+	total := 0
+	for _, pass := range report.Passes {
+		removed[pass.Name] = pass.NbRemovedConstraints
+		total += pass.NbRemovedConstraints
+	}
+	assert.Equal(report.NbConstraintsBefore-report.NbConstraintsAfter, total)
+	assert.Equal(3, removed[PassConstantFolding], report.String())
+	assert.Equal(4, removed[PassLinearInlining], report.String()) // x+12, *2, -x, x+y
+	assert.Equal(1, removed[PassDeduplication], report.String())
+	assert.Equal(1, removed[PassDeadWires], report.String())
+
+	// x*l, x^2 (tagged), m1+m2 == y
+	assert.Equal(3, optimized.NbConstraints, report.String())
+	assert.Equal(2, optimized.NbCOConstraints)
+	assert.Equal(r1cs.PublicWires, optimized.PublicWires)
+	assert.Equal(r1cs.PrivateWires, optimized.PrivateWires)
+	assert.Len(optimized.WireTags, 1)
+	for id, tags := range optimized.WireTags {
+		assert.Equal([]string{"x^2"}, tags)
+		assert.True(id < optimized.NbWires-optimized.NbPublicWires-optimized.NbPrivateWires)
+	}
+
+	// the optimization is stable
+	again, report := optimized.Optimize(fr.ElementModulus())
+	assert.Equal(optimized.NbConstraints, again.NbConstraints, report.String())
+
+	// the optimized R1CS can be converted for PLONK
+	sparse := optimized.ToSparseR1CS()
+	assert.NotNil(sparse)
+}
+
+func TestOptimizeModulus(t *testing.T) {
+	assert := require.New(t)
+
+	// a small prime, such that the field has no backend
+	modulus := big.NewInt(1000003)
+
+	// (p+1)⋅x - x + 5 == y, the terms on x cancel out modulo p
+	circuit := New()
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.PUBLIC_INPUT("y")
+	circuit.MUSTBE_EQ(circuit.ADD(circuit.MUL(x, 1000004), circuit.MUL(x, -1), 5), y)
+
+	r1cs := circuit.ToR1CS()
+	optimized, report := r1cs.Optimize(modulus)
+
+	var half big.Int
+	half.Rsh(modulus, 1)
+	for _, c := range optimized.Constraints {
+		for _, l := range []LinearExpression{c.L, c.R, c.O} {
+			for _, t := range l {
+				assert.NotEqual(0, t.Coeff.Sign(), report.String())
+				assert.True(new(big.Int).Abs(&t.Coeff).Cmp(&half) <= 0, "coefficient not reduced: %s", t.Coeff.String())
+			}
+		}
+	}
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x", 7)
+	good.Assign(backend.Public, "y", 5)
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 7)
+	bad.Assign(backend.Public, "y", 6)
+	assert.NoError(optimized.IsSolved(good, modulus))
+	assert.Error(optimized.IsSolved(bad, modulus))
+}
+
+func TestCompileWithOptimization(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit optimizableCircuit
+	r1cs, err := Compile(&circuit)
+	assert.NoError(err)
+	optimized, err := Compile(&circuit, WithOptimization(fr.ElementModulus()))
+	assert.NoError(err)
+	assert.Less(optimized.NbConstraints, r1cs.NbConstraints)
+}
+
+type optimizableCircuit struct {
+	X Variable
+	Y Variable `gnark:",public"`
+}
+
+func (circuit *optimizableCircuit) Define(cs *CS) error {
+	cs.MUSTBE_EQ(cs.SUB(cs.MUL(cs.ADD(circuit.X, 12), 2), circuit.X), circuit.Y)
+	return nil
+}
@@ -0,0 +1,263 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+)
+
+// IsSolved solves the R1CS with big.Int arithmetic modulo modulus, the inputs values being read from assignment,
+// and returns the first unsatisfied constraint as a *backend.UnsatisfiedConstraintError.
+//
+// It mirrors the Solve method of the curve specific R1CS, for the fields which have no backend,
+// eg the base field of a curve when the circuit verifies a proof or a signature on this curve.
+func (r1cs *R1CS) IsSolved(assignment backend.Assignments, modulus *big.Int) error {
+	s := r1csSolver{
+		r1cs:         r1cs,
+		modulus:      modulus,
+		values:       make([]big.Int, r1cs.NbWires),
+		instantiated: make([]bool, r1cs.NbWires),
+	}
+
+	// instantiate the private and public inputs
+	privateOffset := r1cs.NbWires - r1cs.NbPublicWires - r1cs.NbPrivateWires
+	if err := s.instantiateInputs(assignment, privateOffset, backend.Secret, r1cs.PrivateWires); err != nil {
+		return err
+	}
+	publicOffset := r1cs.NbWires - r1cs.NbPublicWires
+	if err := s.instantiateInputs(assignment, publicOffset, backend.Public, r1cs.PublicWires); err != nil {
+		return err
+	}
+
+	var check big.Int
+	for i := range r1cs.Constraints {
+		r1c := &r1cs.Constraints[i]
+
+		// the first NbCOConstraints constraints have exactly one uncomputed wire
+		if i < r1cs.NbCOConstraints {
+			if err := s.solve(r1c); err != nil {
+				if i < len(r1cs.CallSites) {
+					return fmt.Errorf("constraint %d: %w at %s", i, err, r1cs.CallSites[i])
+				}
+				return fmt.Errorf("constraint %d: %w", i, err)
+			}
+		}
+
+		a, b, c := s.value(r1c.L), s.value(r1c.R), s.value(r1c.O)
+		check.Mul(a, b).Mod(&check, modulus)
+		if check.Cmp(c) != 0 {
+			err := &backend.UnsatisfiedConstraintError{
+				ConstraintID: i,
+				L:            s.linearExpressionString(r1c.L),
+				R:            s.linearExpressionString(r1c.R),
+				O:            s.linearExpressionString(r1c.O),
+			}
+			err.A.Set(a)
+			err.B.Set(b)
+			err.C.Set(c)
+			if i < len(r1cs.CallSites) {
+				err.CallSite = r1cs.CallSites[i]
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// r1csSolver holds the wires values of a R1CS being solved over big.Int
+type r1csSolver struct {
+	r1cs         *R1CS
+	modulus      *big.Int
+	values       []big.Int
+	instantiated []bool
+}
+
+func (s *r1csSolver) instantiateInputs(assignment backend.Assignments, offset int, visibility backend.Visibility, names []string) error {
+	for i, name := range names {
+		if name == backend.OneWire {
+			s.values[offset+i].SetUint64(1)
+			s.instantiated[offset+i] = true
+			continue
+		}
+		val, ok := assignment[name]
+		if !ok {
+			return fmt.Errorf("%q: %w", name, backend.ErrInputNotSet)
+		}
+		if visibility == backend.Secret && val.IsPublic || visibility == backend.Public && !val.IsPublic {
+			return fmt.Errorf("%q: %w", name, backend.ErrInputVisiblity)
+		}
+		s.values[offset+i].Mod(&val.Value, s.modulus)
+		s.instantiated[offset+i] = true
+	}
+	return nil
+}
+
+// value returns the value of l, all its wires being instantiated
+func (s *r1csSolver) value(l LinearExpression) *big.Int {
+	var res, tmp big.Int
+	for _, t := range l {
+		tmp.Mul(&t.Coeff, &s.values[t.ID])
+		res.Add(&res, &tmp)
+	}
+	return res.Mod(&res, s.modulus)
+}
+
+// solve computes the uninstantiated wire(s) of r1c, it fails only if r1c is solved by a hint which fails
+func (s *r1csSolver) solve(r1c *R1C) error {
+	switch r1c.Solver {
+
+	// isolate the uncomputed wire: a*b = c, one of a, b, c being k*w + the instantiated terms
+	case SingleOutput:
+		var sums [3]big.Int
+		location, id := -1, int64(-1)
+		var coeff big.Int
+		for j, l := range [3]LinearExpression{r1c.L, r1c.R, r1c.O} {
+			for _, t := range l {
+				if s.instantiated[t.ID] {
+					var tmp big.Int
+					tmp.Mul(&t.Coeff, &s.values[t.ID])
+					sums[j].Add(&sums[j], &tmp)
+				} else {
+					location, id = j, t.ID
+					coeff.Set(&t.Coeff)
+				}
+			}
+			sums[j].Mod(&sums[j], s.modulus)
+		}
+		if location == -1 {
+			return nil
+		}
+
+		var res big.Int
+		switch location {
+		case 0, 1:
+			// k*w = c/b - a (or c/a - b), w being 0 if the other factor is 0
+			other := &sums[1-location]
+			if other.Sign() == 0 {
+				break
+			}
+			res.ModInverse(other, s.modulus).
+				Mul(&res, &sums[2]).
+				Sub(&res, &sums[location])
+		case 2:
+			// k*w = a*b - c
+			res.Mul(&sums[0], &sums[1]).Sub(&res, &sums[2])
+		}
+		var inv big.Int
+		inv.ModInverse(coeff.Mod(&coeff, s.modulus), s.modulus)
+		s.values[id].Mul(&res, &inv).Mod(&s.values[id], s.modulus)
+		s.instantiated[id] = true
+
+	// binary decomposition of O[0], the bits being the wires of L
+	case BinaryDec:
+		n := &s.values[r1c.O[0].ID]
+		for i, t := range r1c.L {
+			if !s.instantiated[t.ID] {
+				s.values[t.ID].SetUint64(uint64(n.Bit(i)))
+				s.instantiated[t.ID] = true
+			}
+		}
+
+	// x*m = 1 - res, m being the inverse of x, or 0 if x == 0
+	case IsZero:
+		x := s.value(r1c.L)
+		m := r1c.R[0].ID
+		if x.Sign() == 0 {
+			s.values[m].SetUint64(0)
+		} else {
+			s.values[m].ModInverse(x, s.modulus)
+		}
+		s.instantiated[m] = true
+		return s.solve(&R1C{L: r1c.L, R: r1c.R, O: r1c.O, Solver: SingleOutput})
+
+	// the outputs O are computed by the hint from the inputs L, except the ones already instantiated
+	// (eg an output constrained to be equal to an input), checked by the other constraints
+	case Hint:
+		inputs := make([]*big.Int, len(r1c.L))
+		for i, t := range r1c.L {
+			inputs[i] = new(big.Int).Set(&s.values[t.ID])
+		}
+		outputs, err := SolveHint(r1c.HintID, s.modulus, inputs, len(r1c.O))
+		if err != nil {
+			return err
+		}
+		for i, t := range r1c.O {
+			if !s.instantiated[t.ID] {
+				s.values[t.ID].Set(outputs[i])
+				s.instantiated[t.ID] = true
+			}
+		}
+
+	default:
+		panic("unimplemented solving method")
+	}
+	return nil
+}
+
+// linearExpressionString renders l with the wire names, the coefficients being printed in [-q/2, q/2]
+func (s *r1csSolver) linearExpressionString(l LinearExpression) string {
+	if len(l) == 0 {
+		return "0"
+	}
+	var halfModulus big.Int
+	halfModulus.Rsh(s.modulus, 1)
+
+	var sb strings.Builder
+	for i, t := range l {
+		var coeff big.Int
+		coeff.Mod(&t.Coeff, s.modulus)
+		if coeff.Cmp(&halfModulus) > 0 {
+			coeff.Sub(&coeff, s.modulus)
+		}
+		switch {
+		case i == 0 && coeff.Sign() < 0:
+			sb.WriteString("-")
+		case i > 0 && coeff.Sign() < 0:
+			sb.WriteString(" - ")
+		case i > 0:
+			sb.WriteString(" + ")
+		}
+		coeff.Abs(&coeff)
+		if !coeff.IsInt64() || coeff.Int64() != 1 {
+			sb.WriteString(coeff.String() + "*")
+		}
+		sb.WriteString(s.wireName(int(t.ID)))
+	}
+	return sb.String()
+}
+
+// wireName returns the name of an input wire, the tags of an intermediate wire, or its ID
+func (s *r1csSolver) wireName(id int) string {
+	r1cs := s.r1cs
+	privateOffset := r1cs.NbWires - r1cs.NbPublicWires - r1cs.NbPrivateWires
+	publicOffset := r1cs.NbWires - r1cs.NbPublicWires
+	switch {
+	case id >= publicOffset && id-publicOffset < len(r1cs.PublicWires):
+		return r1cs.PublicWires[id-publicOffset]
+	case id >= privateOffset && id-privateOffset < len(r1cs.PrivateWires):
+		return r1cs.PrivateWires[id-privateOffset]
+	case len(r1cs.WireTags[id]) > 0:
+		return strings.Join(r1cs.WireTags[id], "|")
+	}
+	return "wire_" + strconv.Itoa(id)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestR1CSIsSolved(t *testing.T) {
+	assert := require.New(t)
+
+	// a small prime, such that the field has no backend
+	modulus := big.NewInt(1000003)
+
+	cs := New()
+	x := cs.SECRET_INPUT("x")
+	y := cs.PUBLIC_INPUT("y")
+	z := cs.DIV(cs.MUL(x, x, 3), y)
+	cs.MUSTBE_EQ(cs.ADD(z, cs.IS_ZERO(x)), *big.NewInt(12))
+	bits := cs.TO_BINARY(x, 4)
+	cs.MUSTBE_EQ(cs.FROM_BINARY(bits[0], bits[1], bits[2], bits[3]), x)
+	r1cs := cs.ToR1CS()
+
+	// 3*x²/y + (x == 0) == 12
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x", 4)
+	good.Assign(backend.Public, "y", 4)
+	assert.NoError(r1cs.IsSolved(good, modulus))
+
+	// 3*x²/y + (x == 0) != 12
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 4)
+	bad.Assign(backend.Public, "y", 5)
+	err := r1cs.IsSolved(bad, modulus)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint))
+	var unsatisfied *backend.UnsatisfiedConstraintError
+	assert.True(errors.As(err, &unsatisfied))
+	// the call site doesn't depend on the location of the sources
+	assert.True(strings.HasPrefix(unsatisfied.CallSite, "github.com/consensys/gnark/frontend/r1cs_solver_test.go:"), unsatisfied.CallSite)
+
+	// x doesn't fit on 4 bits
+	bad = backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 16)
+	bad.Assign(backend.Public, "y", 64)
+	assert.True(errors.Is(r1cs.IsSolved(bad, modulus), backend.ErrUnsatisfiedConstraint))
+
+	// missing input
+	bad = backend.NewAssignment()
+	bad.Assign(backend.Public, "y", 4)
+	assert.True(errors.Is(r1cs.IsSolved(bad, modulus), backend.ErrInputNotSet))
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontend
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+)
+
+// SparseR1CS describes a set of PLONK constraints
+// each constraint is of the form qL⋅a + qR⋅b + qM⋅(a⋅b) + qO⋅c + qC = 0
+//
+// the wires of the R1CS from which the SparseR1CS is derived are kept with the same IDs,
+// the internal wires introduced to split the linear expressions come after them.
+// the first NbPublicWires constraints are of the form -a = 0, a being the i-th public wire:
+// they are satisfied by adding the public input value to the constraint (PLONK public input polynomial)
+type SparseR1CS struct {
+	// R1CS used to solve the wires shared by the two representations
+	R1CS R1CS
+
+	// Wires
+	NbWires int // includes the R1CS wires
+
+	// Constraints
+	NbConstraints int
+	Constraints   []SparseR1C
+}
+
+// SparseR1C used to compute the wires
+// qL⋅a + qR⋅b + qM⋅(a⋅b) + qO⋅c + qC = 0
+type SparseR1C struct {
+	L, R, O            int64 // IDs of the wires a, b, c
+	QL, QR, QM, QO, QC big.Int
+}
+
+// ToSparseR1CS builds a SparseR1CS (PLONK arithmetization) from a system of Constraints
+func (cs *CS) ToSparseR1CS() *SparseR1CS {
+	return cs.ToR1CS().ToSparseR1CS()
+}
+
+// ToSparseR1CS converts a R1CS into a SparseR1CS
+//
+// each R1C (l0+Σli⋅xi)⋅(r0+Σri⋅yi) = (o0+Σoi⋅zi) yields one constraint, once the linear expressions
+// with more than one wire are reduced to a single internal wire through a chain of addition constraints
+func (r1cs *R1CS) ToSparseR1CS() *SparseR1CS {
+	s := &SparseR1CS{
+		R1CS:    *r1cs,
+		NbWires: r1cs.NbWires,
+	}
+
+	// the ONE_WIRE terms are moved in the constant selector
+	oneWireID := int64(-1)
+	for i, name := range r1cs.PublicWires {
+		if name == backend.OneWire {
+			oneWireID = int64(r1cs.NbWires - r1cs.NbPublicWires + i)
+		}
+	}
+	filler := oneWireID
+
+	// public inputs
+	for i := 0; i < r1cs.NbPublicWires; i++ {
+		c := SparseR1C{L: int64(r1cs.NbWires - r1cs.NbPublicWires + i), R: filler, O: filler}
+		c.QL.SetInt64(-1)
+		s.Constraints = append(s.Constraints, c)
+	}
+
+	// reduce returns k, coeff, id such that le == k + coeff⋅wire[id]
+	// if the linear expression is constant, id is set to -1
+	// the linear expressions appearing several times in the R1CS (binary decompositions...) are reduced once
+	reduced := make(map[string]int64)
+	reduce := func(le LinearExpression) (k, coeff big.Int, id int64) {
+		var terms []TermR1cs
+		for _, t := range le {
+			if t.ID == oneWireID {
+				k.Add(&k, &t.Coeff)
+			} else {
+				terms = append(terms, t)
+			}
+		}
+		switch len(terms) {
+		case 0:
+			return k, coeff, -1
+		case 1:
+			coeff.Set(&terms[0].Coeff)
+			return k, coeff, terms[0].ID
+		}
+		coeff.SetInt64(1)
+
+		var key strings.Builder
+		for _, t := range terms {
+			key.WriteString(strconv.FormatInt(t.ID, 10))
+			key.WriteByte(':')
+			key.WriteString(t.Coeff.String())
+			key.WriteByte(',')
+		}
+		if id, ok := reduced[key.String()]; ok {
+			return k, coeff, id
+		}
+
+		// acc = t0 + t1, then acc = acc + ti
+		acc := SparseR1C{L: terms[0].ID, R: terms[1].ID, O: int64(s.NbWires)}
+		acc.QL.Set(&terms[0].Coeff)
+		acc.QR.Set(&terms[1].Coeff)
+		acc.QO.SetInt64(-1)
+		s.Constraints = append(s.Constraints, acc)
+		s.NbWires++
+		for i := 2; i < len(terms); i++ {
+			c := SparseR1C{L: int64(s.NbWires - 1), R: terms[i].ID, O: int64(s.NbWires)}
+			c.QL.SetInt64(1)
+			c.QR.Set(&terms[i].Coeff)
+			c.QO.SetInt64(-1)
+			s.Constraints = append(s.Constraints, c)
+			s.NbWires++
+		}
+		reduced[key.String()] = int64(s.NbWires - 1)
+		return k, coeff, int64(s.NbWires - 1)
+	}
+
+	for _, r1c := range r1cs.Constraints {
+		// the R1C of a hint is always satisfied, it only tells the R1CS solver to call the hint
+		if r1c.Solver == Hint {
+			continue
+		}
+		kL, cL, idL := reduce(r1c.L)
+		kR, cR, idR := reduce(r1c.R)
+		kO, cO, idO := reduce(r1c.O)
+
+		// (kL + cL⋅a)⋅(kR + cR⋅b) = kO + cO⋅c
+		// cL⋅cR⋅ab + cL⋅kR⋅a + kL⋅cR⋅b - cO⋅c + kL⋅kR - kO = 0
+		c := SparseR1C{L: idL, R: idR, O: idO}
+		c.QM.Mul(&cL, &cR)
+		c.QL.Mul(&cL, &kR)
+		c.QR.Mul(&kL, &cR)
+		c.QO.Neg(&cO)
+		c.QC.Mul(&kL, &kR).Sub(&c.QC, &kO)
+		if c.L == -1 {
+			c.L = filler
+		}
+		if c.R == -1 {
+			c.R = filler
+		}
+		if c.O == -1 {
+			c.O = filler
+		}
+		s.Constraints = append(s.Constraints, c)
+	}
+
+	s.NbConstraints = len(s.Constraints)
+
+	return s
+}
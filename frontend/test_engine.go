@@ -0,0 +1,348 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+)
+
+var (
+	ErrNotTestEngine = errors.New("constraint system was not created with NewTestEngine")
+)
+
+// testEngine holds the values of the wires of a constraint system created with NewTestEngine
+type testEngine struct {
+	modulus    big.Int
+	assignment backend.Assignments
+	values     map[*wire]big.Int
+	failure    *failure // first unsatisfied assertion
+	namespace  string   // path of the current namespace of the constraint system, for the call sites
+}
+
+// failure records an unsatisfied assertion, the error message is built once the circuit is defined
+// such that the wires have their tags
+type failure struct {
+	assertion string
+	wires     []*wire
+	values    []big.Int
+	callSite  string
+	err       error // set instead of the above if the failure is not an assertion (eg a missing input)
+}
+
+// NewTestEngine returns a constraint system which, in addition to recording the constraints,
+// evaluates them as they are defined, over big.Int modulo modulus.
+// The inputs values are read from assignment.
+//
+// No R1CS is built and no curve is involved: once the circuit is defined, Inspect returns the values
+// of the tagged variables and the first unsatisfied assertion, with the Go call site that created it.
+func NewTestEngine(modulus *big.Int, assignment backend.Assignments) CS {
+	cs := New()
+	cs.engine = &testEngine{
+		assignment: assignment,
+		values:     make(map[*wire]big.Int),
+	}
+	cs.engine.modulus.Set(modulus)
+	cs.engine.values[cs.Constraints[0].outputWire] = bigOne()
+	return cs
+}
+
+// IsSolved executes the circuit with the test engine (see NewTestEngine), the inputs values being
+// the ones assigned to the Variable of the circuit, and returns the first unsatisfied assertion if any
+func IsSolved(circuit Circuit, modulus *big.Int) error {
+	assignment, err := ToAssignment(circuit)
+	if err != nil {
+		return err
+	}
+
+	cs := NewTestEngine(modulus, assignment)
+	if err := cs.allocateInputs(circuit); err != nil {
+		return err
+	}
+	if err := circuit.Define(&cs); err != nil {
+		return err
+	}
+
+	_, err = cs.Inspect(false)
+	return err
+}
+
+// Inspect returns the tagged variables with their corresponding value, computed by the test engine.
+// If showsInputs is set, it also puts in the resulting map the inputs (public and private).
+// The returned error describes the first unsatisfied assertion, it wraps backend.ErrUnsatisfiedConstraint
+func (cs *CS) Inspect(showsInputs bool) (map[string]big.Int, error) {
+	if cs.engine == nil {
+		return nil, ErrNotTestEngine
+	}
+
+	res := make(map[string]big.Int)
+	for _, c := range cs.Constraints {
+		w := c.outputWire
+		if showsInputs && w.isUserInput() && w.Name != backend.OneWire {
+			res[w.Name] = cs.engine.values[w]
+		}
+		for _, tag := range w.Tags {
+			if _, ok := res[tag]; ok {
+				return nil, backend.ErrDuplicateTag(tag)
+			}
+			res[tag] = cs.engine.values[w]
+		}
+	}
+
+	if cs.engine.failure != nil {
+		return res, cs.engine.failure.error()
+	}
+	return res, nil
+}
+
+func (f *failure) error() error {
+	if f.err != nil {
+		return f.err
+	}
+	operands := make([]string, len(f.wires))
+	for i, w := range f.wires {
+		operands[i] = describeWire(w) + " = " + f.values[i].String()
+	}
+	return fmt.Errorf("%w: %s (%s) at %s", backend.ErrUnsatisfiedConstraint, f.assertion, strings.Join(operands, ", "), f.callSite)
+}
+
+// describeWire returns the name of an input, or the tags of an intermediate wire
+func describeWire(w *wire) string {
+	switch {
+	case w.isUserInput():
+		return w.Name
+	case len(w.Tags) > 0:
+		return strings.Join(w.Tags, "|")
+	}
+	return "<untagged>"
+}
+
+// fail records the first unsatisfied assertion
+func (e *testEngine) fail(assertion string, wires ...*wire) {
+	if e.failure != nil {
+		return
+	}
+	f := &failure{
+		assertion: assertion,
+		wires:     wires,
+		values:    make([]big.Int, len(wires)),
+		callSite:  inNamespace(callSite(), e.namespace),
+	}
+	for i, w := range wires {
+		f.values[i] = e.values[w]
+	}
+	e.failure = f
+}
+
+// setInput sets the value of an input wire from the assignment
+func (e *testEngine) setInput(w *wire, visibility backend.Visibility) {
+	val, ok := e.assignment[w.Name]
+	switch {
+	case !ok:
+		if e.failure == nil {
+			e.failure = &failure{err: fmt.Errorf("%q: %w", w.Name, backend.ErrInputNotSet)}
+		}
+		return
+	case visibility == backend.Secret && val.IsPublic || visibility == backend.Public && !val.IsPublic:
+		if e.failure == nil {
+			e.failure = &failure{err: fmt.Errorf("%q: %w", w.Name, backend.ErrInputVisiblity)}
+		}
+	}
+	e.set(w, val.Value)
+}
+
+func (e *testEngine) set(w *wire, v big.Int) {
+	v.Mod(&v, &e.modulus)
+	e.values[w] = v
+}
+
+func (e *testEngine) value(w *wire) *big.Int {
+	v := e.values[w]
+	return &v
+}
+
+func (e *testEngine) linearValue(l linearExpression) *big.Int {
+	var res, tmp big.Int
+	for _, t := range l {
+		tmp.Mul(&t.Coeff, e.value(t.Wire))
+		res.Add(&res, &tmp)
+	}
+	return res.Mod(&res, &e.modulus)
+}
+
+// inverse returns 1/x, or 0 and false if x is 0
+func (e *testEngine) inverse(x *big.Int) (*big.Int, bool) {
+	var res big.Int
+	if new(big.Int).Mod(x, &e.modulus).Sign() == 0 {
+		return &res, false
+	}
+	res.ModInverse(x, &e.modulus)
+	return &res, true
+}
+
+// compute sets the value of w, defined by the single output expression exp
+func (e *testEngine) compute(w *wire, exp expression) {
+	var res big.Int
+	switch ex := exp.(type) {
+	case *term:
+		res.Mul(&ex.Coeff, e.value(ex.Wire))
+		if ex.Operation == div {
+			inv, ok := e.inverse(&res)
+			if !ok {
+				e.fail("division by zero", ex.Wire)
+			}
+			res.Set(inv)
+		}
+	case *linearExpression:
+		res.Set(e.linearValue(*ex))
+	case *quadraticExpression:
+		left, right := e.linearValue(ex.left), e.linearValue(ex.right)
+		if ex.operation == mul {
+			res.Mul(left, right)
+		} else {
+			inv, ok := e.inverse(left)
+			if !ok {
+				e.fail("division by zero", wiresOf(ex.left)...)
+			}
+			res.Mul(right, inv)
+		}
+	case *selectExpression:
+		// y - b(y-x)
+		res.Sub(e.value(ex.y), e.value(ex.x)).
+			Mul(&res, e.value(ex.b)).
+			Sub(e.value(ex.y), &res)
+	case *xorExpression:
+		// a + b - 2ab
+		a, b := e.value(ex.a), e.value(ex.b)
+		res.Mul(a, b).Lsh(&res, 1).Neg(&res).Add(&res, a).Add(&res, b)
+	case *packExpression:
+		for i := len(ex.bits) - 1; i >= 0; i-- {
+			res.Lsh(&res, 1).Add(&res, e.value(ex.bits[i]))
+		}
+	case *eqConstantExpression:
+		res.Set(&ex.v)
+	case *lutExpression:
+		// b0*(t0 + t1*b1) - (t2 + t3*b1), cf lutExpression.toR1CS
+		var t0, t1, t2, t3, tmp big.Int
+		t0.Sub(&ex.lookuptable[1], &ex.lookuptable[0])
+		t1.Sub(&ex.lookuptable[0], &ex.lookuptable[1]).
+			Sub(&t1, &ex.lookuptable[2]).
+			Add(&t1, &ex.lookuptable[3])
+		t2.Neg(&ex.lookuptable[0])
+		t3.Sub(&ex.lookuptable[0], &ex.lookuptable[2])
+		b0, b1 := e.value(ex.b0), e.value(ex.b1)
+		res.Mul(&t1, b1).Add(&res, &t0).Mul(&res, b0)
+		tmp.Mul(&t3, b1).Add(&tmp, &t2)
+		res.Sub(&res, &tmp)
+	default:
+		panic("test engine: unsupported expression " + exp.string())
+	}
+	e.set(w, res)
+}
+
+// solve sets the values of the output wires of a multi output expression
+func (e *testEngine) solve(exp moExpression) {
+	switch ex := exp.(type) {
+	case *unpackExpression:
+		v := e.value(ex.res)
+		for i, b := range ex.bits {
+			e.set(b, *big.NewInt(int64(v.Bit(i))))
+		}
+		if v.BitLen() > len(ex.bits) {
+			e.fail("binary decomposition on "+strconv.Itoa(len(ex.bits))+" bits", ex.res)
+		}
+	case *isZeroExpression:
+		m, isNonZero := e.inverse(e.value(ex.x))
+		e.set(ex.m, *m)
+		if isNonZero {
+			e.set(ex.res, *big.NewInt(0))
+		} else {
+			e.set(ex.res, bigOne())
+		}
+	case *hintExpression:
+		inputs := make([]*big.Int, len(ex.inputs))
+		for i, w := range ex.inputs {
+			inputs[i] = e.value(w)
+		}
+		outputs, err := SolveHint(ex.id, &e.modulus, inputs, len(ex.outputs))
+		if err != nil {
+			if e.failure == nil {
+				e.failure = &failure{err: fmt.Errorf("%w at %s", err, inNamespace(callSite(), e.namespace))}
+			}
+			return
+		}
+		for i, w := range ex.outputs {
+			e.set(w, *outputs[i])
+		}
+	default:
+		panic("test engine: unsupported expression " + exp.string())
+	}
+}
+
+// check checks an assertion (expression yielding no output)
+func (e *testEngine) check(exp expression) {
+	var res big.Int
+	switch ex := exp.(type) {
+	case *booleanExpression:
+		// (1-b)*b == 0
+		b := e.value(ex.b)
+		res.Sub(big.NewInt(1), b).Mul(&res, b)
+		if res.Mod(&res, &e.modulus).Sign() != 0 {
+			e.fail("MUSTBE_BOOLEAN", ex.b)
+		}
+	case *implyExpression:
+		// (1-b-a)*a == 0
+		a, b := e.value(ex.a), e.value(ex.b)
+		res.Sub(big.NewInt(1), b).Sub(&res, a).Mul(&res, a)
+		if res.Mod(&res, &e.modulus).Sign() != 0 {
+			e.fail("MUSTBE_LESS_OR_EQ", ex.b, ex.a)
+		}
+	case *zeroProductExpression:
+		res.Mul(e.value(ex.a), e.value(ex.b))
+		if res.Mod(&res, &e.modulus).Sign() != 0 {
+			e.fail("IS_ZERO", ex.a, ex.b)
+		}
+	default:
+		panic("test engine: unsupported expression " + exp.string())
+	}
+}
+
+func (e *testEngine) checkEqual(w1, w2 *wire) {
+	if e.value(w1).Cmp(e.value(w2)) != 0 {
+		e.fail("MUSTBE_EQ", w1, w2)
+	}
+}
+
+func (e *testEngine) checkEqualConstant(w *wire, constant big.Int) {
+	constant.Mod(&constant, &e.modulus)
+	if e.value(w).Cmp(&constant) != 0 {
+		e.fail("MUSTBE_EQ "+constant.String(), w)
+	}
+}
+
+func wiresOf(l linearExpression) []*wire {
+	res := make([]*wire, len(l))
+	for i, t := range l {
+		res[i] = t.Wire
+	}
+	return res
+}
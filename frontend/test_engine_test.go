@@ -0,0 +1,191 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"math/big"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gurvy/bn256/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestEngineValues(t *testing.T) {
+	assert := require.New(t)
+
+	assignment := backend.NewAssignment()
+	assignment.Assign(backend.Secret, "x", 6)
+	assignment.Assign(backend.Public, "y", 4)
+	assignment.Assign(backend.Secret, "b", 1)
+
+	cs := NewTestEngine(fr.ElementModulus(), assignment)
+
+	x := cs.SECRET_INPUT("x")
+	y := cs.PUBLIC_INPUT("y")
+	b := cs.SECRET_INPUT("b")
+
+	cs.ADD(x, y, 3).Tag("add")
+	cs.SUB(x, y).Tag("sub")
+	cs.SUB(y, x).Tag("sub_neg")
+	cs.MUL(x, y, 2).Tag("mul")
+	cs.DIV(x, y).Tag("div")
+	cs.INV(y).Tag("inv")
+	cs.SELECT(b, x, y).Tag("select")
+	cs.SELECT(cs.NOT(b), x, y).Tag("select_not")
+	cs.XOR(b, cs.IS_ZERO(x)).Tag("xor")
+	cs.IS_EQUAL(x, 6).Tag("is_equal")
+	cs.IS_LESS(x, y, 8).Tag("is_less")
+	cs.CMP(y, x, 8).Tag("cmp")
+	bits := cs.TO_BINARY(x, 4)
+	cs.FROM_BINARY(bits[1], bits[2]).Tag("from_binary")
+	cs.SELECT_LUT(b, cs.ALLOCATE(0), [4]big.Int{*big.NewInt(10), *big.NewInt(11), *big.NewInt(12), *big.NewInt(13)}).Tag("lut")
+	cs.MUSTBE_EQ(cs.MUL(x, 2), cs.ADD(x, x))
+
+	values, err := cs.Inspect(true)
+	assert.NoError(err)
+
+	var half, minusTwo, minusOne big.Int
+	half.ModInverse(big.NewInt(4), fr.ElementModulus())
+	minusTwo.Sub(fr.ElementModulus(), big.NewInt(2))
+	minusOne.Sub(fr.ElementModulus(), big.NewInt(1))
+
+	expected := map[string]big.Int{
+		"x":           *big.NewInt(6),
+		"y":           *big.NewInt(4),
+		"b":           *big.NewInt(1),
+		"add":         *big.NewInt(13),
+		"sub":         *big.NewInt(2),
+		"sub_neg":     minusTwo,
+		"mul":         *big.NewInt(48),
+		"div":         *new(big.Int).Mod(new(big.Int).Mul(&half, big.NewInt(6)), fr.ElementModulus()),
+		"inv":         half,
+		"select":      *big.NewInt(6),
+		"select_not":  *big.NewInt(4),
+		"xor":         *big.NewInt(1),
+		"is_equal":    *big.NewInt(1),
+		"is_less":     *big.NewInt(0),
+		"cmp":         minusOne,
+		"from_binary": *big.NewInt(3),
+		"lut":         *big.NewInt(12),
+	}
+	assert.Equal(len(expected), len(values))
+	for k, v := range expected {
+		got := values[k]
+		assert.Equal(0, v.Cmp(&got), "%s: expected %s, got %s", k, v.String(), got.String())
+	}
+}
+
+func TestTestEngineFailures(t *testing.T) {
+	assert := require.New(t)
+
+	assignment := backend.NewAssignment()
+	assignment.Assign(backend.Secret, "x", 2)
+	assignment.Assign(backend.Public, "y", 5)
+
+	// MUSTBE_EQ, the tags and the call site are reported
+	cs := NewTestEngine(fr.ElementModulus(), assignment)
+	x := cs.SECRET_INPUT("x")
+	y := cs.PUBLIC_INPUT("y")
+	square := cs.MUL(x, x)
+	square.Tag("square")
+	cs.MUSTBE_EQ(square, y)
+	_, _, line, _ := runtime.Caller(0)
+	cs.MUSTBE_BOOLEAN(y) // not reported, only the first failure is
+
+	_, err := cs.Inspect(false)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint))
+	assert.Contains(err.Error(), "MUSTBE_EQ")
+	assert.Contains(err.Error(), "square = 4")
+	assert.Contains(err.Error(), "y = 5")
+	assert.Contains(err.Error(), "test_engine_test.go:"+strconv.Itoa(line-1))
+	assert.False(strings.Contains(err.Error(), "MUSTBE_BOOLEAN"))
+
+	// MUSTBE_BOOLEAN
+	cs = NewTestEngine(fr.ElementModulus(), assignment)
+	x = cs.SECRET_INPUT("x")
+	cs.XOR(x, cs.ALLOCATE(1))
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint))
+	assert.Contains(err.Error(), "MUSTBE_BOOLEAN (x = 2)")
+
+	// MUSTBE_LESS_OR_EQ
+	cs = NewTestEngine(fr.ElementModulus(), assignment)
+	y = cs.PUBLIC_INPUT("y")
+	cs.MUSTBE_LESS_OR_EQ(y, 4, 256)
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint))
+	assert.Contains(err.Error(), "MUSTBE_LESS_OR_EQ")
+
+	// range check
+	cs = NewTestEngine(fr.ElementModulus(), assignment)
+	y = cs.PUBLIC_INPUT("y")
+	cs.MUSTBE_IN_RANGE(y, 2)
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint))
+	assert.Contains(err.Error(), "binary decomposition on 2 bits (y = 5)")
+
+	// division by zero
+	cs = NewTestEngine(fr.ElementModulus(), assignment)
+	x = cs.SECRET_INPUT("x")
+	cs.DIV(x, cs.SUB(x, x))
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint))
+	assert.Contains(err.Error(), "division by zero")
+
+	// missing input and wrong visibility
+	cs = NewTestEngine(fr.ElementModulus(), assignment)
+	cs.SECRET_INPUT("z")
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, backend.ErrInputNotSet))
+
+	cs = NewTestEngine(fr.ElementModulus(), assignment)
+	cs.SECRET_INPUT("y")
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, backend.ErrInputVisiblity))
+
+	// not a test engine
+	cs = New()
+	_, err = cs.Inspect(false)
+	assert.True(errors.Is(err, ErrNotTestEngine))
+}
+
+func TestIsSolved(t *testing.T) {
+	assert := require.New(t)
+
+	// B == (A + P0.X + P1.Y + Secrets) * P0.Y
+	var circuit testCircuit
+	circuit.A.Assign(1)
+	circuit.B.Assign(64)
+	for i := 0; i < len(circuit.Points); i++ {
+		circuit.Points[i].X.Assign(2)
+		circuit.Points[i].Y.Assign(4)
+	}
+	circuit.Secrets = make([]Variable, 1)
+	circuit.Secrets[0].Assign(9)
+	assert.NoError(IsSolved(&circuit, fr.ElementModulus()))
+
+	circuit.Secrets = make([]Variable, 1)
+	circuit.Secrets[0].Assign(10)
+	err := IsSolved(&circuit, fr.ElementModulus())
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint))
+	assert.Contains(err.Error(), "B = 64")
+}
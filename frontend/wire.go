@@ -32,6 +32,7 @@ type wire struct {
 	IsPrivate    bool
 	IsConsumed   bool     // if false it means it is the last wire of the computational graph
 	Tags         []string // if debug is set, the variable can be displayed once the wires are computed
+	isBoolean    bool     // set if the wire is boolean constrained (explicitly or by construction, e.g. a xor)
 }
 
 func (w wire) isUserInput() bool {
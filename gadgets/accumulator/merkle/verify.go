@@ -55,13 +55,22 @@ import (
 // TODO this shouldn't be a constant, but don't know how to to avoid passing it to every function
 const nbBits = 256
 
+// operands returns the bits as operands of FROM_BINARY
+func operands(bits []*frontend.Constraint) []interface{} {
+	res := make([]interface{}, len(bits))
+	for i := range bits {
+		res[i] = bits[i]
+	}
+	return res
+}
+
 // leafSum returns the hash created from data inserted to form a leaf. Leaf
 // sums are calculated using:
 //		Hash(0x00 || data)
 func leafSumDeprecated(circuit *frontend.CS, h mimc.MiMCGadget, data *frontend.Constraint) *frontend.Constraint {
 
 	// TODO find a better way than querying the binary decomposition, too many constraints
-	dataBin := circuit.TO_BINARY(data, nbBits)
+	dataBin := operands(circuit.TO_BINARY(data, nbBits))
 
 	// prepending 0x00 means the first chunk to be hashed will consist of the first 31 bytes
 	d1 := circuit.FROM_BINARY(dataBin[8:]...)
@@ -90,8 +99,8 @@ func leafSum(circuit *frontend.CS, h mimc.MiMCGadget, data *frontend.Constraint)
 func nodeSumDeprecated(circuit *frontend.CS, h mimc.MiMCGadget, a, b *frontend.Constraint) *frontend.Constraint {
 
 	// TODO find a better way than querying the binary decomposition (too many constraints)
-	d1Bin := circuit.TO_BINARY(a, nbBits)
-	d2Bin := circuit.TO_BINARY(b, nbBits)
+	d1Bin := operands(circuit.TO_BINARY(a, nbBits))
+	d2Bin := operands(circuit.TO_BINARY(b, nbBits))
 
 	// multiplying by shifter shifts a number by 31*8 bits
 	var shifter big.Int
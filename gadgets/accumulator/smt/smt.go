@@ -0,0 +1,58 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package smt provides the gadgets verifying the proofs of the sparse Merkle trees
+// of crypto/accumulator/smt, the nodes being computed with MiMC.
+package smt
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets/hash/mimc"
+)
+
+// VerifyProof checks that leaf is the leaf of key in the tree of root root,
+// siblings being the siblings of the path of key from the leaf to the root.
+// The depth of the tree is len(siblings), key must fit on len(siblings) bits.
+func VerifyProof(circuit *frontend.CS, h mimc.MiMCGadget, root, key, leaf *frontend.Constraint, siblings []*frontend.Constraint) {
+	path := circuit.TO_BINARY(key, len(siblings))
+	circuit.MUSTBE_EQ(computeRoot(circuit, h, path, leaf, siblings), root)
+}
+
+// VerifyNonInclusion checks that key is not set in the tree of root root, ie that its leaf is empty
+func VerifyNonInclusion(circuit *frontend.CS, h mimc.MiMCGadget, root, key *frontend.Constraint, siblings []*frontend.Constraint) {
+	VerifyProof(circuit, h, root, key, circuit.ALLOCATE(0), siblings)
+}
+
+// VerifyUpdate checks that setting the leaf of key from oldLeaf to newLeaf changes the root
+// of the tree from oldRoot to newRoot, the siblings being shared by the two paths.
+// An insertion is an update from the empty leaf (0), a removal an update to the empty leaf.
+func VerifyUpdate(circuit *frontend.CS, h mimc.MiMCGadget, oldRoot, newRoot, key, oldLeaf, newLeaf *frontend.Constraint, siblings []*frontend.Constraint) {
+	path := circuit.TO_BINARY(key, len(siblings))
+	circuit.MUSTBE_EQ(computeRoot(circuit, h, path, oldLeaf, siblings), oldRoot)
+	circuit.MUSTBE_EQ(computeRoot(circuit, h, path, newLeaf, siblings), newRoot)
+}
+
+// computeRoot returns the root of the tree in which the leaf of path is leaf,
+// the node being the left child at height i if path[i] is 0
+func computeRoot(circuit *frontend.CS, h mimc.MiMCGadget, path []*frontend.Constraint, leaf *frontend.Constraint, siblings []*frontend.Constraint) *frontend.Constraint {
+	res := leaf
+	for i := range siblings {
+		left := circuit.SELECT(path[i], siblings[i], res)
+		right := circuit.SELECT(path[i], res, siblings[i])
+		res = h.Hash(circuit, left, right)
+	}
+	return res
+}
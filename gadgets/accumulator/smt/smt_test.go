@@ -0,0 +1,198 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smt
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	"github.com/consensys/gnark/crypto/accumulator/smt"
+	mimc_bn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets/hash/mimc"
+	"github.com/consensys/gurvy"
+)
+
+const depth = 4
+
+// leaf returns a non empty leaf
+func leaf(v byte) []byte {
+	res := make([]byte, 32)
+	res[31] = v
+	return res
+}
+
+// newTree returns a tree of depth depth with a few keys set
+func newTree(t *testing.T) *smt.Tree {
+	tree, err := smt.New(mimc_bn256.NewMiMC("seed"), depth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []uint64{0, 3, 9, 15} {
+		if err := tree.Set(k, leaf(byte(k+1))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tree
+}
+
+// allocateSiblings allocates the siblings of a proof as secret inputs
+func allocateSiblings(circuit *frontend.CS) []*frontend.Constraint {
+	res := make([]*frontend.Constraint, depth)
+	for i := range res {
+		res[i] = circuit.SECRET_INPUT("sibling" + strconv.Itoa(i))
+	}
+	return res
+}
+
+// assignSiblings assigns the siblings of a proof
+func assignSiblings(assignment backend.Assignments, siblings [][]byte) {
+	for i := range siblings {
+		assignment.Assign(backend.Secret, "sibling"+strconv.Itoa(i), new(big.Int).SetBytes(siblings[i]))
+	}
+}
+
+func TestVerifyProof(t *testing.T) {
+
+	assert := groth16_bn256.NewAssert(t)
+
+	h, err := mimc.NewMiMCGadget("seed", gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := frontend.New()
+	root := circuit.PUBLIC_INPUT("root")
+	key := circuit.SECRET_INPUT("key")
+	l := circuit.SECRET_INPUT("leaf")
+	VerifyProof(&circuit, h, root, key, l, allocateSiblings(&circuit))
+	r1cs := backend_bn256.New(&circuit)
+
+	tree := newTree(t)
+	assign := func(key uint64, leaf []byte) backend.Assignments {
+		proof, err := tree.Prove(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := backend.NewAssignment()
+		res.Assign(backend.Public, "root", new(big.Int).SetBytes(tree.Root()))
+		res.Assign(backend.Secret, "key", key)
+		res.Assign(backend.Secret, "leaf", new(big.Int).SetBytes(leaf))
+		assignSiblings(res, proof.Siblings)
+		return res
+	}
+
+	// inclusion
+	assert.CorrectExecution(&r1cs, assign(9, leaf(10)), nil)
+
+	// non-inclusion, the leaf being empty
+	assert.CorrectExecution(&r1cs, assign(8, make([]byte, 32)), nil)
+
+	// wrong leaf
+	assert.NotSolved(&r1cs, assign(9, leaf(11)))
+	assert.NotSolved(&r1cs, assign(8, leaf(1)))
+
+	// the key must fit on depth bits
+	bad := assign(9, leaf(10))
+	delete(bad, "key")
+	bad.Assign(backend.Secret, "key", 9+(1<<depth))
+	assert.NotSolved(&r1cs, bad)
+}
+
+func TestVerifyNonInclusion(t *testing.T) {
+
+	assert := groth16_bn256.NewAssert(t)
+
+	h, err := mimc.NewMiMCGadget("seed", gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := frontend.New()
+	root := circuit.PUBLIC_INPUT("root")
+	key := circuit.PUBLIC_INPUT("key")
+	VerifyNonInclusion(&circuit, h, root, key, allocateSiblings(&circuit))
+	r1cs := backend_bn256.New(&circuit)
+
+	tree := newTree(t)
+	assign := func(key uint64) backend.Assignments {
+		proof, err := tree.Prove(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := backend.NewAssignment()
+		res.Assign(backend.Public, "root", new(big.Int).SetBytes(tree.Root()))
+		res.Assign(backend.Public, "key", key)
+		assignSiblings(res, proof.Siblings)
+		return res
+	}
+
+	assert.CorrectExecution(&r1cs, assign(8), nil)
+	assert.NotSolved(&r1cs, assign(9))
+}
+
+func TestVerifyUpdate(t *testing.T) {
+
+	assert := groth16_bn256.NewAssert(t)
+
+	h, err := mimc.NewMiMCGadget("seed", gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := frontend.New()
+	oldRoot := circuit.PUBLIC_INPUT("oldRoot")
+	newRoot := circuit.PUBLIC_INPUT("newRoot")
+	key := circuit.SECRET_INPUT("key")
+	oldLeaf := circuit.SECRET_INPUT("oldLeaf")
+	newLeaf := circuit.SECRET_INPUT("newLeaf")
+	VerifyUpdate(&circuit, h, oldRoot, newRoot, key, oldLeaf, newLeaf, allocateSiblings(&circuit))
+	r1cs := backend_bn256.New(&circuit)
+
+	tree := newTree(t)
+	assign := func(key uint64, leaf []byte) backend.Assignments {
+		res := backend.NewAssignment()
+		res.Assign(backend.Public, "oldRoot", new(big.Int).SetBytes(tree.Root()))
+		proof, err := tree.Update(key, leaf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Assign(backend.Public, "newRoot", new(big.Int).SetBytes(tree.Root()))
+		res.Assign(backend.Secret, "key", key)
+		res.Assign(backend.Secret, "oldLeaf", new(big.Int).SetBytes(proof.OldLeaf))
+		res.Assign(backend.Secret, "newLeaf", new(big.Int).SetBytes(proof.NewLeaf))
+		assignSiblings(res, proof.Siblings)
+		return res
+	}
+
+	// update, insertion and removal
+	assert.CorrectExecution(&r1cs, assign(9, leaf(7)), nil)
+	assert.CorrectExecution(&r1cs, assign(8, leaf(8)), nil)
+	assert.CorrectExecution(&r1cs, assign(3, make([]byte, 32)), nil)
+
+	// the new leaf doesn't match the new root
+	bad := assign(9, leaf(9))
+	delete(bad, "newLeaf")
+	bad.Assign(backend.Secret, "newLeaf", 10)
+	assert.NotSolved(&r1cs, bad)
+
+	// the roots are swapped
+	bad = assign(15, leaf(11))
+	bad["oldRoot"], bad["newRoot"] = bad["newRoot"], bad["oldRoot"]
+	assert.NotSolved(&r1cs, bad)
+}
@@ -0,0 +1,407 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls377
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy/bls377"
+	"github.com/consensys/gurvy/bls377/fp"
+	"github.com/consensys/gurvy/bls377/fr"
+)
+
+// tester builds a circuit with the test engine, modulo the base field of BLS12-377,
+// and records the expected values of the tagged constraints
+type tester struct {
+	circuit    frontend.CS
+	assignment backend.Assignments
+	expected   map[string]fp.Element
+}
+
+func newTester() *tester {
+	assignment := backend.NewAssignment()
+	return &tester{
+		circuit:    frontend.NewTestEngine(fp.ElementModulus(), assignment),
+		assignment: assignment,
+		expected:   make(map[string]fp.Element),
+	}
+}
+
+// input returns secret inputs name0, name1, ... set to values
+func (t *tester) input(name string, values ...fp.Element) []*frontend.Constraint {
+	res := make([]*frontend.Constraint, len(values))
+	for i := range values {
+		t.assignment.Assign(backend.Secret, name+strconv.Itoa(i), values[i])
+		res[i] = t.circuit.SECRET_INPUT(name + strconv.Itoa(i))
+	}
+	return res
+}
+
+func (t *tester) fp12Input(name string, e *bls377.PairingResult) Fp12Gadget {
+	c := t.input(name, fp12Values(e)...)
+	return Fp12Gadget{
+		C0: Fp6Gadget{Fp2Gadget{c[0], c[1]}, Fp2Gadget{c[2], c[3]}, Fp2Gadget{c[4], c[5]}},
+		C1: Fp6Gadget{Fp2Gadget{c[6], c[7]}, Fp2Gadget{c[8], c[9]}, Fp2Gadget{c[10], c[11]}},
+	}
+}
+
+func (t *tester) g1Input(name string, p *bls377.G1Affine) G1Gadget {
+	c := t.input(name, p.X, p.Y)
+	return G1Gadget{c[0], c[1]}
+}
+
+func (t *tester) g2Input(name string, p *bls377.G2Affine) G2Gadget {
+	c := t.input(name, p.X.A0, p.X.A1, p.Y.A0, p.Y.A1)
+	return G2Gadget{Fp2Gadget{c[0], c[1]}, Fp2Gadget{c[2], c[3]}}
+}
+
+// expect tags the constraints and records their expected values
+func (t *tester) expect(tag string, constraints []*frontend.Constraint, values []fp.Element) {
+	for i := range constraints {
+		constraints[i].Tag(tag + strconv.Itoa(i))
+		t.expected[tag+strconv.Itoa(i)] = values[i]
+	}
+}
+
+func (t *tester) run(tt *testing.T) {
+	values, err := t.circuit.Inspect(false)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	for tag, v := range t.expected {
+		var expected big.Int
+		v.ToBigIntRegular(&expected)
+		if value := values[tag]; value.Cmp(&expected) != 0 {
+			tt.Error(tag, "expected", expected.String(), "got", value.String())
+		}
+	}
+}
+
+// fp12Values returns the coordinates of e over Fp: C0.B0.A0, C0.B0.A1, C0.B1.A0, ...
+func fp12Values(e *bls377.PairingResult) []fp.Element {
+	return []fp.Element{
+		e.C0.B0.A0, e.C0.B0.A1, e.C0.B1.A0, e.C0.B1.A1, e.C0.B2.A0, e.C0.B2.A1,
+		e.C1.B0.A0, e.C1.B0.A1, e.C1.B1.A0, e.C1.B1.A1, e.C1.B2.A0, e.C1.B2.A1,
+	}
+}
+
+// fp12Constraints returns the coordinates of e over Fp, in the same order as fp12Values
+func fp12Constraints(e *Fp12Gadget) []*frontend.Constraint {
+	return []*frontend.Constraint{
+		e.C0.B0.A0, e.C0.B0.A1, e.C0.B1.A0, e.C0.B1.A1, e.C0.B2.A0, e.C0.B2.A1,
+		e.C1.B0.A0, e.C1.B0.A1, e.C1.B1.A0, e.C1.B1.A1, e.C1.B2.A0, e.C1.B2.A1,
+	}
+}
+
+func randomG1G2() (bls377.G1Affine, bls377.G2Affine) {
+	c := bls377.BLS377()
+	var s fr.Element
+	var pj bls377.G1Jac
+	var qj bls377.G2Jac
+	var p bls377.G1Affine
+	var q bls377.G2Affine
+	pj.ScalarMulByGen(c, *s.SetRandom()).ToAffineFromJac(&p)
+	qj.ScalarMulByGen(c, *s.SetRandom()).ToAffineFromJac(&q)
+	return p, q
+}
+
+func TestFieldTower(t *testing.T) {
+	test := newTester()
+	circuit := &test.circuit
+
+	var a, b bls377.PairingResult
+	a.SetRandom()
+	b.SetRandom()
+	A := test.fp12Input("a", &a)
+	B := test.fp12Input("b", &b)
+
+	// the Fp2 and Fp6 operations are done on the first coordinates of Fp12 elements
+	var r bls377.PairingResult
+	var R Fp12Gadget
+
+	r.C0.B0.Mul(&a.C0.B0, &b.C0.B0)
+	R.C0.B0.Mul(circuit, &A.C0.B0, &B.C0.B0)
+	test.expect("fp2_mul", fp12Constraints(&R)[:2], fp12Values(&r)[:2])
+
+	r.C0.B0.Square(&a.C0.B0)
+	R.C0.B0.Square(circuit, &A.C0.B0)
+	test.expect("fp2_square", fp12Constraints(&R)[:2], fp12Values(&r)[:2])
+
+	r.C0.B0.Inverse(&a.C0.B0)
+	R.C0.B0.Inverse(circuit, &A.C0.B0)
+	test.expect("fp2_inverse", fp12Constraints(&R)[:2], fp12Values(&r)[:2])
+
+	r.C0.B0.Inverse(&b.C0.B0).Mul(&r.C0.B0, &a.C0.B0)
+	R.C0.B0.Div(circuit, &A.C0.B0, &B.C0.B0)
+	test.expect("fp2_div", fp12Constraints(&R)[:2], fp12Values(&r)[:2])
+
+	r.C0.Mul(&a.C0, &b.C0)
+	R.C0.Mul(circuit, &A.C0, &B.C0)
+	test.expect("fp6_mul", fp12Constraints(&R)[:6], fp12Values(&r)[:6])
+
+	r.C0.Square(&a.C0)
+	R.C0.Square(circuit, &A.C0)
+	test.expect("fp6_square", fp12Constraints(&R)[:6], fp12Values(&r)[:6])
+
+	r.C0.Inverse(&a.C0)
+	R.C0.Inverse(circuit, &A.C0)
+	test.expect("fp6_inverse", fp12Constraints(&R)[:6], fp12Values(&r)[:6])
+
+	r.Mul(&a, &b)
+	R.Mul(circuit, &A, &B)
+	test.expect("fp12_mul", fp12Constraints(&R), fp12Values(&r))
+
+	r.Square(&a)
+	R.Square(circuit, &A)
+	test.expect("fp12_square", fp12Constraints(&R), fp12Values(&r))
+
+	r.Inverse(&a)
+	R.Inverse(circuit, &A)
+	test.expect("fp12_inverse", fp12Constraints(&R), fp12Values(&r))
+
+	r.Conjugate(&a)
+	R.Conjugate(circuit, &A)
+	test.expect("fp12_conjugate", fp12Constraints(&R), fp12Values(&r))
+
+	r.Frobenius(&a)
+	R.Frobenius(circuit, &A)
+	test.expect("fp12_frobenius", fp12Constraints(&R), fp12Values(&r))
+
+	r.FrobeniusSquare(&a)
+	R.FrobeniusSquare(circuit, &A)
+	test.expect("fp12_frobenius_square", fp12Constraints(&R), fp12Values(&r))
+
+	r.FrobeniusCube(&a)
+	R.FrobeniusCube(circuit, &A)
+	test.expect("fp12_frobenius_cube", fp12Constraints(&R), fp12Values(&r))
+
+	r.Expt(&a)
+	R.Expt(circuit, &A)
+	test.expect("fp12_expt", fp12Constraints(&R), fp12Values(&r))
+
+	test.run(t)
+}
+
+func TestFinalExponentiation(t *testing.T) {
+	test := newTester()
+
+	var a bls377.PairingResult
+	a.SetRandom()
+	A := test.fp12Input("a", &a)
+
+	var R Fp12Gadget
+	R.FinalExponentiation(&test.circuit, &A)
+	r := bls377.BLS377().FinalExponentiation(&a)
+	test.expect("final_exponentiation", fp12Constraints(&R), fp12Values(&r))
+
+	test.run(t)
+}
+
+func TestG1(t *testing.T) {
+	test := newTester()
+	circuit := &test.circuit
+	c := bls377.BLS377()
+
+	p1, _ := randomG1G2()
+	p2, _ := randomG1G2()
+	P1 := test.g1Input("p1", &p1)
+	P2 := test.g1Input("p2", &p2)
+	P1.MustBeOnCurve(circuit)
+	P2.MustBeOnCurve(circuit)
+
+	var j bls377.G1Jac
+	var r bls377.G1Affine
+	var R G1Gadget
+
+	p1.ToJacobian(&j).AddMixed(&p2)
+	j.ToAffineFromJac(&r)
+	R.Add(circuit, &P1, &P2)
+	test.expect("add", []*frontend.Constraint{R.X, R.Y}, []fp.Element{r.X, r.Y})
+
+	p1.ToJacobian(&j).Double()
+	j.ToAffineFromJac(&r)
+	R.Double(circuit, &P1)
+	test.expect("double", []*frontend.Constraint{R.X, R.Y}, []fp.Element{r.X, r.Y})
+
+	// p1 + s*p2, for s random and s = 0
+	var s fr.Element
+	s.SetRandom()
+	var p2j, sp2 bls377.G1Jac
+	p2.ToJacobian(&p2j)
+	sp2.ScalarMul(c, &p2j, *new(fr.Element).Set(&s).FromMont()) // the scalar is given in regular form
+	p1.ToJacobian(&j).Add(c, &sp2)
+	j.ToAffineFromJac(&r)
+	R.ScalarMulAdd(circuit, &P1, &P2, s, fr.ElementModulus().BitLen())
+	test.expect("scalar_mul_add", []*frontend.Constraint{R.X, R.Y}, []fp.Element{r.X, r.Y})
+
+	R.ScalarMulAdd(circuit, &P1, &P2, 0, fr.ElementModulus().BitLen())
+	test.expect("scalar_mul_add_zero", []*frontend.Constraint{R.X, R.Y}, []fp.Element{p1.X, p1.Y})
+
+	test.run(t)
+
+	// adding a point to itself is unsatisfiable, the slope would be free
+	test = newTester()
+	P1 = test.g1Input("p1", &p1)
+	R.Add(&test.circuit, &P1, &P1)
+	if _, err := test.circuit.Inspect(false); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("expected an unsatisfied constraint, got", err)
+	}
+
+	// a point of the subgroup
+	test = newTester()
+	P1 = test.g1Input("p1", &p1)
+	P1.MustBeInSubGroup(&test.circuit)
+	test.run(t)
+
+	// a point on the curve, not in the subgroup (the cofactor is large)
+	var p3 bls377.G1Affine
+	var rhs fp.Element
+	for x := uint64(1); ; x++ {
+		p3.X.SetUint64(x)
+		rhs.Square(&p3.X).Mul(&rhs, &p3.X).Add(&rhs, new(fp.Element).SetOne())
+		if p3.Y.Sqrt(&rhs) != nil {
+			break
+		}
+	}
+	test = newTester()
+	P3 := test.g1Input("p3", &p3)
+	P3.MustBeOnCurve(&test.circuit)
+	P3.MustBeInSubGroup(&test.circuit)
+	if _, err := test.circuit.Inspect(false); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("expected an unsatisfied constraint, got", err)
+	}
+
+	// a point not on the curve
+	test = newTester()
+	p1.Y.Double(&p1.Y)
+	P1 = test.g1Input("p1", &p1)
+	P1.MustBeOnCurve(&test.circuit)
+	if _, err := test.circuit.Inspect(false); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("expected an unsatisfied constraint, got", err)
+	}
+}
+
+func TestG2(t *testing.T) {
+	test := newTester()
+	circuit := &test.circuit
+
+	_, q1 := randomG1G2()
+	_, q2 := randomG1G2()
+	Q1 := test.g2Input("q1", &q1)
+	Q2 := test.g2Input("q2", &q2)
+	Q1.MustBeOnCurve(circuit)
+	Q2.MustBeOnCurve(circuit)
+
+	var j bls377.G2Jac
+	var r bls377.G2Affine
+	var R G2Gadget
+
+	q1.ToJacobian(&j).AddMixed(&q2)
+	j.ToAffineFromJac(&r)
+	R.Add(circuit, &Q1, &Q2)
+	test.expect("add", []*frontend.Constraint{R.X.A0, R.X.A1, R.Y.A0, R.Y.A1}, []fp.Element{r.X.A0, r.X.A1, r.Y.A0, r.Y.A1})
+
+	q1.ToJacobian(&j).Double()
+	j.ToAffineFromJac(&r)
+	R.Double(circuit, &Q1)
+	test.expect("double", []*frontend.Constraint{R.X.A0, R.X.A1, R.Y.A0, R.Y.A1}, []fp.Element{r.X.A0, r.X.A1, r.Y.A0, r.Y.A1})
+
+	test.run(t)
+
+	// a point of the subgroup
+	test = newTester()
+	Q1 = test.g2Input("q1", &q1)
+	Q1.MustBeInSubGroup(&test.circuit)
+	test.run(t)
+
+	// a point on the twist, not in the subgroup (the cofactor is large): x = k, y = √(k³ + u/5)
+	var q3 bls377.G2Affine
+	var b, half, n, t0, t1 fp.Element
+	b.SetUint64(nonResidue).Inverse(&b)
+	half.SetUint64(2).Inverse(&half)
+	for x := uint64(1); ; x++ {
+		q3.X.A0.SetUint64(x)
+		q3.X.A1.SetZero()
+
+		// √(a0 + a1u) = c0 + c1u, c0 = √((a0 ± √(a0² - 5a1²))/2), c1 = a1/2c0
+		a0, a1 := new(fp.Element).Square(&q3.X.A0), b
+		a0.Mul(a0, &q3.X.A0)
+		t0.Square(a0)
+		t1.Square(&a1).Mul(&t1, new(fp.Element).SetUint64(nonResidue))
+		if n.Sqrt(t0.Sub(&t0, &t1)) == nil {
+			continue
+		}
+		t0.Add(a0, &n).Mul(&t0, &half)
+		if q3.Y.A0.Sqrt(&t0) == nil {
+			t0.Sub(a0, &n).Mul(&t0, &half)
+			if q3.Y.A0.Sqrt(&t0) == nil {
+				continue
+			}
+		}
+		t0.Double(&q3.Y.A0).Inverse(&t0)
+		q3.Y.A1.Mul(&a1, &t0)
+		break
+	}
+	test = newTester()
+	Q3 := test.g2Input("q3", &q3)
+	Q3.MustBeOnCurve(&test.circuit)
+	if _, err := test.circuit.Inspect(false); err != nil {
+		t.Fatal("the point should be on the twist", err)
+	}
+	test = newTester()
+	Q3 = test.g2Input("q3", &q3)
+	Q3.MustBeInSubGroup(&test.circuit)
+	if _, err := test.circuit.Inspect(false); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("expected an unsatisfied constraint, got", err)
+	}
+
+	// a point not on the twist
+	test = newTester()
+	q1.Y.A1.Double(&q1.Y.A1)
+	Q1 = test.g2Input("q1", &q1)
+	Q1.MustBeOnCurve(&test.circuit)
+	if _, err := test.circuit.Inspect(false); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("expected an unsatisfied constraint, got", err)
+	}
+}
+
+func TestPairing(t *testing.T) {
+	test := newTester()
+	c := bls377.BLS377()
+
+	// e(p1, q1) * e(p2, q2)
+	p1, q1 := randomG1G2()
+	p2, q2 := randomG1G2()
+	P := []G1Gadget{test.g1Input("p1", &p1), test.g1Input("p2", &p2)}
+	Q := []G2Gadget{test.g2Input("q1", &q1), test.g2Input("q2", &q2)}
+
+	R := MillerLoop(&test.circuit, P, Q)
+	R.FinalExponentiation(&test.circuit, &R)
+
+	var ml1, ml2 bls377.PairingResult
+	c.MillerLoop(p1, q1, &ml1)
+	c.MillerLoop(p2, q2, &ml2)
+	r := c.FinalExponentiation(&ml1, &ml2)
+	test.expect("pairing", fp12Constraints(&R), fp12Values(&r))
+
+	test.run(t)
+}
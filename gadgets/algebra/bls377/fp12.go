@@ -0,0 +1,263 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls377
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy/bls377"
+	"github.com/consensys/gurvy/bls377/fp"
+)
+
+// Fp12Gadget element of Fp12 (in r1cs form): C0 + C1*w
+type Fp12Gadget struct {
+	C0, C1 Fp6Gadget
+}
+
+// NewFp12Gadget creates a new instance of Fp12Gadget, set to the constant e
+func NewFp12Gadget(circuit *frontend.CS, e bls377.PairingResult) Fp12Gadget {
+	newFp2 := func(a0, a1 fp.Element) Fp2Gadget {
+		return NewFp2Gadget(circuit, a0, a1)
+	}
+	return Fp12Gadget{
+		C0: Fp6Gadget{
+			B0: newFp2(e.C0.B0.A0, e.C0.B0.A1),
+			B1: newFp2(e.C0.B1.A0, e.C0.B1.A1),
+			B2: newFp2(e.C0.B2.A0, e.C0.B2.A1),
+		},
+		C1: Fp6Gadget{
+			B0: newFp2(e.C1.B0.A0, e.C1.B0.A1),
+			B1: newFp2(e.C1.B1.A0, e.C1.B1.A1),
+			B2: newFp2(e.C1.B2.A0, e.C1.B2.A1),
+		},
+	}
+}
+
+// NewFp12GadgetOne creates a new instance of Fp12Gadget, set to 1
+func NewFp12GadgetOne(circuit *frontend.CS) Fp12Gadget {
+	var one bls377.PairingResult
+	one.SetOne()
+	return NewFp12Gadget(circuit, one)
+}
+
+// Add sets e to e1+e2 and returns e
+func (e *Fp12Gadget) Add(circuit *frontend.CS, e1, e2 *Fp12Gadget) *Fp12Gadget {
+	e.C0.Add(circuit, &e1.C0, &e2.C0)
+	e.C1.Add(circuit, &e1.C1, &e2.C1)
+	return e
+}
+
+// Sub sets e to e1-e2 and returns e
+func (e *Fp12Gadget) Sub(circuit *frontend.CS, e1, e2 *Fp12Gadget) *Fp12Gadget {
+	e.C0.Sub(circuit, &e1.C0, &e2.C0)
+	e.C1.Sub(circuit, &e1.C1, &e2.C1)
+	return e
+}
+
+// Mul sets e to e1*e2 and returns e
+func (e *Fp12Gadget) Mul(circuit *frontend.CS, e1, e2 *Fp12Gadget) *Fp12Gadget {
+	// Karatsuba, cf Algorithm 20 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, c0, c1, tmp Fp6Gadget
+	t0.Mul(circuit, &e1.C0, &e2.C0)
+	t1.Mul(circuit, &e1.C1, &e2.C1)
+
+	// c1 = (c0+c1)(c0'+c1') - t0 - t1
+	c1.Add(circuit, &e1.C0, &e1.C1)
+	tmp.Add(circuit, &e2.C0, &e2.C1)
+	c1.Mul(circuit, &c1, &tmp).Sub(circuit, &c1, &t0).Sub(circuit, &c1, &t1)
+
+	// c0 = t0 + t1*v
+	c0.MulByNonResidue(circuit, &t1).Add(circuit, &c0, &t0)
+
+	e.C0, e.C1 = c0, c1
+	return e
+}
+
+// Square sets e to e1*e1 and returns e
+func (e *Fp12Gadget) Square(circuit *frontend.CS, e1 *Fp12Gadget) *Fp12Gadget {
+	// complex method: (c0+c1w)² = (c0+c1)(c0+c1v) - c0c1 - c0c1v + 2c0c1w
+	var c0c1, c0, c1, tmp Fp6Gadget
+	c0c1.Mul(circuit, &e1.C0, &e1.C1)
+
+	c0.Add(circuit, &e1.C0, &e1.C1)
+	tmp.MulByNonResidue(circuit, &e1.C1).Add(circuit, &tmp, &e1.C0)
+	c0.Mul(circuit, &c0, &tmp).Sub(circuit, &c0, &c0c1)
+	tmp.MulByNonResidue(circuit, &c0c1)
+	c0.Sub(circuit, &c0, &tmp)
+
+	c1.Add(circuit, &c0c1, &c0c1)
+
+	e.C0, e.C1 = c0, c1
+	return e
+}
+
+// Conjugate sets e to e1.C0 - e1.C1*w and returns e
+func (e *Fp12Gadget) Conjugate(circuit *frontend.CS, e1 *Fp12Gadget) *Fp12Gadget {
+	e.C0 = e1.C0
+	e.C1.Neg(circuit, &e1.C1)
+	return e
+}
+
+// Inverse sets e to 1/e1 and returns e
+func (e *Fp12Gadget) Inverse(circuit *frontend.CS, e1 *Fp12Gadget) *Fp12Gadget {
+	// Algorithm 23 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1 Fp6Gadget
+	t0.Square(circuit, &e1.C0)
+	t1.Square(circuit, &e1.C1)
+	t1.MulByNonResidue(circuit, &t1)
+	t0.Sub(circuit, &t0, &t1).Inverse(circuit, &t0)
+
+	var c0, c1 Fp6Gadget
+	c0.Mul(circuit, &e1.C0, &t0)
+	c1.Mul(circuit, &e1.C1, &t0).Neg(circuit, &c1)
+
+	e.C0, e.C1 = c0, c1
+	return e
+}
+
+// Frobenius sets e to e1**p and returns e
+func (e *Fp12Gadget) Frobenius(circuit *frontend.CS, e1 *Fp12Gadget) *Fp12Gadget {
+	return e.frobenius(circuit, e1, &frobenius[0], true)
+}
+
+// FrobeniusSquare sets e to e1**(p²) and returns e
+func (e *Fp12Gadget) FrobeniusSquare(circuit *frontend.CS, e1 *Fp12Gadget) *Fp12Gadget {
+	return e.frobenius(circuit, e1, &frobenius[1], false)
+}
+
+// FrobeniusCube sets e to e1**(p³) and returns e
+func (e *Fp12Gadget) FrobeniusCube(circuit *frontend.CS, e1 *Fp12Gadget) *Fp12Gadget {
+	return e.frobenius(circuit, e1, &frobenius[2], true)
+}
+
+// frobenius multiplies the coordinates of e1 by the powers of w**(p**k-1), conjugating them first if k is odd
+func (e *Fp12Gadget) frobenius(circuit *frontend.CS, e1 *Fp12Gadget, gamma *[6]big.Int, conjugate bool) *Fp12Gadget {
+	// w**i = v**(i/2) w**(i%2): C0.Bj is the coordinate of w**(2j), C1.Bj the one of w**(2j+1)
+	coordinates := [6]Fp2Gadget{e1.C0.B0, e1.C1.B0, e1.C0.B1, e1.C1.B1, e1.C0.B2, e1.C1.B2}
+	for i := range coordinates {
+		if conjugate {
+			coordinates[i].Conjugate(circuit, &coordinates[i])
+		}
+		if gamma[i].Cmp(big.NewInt(1)) != 0 {
+			coordinates[i].MulByFp(circuit, &coordinates[i], gamma[i])
+		}
+	}
+	e.C0.B0, e.C1.B0, e.C0.B1, e.C1.B1, e.C0.B2, e.C1.B2 = coordinates[0], coordinates[1], coordinates[2], coordinates[3], coordinates[4], coordinates[5]
+	return e
+}
+
+// Expt sets e to e1**t, t being the seed of BLS12-377, and returns e
+func (e *Fp12Gadget) Expt(circuit *frontend.CS, e1 *Fp12Gadget) *Fp12Gadget {
+	// t = 9586122913090633729 = 136227 * 2**46 + 1, the addition chain for 136227 being the one of gurvy
+	var res, x33 Fp12Gadget
+	res = *e1
+	for i := 0; i < 5; i++ {
+		res.Square(circuit, &res)
+	}
+	res.Mul(circuit, &res, e1)
+	x33 = res
+	for i := 0; i < 7; i++ {
+		res.Square(circuit, &res)
+	}
+	res.Mul(circuit, &res, &x33)
+	for i := 0; i < 4; i++ {
+		res.Square(circuit, &res)
+	}
+	res.Mul(circuit, &res, e1)
+	res.Square(circuit, &res)
+	res.Mul(circuit, &res, e1)
+
+	for i := 0; i < 46; i++ {
+		res.Square(circuit, &res)
+	}
+	res.Mul(circuit, &res, e1)
+
+	*e = res
+	return e
+}
+
+// FinalExponentiation sets e to e1**((p**12-1)/r) and returns e
+// (the hard part being computed as in gurvy, the result is actually the cube of it, which is fine as r != 3)
+func (e *Fp12Gadget) FinalExponentiation(circuit *frontend.CS, e1 *Fp12Gadget) *Fp12Gadget {
+	var res, t0, t1, t2, t3, t4, t5 Fp12Gadget
+
+	// easy part: e1**((p**6-1)(p**2+1)), e1**(p**6) being its conjugate
+	t0.Conjugate(circuit, e1)
+	res.Inverse(circuit, e1)
+	t0.Mul(circuit, &t0, &res)
+	res.FrobeniusSquare(circuit, &t0).Mul(circuit, &res, &t0)
+
+	// hard part, Algorithm 1 of https://eprint.iacr.org/2016/130.pdf
+	// res being unitary, its inverse is its conjugate
+	t0.Conjugate(circuit, &res).Square(circuit, &t0)
+	t5.Expt(circuit, &res)
+	t1.Square(circuit, &t5)
+	t3.Mul(circuit, &t0, &t5)
+
+	t0.Expt(circuit, &t3)
+	t2.Expt(circuit, &t0)
+	t4.Expt(circuit, &t2)
+
+	t4.Mul(circuit, &t1, &t4)
+	t1.Expt(circuit, &t4)
+	t3.Conjugate(circuit, &t3)
+	t1.Mul(circuit, &t3, &t1)
+	t1.Mul(circuit, &t1, &res)
+
+	t0.Mul(circuit, &t0, &res)
+	t0.FrobeniusCube(circuit, &t0)
+
+	t3.Conjugate(circuit, &res)
+	t4.Mul(circuit, &t3, &t4)
+	t4.Frobenius(circuit, &t4)
+
+	t5.Mul(circuit, &t2, &t5)
+	t5.FrobeniusSquare(circuit, &t5)
+
+	t5.Mul(circuit, &t5, &t0)
+	t5.Mul(circuit, &t5, &t4)
+	t5.Mul(circuit, &t5, &t1)
+
+	*e = t5
+	return e
+}
+
+// MustBeEqual constrains e to be equal to e1
+func (e *Fp12Gadget) MustBeEqual(circuit *frontend.CS, e1 *Fp12Gadget) {
+	e.C0.MustBeEqual(circuit, &e1.C0)
+	e.C1.MustBeEqual(circuit, &e1.C1)
+}
+
+// frobenius[k-1][i] = w**(i*(p**k-1)) = u**(i*(p**k-1)/6), for k = 1, 2, 3: these are elements of Fp
+var frobenius [3][6]big.Int
+
+func init() {
+	// u² = 5, so u**(i*(p**k-1)/6) = 5**(i*(p**k-1)/12)
+	p := fp.ElementModulus()
+	var pk, exponent big.Int
+	pk.SetUint64(1)
+	for k := 0; k < 3; k++ {
+		pk.Mul(&pk, p)
+		for i := 0; i < 6; i++ {
+			exponent.Sub(&pk, big.NewInt(1)).
+				Mul(&exponent, big.NewInt(int64(i))).
+				Div(&exponent, big.NewInt(12))
+			frobenius[k][i].Exp(big.NewInt(nonResidue), &exponent, p)
+		}
+	}
+}
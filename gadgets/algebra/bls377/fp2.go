@@ -0,0 +1,180 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bls377 provides gadgets for the arithmetic of BLS12-377: the Fp2, Fp6, Fp12 tower
+// over its base field Fp, the G1 and G2 groups, and the pairing.
+//
+// Fp being the scalar field of BW6-761, the elements of Fp are single constraints: the circuits
+// using this package are meant to be proven over BW6-761 (or tested modulo Fp, see frontend.NewTestEngine).
+// The tower is the one of github.com/consensys/gurvy/bls377, such that the results can be compared with it:
+// Fp2 = Fp[u]/(u²-5), Fp6 = Fp2[v]/(v³-u), Fp12 = Fp6[w]/(w²-v)
+package bls377
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Fp2Gadget element of Fp2 (in r1cs form): A0 + A1*u
+type Fp2Gadget struct {
+	A0, A1 *frontend.Constraint
+}
+
+// NewFp2Gadget creates a new instance of Fp2Gadget
+// if a0 and a1 are not of type frontend.Constraint they are allocated (ALLOCATE) in the circuit
+func NewFp2Gadget(circuit *frontend.CS, a0, a1 interface{}) Fp2Gadget {
+	return Fp2Gadget{
+		circuit.ALLOCATE(a0),
+		circuit.ALLOCATE(a1),
+	}
+}
+
+// Add sets e to e1+e2 and returns e
+func (e *Fp2Gadget) Add(circuit *frontend.CS, e1, e2 *Fp2Gadget) *Fp2Gadget {
+	a0 := circuit.ADD(e1.A0, e2.A0)
+	a1 := circuit.ADD(e1.A1, e2.A1)
+	e.A0, e.A1 = a0, a1
+	return e
+}
+
+// Sub sets e to e1-e2 and returns e
+func (e *Fp2Gadget) Sub(circuit *frontend.CS, e1, e2 *Fp2Gadget) *Fp2Gadget {
+	a0 := circuit.SUB(e1.A0, e2.A0)
+	a1 := circuit.SUB(e1.A1, e2.A1)
+	e.A0, e.A1 = a0, a1
+	return e
+}
+
+// Neg sets e to -e1 and returns e
+func (e *Fp2Gadget) Neg(circuit *frontend.CS, e1 *Fp2Gadget) *Fp2Gadget {
+	a0 := circuit.MUL(e1.A0, -1)
+	a1 := circuit.MUL(e1.A1, -1)
+	e.A0, e.A1 = a0, a1
+	return e
+}
+
+// Mul sets e to e1*e2 and returns e
+func (e *Fp2Gadget) Mul(circuit *frontend.CS, e1, e2 *Fp2Gadget) *Fp2Gadget {
+	// Karatsuba: (a+bu)*(c+du) = (ac+5bd) + ((a+b)(c+d)-ac-bd)u
+	ac := circuit.MUL(e1.A0, e2.A0)
+	bd := circuit.MUL(e1.A1, e2.A1)
+	abcd := circuit.MUL(
+		frontend.LinearCombination{term(e1.A0, 1), term(e1.A1, 1)},
+		frontend.LinearCombination{term(e2.A0, 1), term(e2.A1, 1)},
+	)
+	e.A0 = linear(circuit, term(ac, 1), term(bd, nonResidue))
+	e.A1 = linear(circuit, term(abcd, 1), term(ac, -1), term(bd, -1))
+	return e
+}
+
+// Square sets e to e1*e1 and returns e
+func (e *Fp2Gadget) Square(circuit *frontend.CS, e1 *Fp2Gadget) *Fp2Gadget {
+	// complex method: (a+bu)² = ((a+b)(a+5b)-6ab) + 2ab*u
+	ab2 := circuit.MUL(
+		frontend.LinearCombination{term(e1.A0, 2)},
+		frontend.LinearCombination{term(e1.A1, 1)},
+	)
+	t := circuit.MUL(
+		frontend.LinearCombination{term(e1.A0, 1), term(e1.A1, 1)},
+		frontend.LinearCombination{term(e1.A0, 1), term(e1.A1, nonResidue)},
+	)
+	e.A0 = linear(circuit, term(t, 1), term(ab2, -3))
+	e.A1 = ab2
+	return e
+}
+
+// MulByFp sets e to e1*c, c being an element of Fp (constraint or constant), and returns e
+func (e *Fp2Gadget) MulByFp(circuit *frontend.CS, e1 *Fp2Gadget, c interface{}) *Fp2Gadget {
+	a0 := circuit.MUL(e1.A0, c)
+	a1 := circuit.MUL(e1.A1, c)
+	e.A0, e.A1 = a0, a1
+	return e
+}
+
+// MulByNonResidue sets e to e1*u and returns e
+func (e *Fp2Gadget) MulByNonResidue(circuit *frontend.CS, e1 *Fp2Gadget) *Fp2Gadget {
+	a0 := circuit.MUL(e1.A1, nonResidue)
+	e.A0, e.A1 = a0, e1.A0
+	return e
+}
+
+// Conjugate sets e to the conjugate of e1 (A0 - A1*u) and returns e
+func (e *Fp2Gadget) Conjugate(circuit *frontend.CS, e1 *Fp2Gadget) *Fp2Gadget {
+	e.A0, e.A1 = e1.A0, circuit.MUL(e1.A1, -1)
+	return e
+}
+
+// Inverse sets e to 1/e1 and returns e, e1 is constrained to be non zero
+func (e *Fp2Gadget) Inverse(circuit *frontend.CS, e1 *Fp2Gadget) *Fp2Gadget {
+	// 1/(a+bu) = (a-bu)/(a²-5b²)
+	inv := e1.inverseNorm(circuit)
+	a0 := circuit.MUL(e1.A0, inv)
+	a1 := circuit.MUL(
+		frontend.LinearCombination{term(e1.A1, -1)},
+		frontend.LinearCombination{term(inv, 1)},
+	)
+	e.A0, e.A1 = a0, a1
+	return e
+}
+
+// Div sets e to e1/e2 and returns e, e2 is constrained to be non zero
+func (e *Fp2Gadget) Div(circuit *frontend.CS, e1, e2 *Fp2Gadget) *Fp2Gadget {
+	// e1/e2 = e1*conjugate(e2)/norm(e2), the division by the norm being done on each coordinate
+	var t, conjugate Fp2Gadget
+	conjugate.Conjugate(circuit, e2)
+	t.Mul(circuit, e1, &conjugate)
+	inv := e2.inverseNorm(circuit)
+	a0 := circuit.MUL(t.A0, inv)
+	a1 := circuit.MUL(t.A1, inv)
+	e.A0, e.A1 = a0, a1
+	return e
+}
+
+// MustBeEqual constrains e to be equal to e1
+func (e *Fp2Gadget) MustBeEqual(circuit *frontend.CS, e1 *Fp2Gadget) {
+	circuit.MUSTBE_EQ(e.A0, e1.A0)
+	circuit.MUSTBE_EQ(e.A1, e1.A1)
+}
+
+// norm returns a²-5b², the norm of a+bu
+func (e *Fp2Gadget) norm(circuit *frontend.CS) *frontend.Constraint {
+	aa := circuit.MUL(e.A0, e.A0)
+	bb := circuit.MUL(e.A1, e.A1)
+	return linear(circuit, term(aa, 1), term(bb, -nonResidue))
+}
+
+// inverseNorm returns 1/(a²-5b²), constraining a+bu to be non zero: dividing each coordinate
+// by the norm would leave the quotient free when both the dividend and the norm are 0
+func (e *Fp2Gadget) inverseNorm(circuit *frontend.CS) *frontend.Constraint {
+	return circuit.DIV(
+		frontend.LinearCombination{term(circuit.ALLOCATE(1), 1)},
+		frontend.LinearCombination{term(e.norm(circuit), 1)},
+	)
+}
+
+// nonResidue u² in Fp2
+const nonResidue = 5
+
+// term returns the term coeff*c of a linear combination
+func term(c *frontend.Constraint, coeff int64) frontend.Term {
+	return frontend.Term{Constraint: c, Coeff: *big.NewInt(coeff)}
+}
+
+// linear returns the sum of the terms, as a single constraint
+func linear(circuit *frontend.CS, terms ...frontend.Term) *frontend.Constraint {
+	return circuit.MUL(frontend.LinearCombination(terms), frontend.LinearCombination{term(circuit.ALLOCATE(1), 1)})
+}
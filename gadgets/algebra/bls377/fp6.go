@@ -0,0 +1,180 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls377
+
+import "github.com/consensys/gnark/frontend"
+
+// Fp6Gadget element of Fp6 (in r1cs form): B0 + B1*v + B2*v²
+type Fp6Gadget struct {
+	B0, B1, B2 Fp2Gadget
+}
+
+// Add sets e to e1+e2 and returns e
+func (e *Fp6Gadget) Add(circuit *frontend.CS, e1, e2 *Fp6Gadget) *Fp6Gadget {
+	e.B0.Add(circuit, &e1.B0, &e2.B0)
+	e.B1.Add(circuit, &e1.B1, &e2.B1)
+	e.B2.Add(circuit, &e1.B2, &e2.B2)
+	return e
+}
+
+// Sub sets e to e1-e2 and returns e
+func (e *Fp6Gadget) Sub(circuit *frontend.CS, e1, e2 *Fp6Gadget) *Fp6Gadget {
+	e.B0.Sub(circuit, &e1.B0, &e2.B0)
+	e.B1.Sub(circuit, &e1.B1, &e2.B1)
+	e.B2.Sub(circuit, &e1.B2, &e2.B2)
+	return e
+}
+
+// Neg sets e to -e1 and returns e
+func (e *Fp6Gadget) Neg(circuit *frontend.CS, e1 *Fp6Gadget) *Fp6Gadget {
+	e.B0.Neg(circuit, &e1.B0)
+	e.B1.Neg(circuit, &e1.B1)
+	e.B2.Neg(circuit, &e1.B2)
+	return e
+}
+
+// Mul sets e to e1*e2 and returns e
+func (e *Fp6Gadget) Mul(circuit *frontend.CS, e1, e2 *Fp6Gadget) *Fp6Gadget {
+	// Karatsuba, cf Algorithm 13 from https://eprint.iacr.org/2010/354.pdf
+	var t0, t1, t2, c0, c1, c2, tmp1, tmp2 Fp2Gadget
+	t0.Mul(circuit, &e1.B0, &e2.B0)
+	t1.Mul(circuit, &e1.B1, &e2.B1)
+	t2.Mul(circuit, &e1.B2, &e2.B2)
+
+	// c0 = t0 + ((b1+b2)(b1'+b2') - t1 - t2)*u
+	tmp1.Add(circuit, &e1.B1, &e1.B2)
+	tmp2.Add(circuit, &e2.B1, &e2.B2)
+	c0.Mul(circuit, &tmp1, &tmp2).Sub(circuit, &c0, &t1).Sub(circuit, &c0, &t2).MulByNonResidue(circuit, &c0).Add(circuit, &c0, &t0)
+
+	// c1 = (b0+b1)(b0'+b1') - t0 - t1 + t2*u
+	tmp1.Add(circuit, &e1.B0, &e1.B1)
+	tmp2.Add(circuit, &e2.B0, &e2.B1)
+	c1.Mul(circuit, &tmp1, &tmp2).Sub(circuit, &c1, &t0).Sub(circuit, &c1, &t1)
+	tmp1.MulByNonResidue(circuit, &t2)
+	c1.Add(circuit, &c1, &tmp1)
+
+	// c2 = (b0+b2)(b0'+b2') - t0 - t2 + t1
+	tmp1.Add(circuit, &e1.B0, &e1.B2)
+	tmp2.Add(circuit, &e2.B0, &e2.B2)
+	c2.Mul(circuit, &tmp1, &tmp2).Sub(circuit, &c2, &t0).Sub(circuit, &c2, &t2).Add(circuit, &c2, &t1)
+
+	e.B0, e.B1, e.B2 = c0, c1, c2
+	return e
+}
+
+// Square sets e to e1*e1 and returns e
+func (e *Fp6Gadget) Square(circuit *frontend.CS, e1 *Fp6Gadget) *Fp6Gadget {
+	// CH-SQR2, cf https://eprint.iacr.org/2006/471.pdf
+	var s0, s1, s2, s3, s4, c0, c1, c2 Fp2Gadget
+	s0.Square(circuit, &e1.B0)
+	s1.Mul(circuit, &e1.B0, &e1.B1).Add(circuit, &s1, &s1)
+	s2.Sub(circuit, &e1.B0, &e1.B1).Add(circuit, &s2, &e1.B2).Square(circuit, &s2)
+	s3.Mul(circuit, &e1.B1, &e1.B2).Add(circuit, &s3, &s3)
+	s4.Square(circuit, &e1.B2)
+
+	c0.MulByNonResidue(circuit, &s3).Add(circuit, &c0, &s0)
+	c1.MulByNonResidue(circuit, &s4).Add(circuit, &c1, &s1)
+	c2.Add(circuit, &s1, &s2).Add(circuit, &c2, &s3).Sub(circuit, &c2, &s0).Sub(circuit, &c2, &s4)
+
+	e.B0, e.B1, e.B2 = c0, c1, c2
+	return e
+}
+
+// MulByFp2 sets e to e1*c, c being an element of Fp2, and returns e
+func (e *Fp6Gadget) MulByFp2(circuit *frontend.CS, e1 *Fp6Gadget, c *Fp2Gadget) *Fp6Gadget {
+	e.B0.Mul(circuit, &e1.B0, c)
+	e.B1.Mul(circuit, &e1.B1, c)
+	e.B2.Mul(circuit, &e1.B2, c)
+	return e
+}
+
+// MulByNonResidue sets e to e1*v and returns e
+func (e *Fp6Gadget) MulByNonResidue(circuit *frontend.CS, e1 *Fp6Gadget) *Fp6Gadget {
+	var b0 Fp2Gadget
+	b0.MulByNonResidue(circuit, &e1.B2)
+	e.B0, e.B1, e.B2 = b0, e1.B0, e1.B1
+	return e
+}
+
+// Inverse sets e to 1/e1 and returns e
+func (e *Fp6Gadget) Inverse(circuit *frontend.CS, e1 *Fp6Gadget) *Fp6Gadget {
+	// Algorithm 17 from https://eprint.iacr.org/2010/354.pdf, as in gurvy
+	var t0, t1, t2, t3, t4, t5, c0, c1, c2, t6, tmp Fp2Gadget
+	t0.Square(circuit, &e1.B0)
+	t1.Square(circuit, &e1.B1)
+	t2.Square(circuit, &e1.B2)
+	t3.Mul(circuit, &e1.B0, &e1.B1)
+	t4.Mul(circuit, &e1.B0, &e1.B2)
+	t5.Mul(circuit, &e1.B1, &e1.B2)
+
+	// c0 = t0 - t5*u, c1 = t2*u - t3, c2 = t1 - t4
+	c0.MulByNonResidue(circuit, &t5).Sub(circuit, &t0, &c0)
+	c1.MulByNonResidue(circuit, &t2).Sub(circuit, &c1, &t3)
+	c2.Sub(circuit, &t1, &t4)
+
+	// t6 = 1/(b0*c0 + (b2*c1 + b1*c2)*u)
+	t6.Mul(circuit, &e1.B2, &c1)
+	tmp.Mul(circuit, &e1.B1, &c2)
+	t6.Add(circuit, &t6, &tmp).MulByNonResidue(circuit, &t6)
+	tmp.Mul(circuit, &e1.B0, &c0)
+	t6.Add(circuit, &t6, &tmp).Inverse(circuit, &t6)
+
+	e.B0.Mul(circuit, &c0, &t6)
+	e.B1.Mul(circuit, &c1, &t6)
+	e.B2.Mul(circuit, &c2, &t6)
+	return e
+}
+
+// MustBeEqual constrains e to be equal to e1
+func (e *Fp6Gadget) MustBeEqual(circuit *frontend.CS, e1 *Fp6Gadget) {
+	e.B0.MustBeEqual(circuit, &e1.B0)
+	e.B1.MustBeEqual(circuit, &e1.B1)
+	e.B2.MustBeEqual(circuit, &e1.B2)
+}
+
+// mulByFpV sets e to e1*c*v, c being an element of Fp, and returns e
+func (e *Fp6Gadget) mulByFpV(circuit *frontend.CS, e1 *Fp6Gadget, c *frontend.Constraint) *Fp6Gadget {
+	var b0, b1, b2 Fp2Gadget
+	b0.MulByNonResidue(circuit, &e1.B2).MulByFp(circuit, &b0, c)
+	b1.MulByFp(circuit, &e1.B0, c)
+	b2.MulByFp(circuit, &e1.B1, c)
+	e.B0, e.B1, e.B2 = b0, b1, b2
+	return e
+}
+
+// mulBy12 sets e to e1*(c1*v + c2*v²) and returns e
+func (e *Fp6Gadget) mulBy12(circuit *frontend.CS, e1 *Fp6Gadget, c1, c2 *Fp2Gadget) *Fp6Gadget {
+	var b0, b1, b2, tmp Fp2Gadget
+
+	// b0 = (b2*c1 + b1*c2)*u
+	b0.Mul(circuit, &e1.B2, c1)
+	tmp.Mul(circuit, &e1.B1, c2)
+	b0.Add(circuit, &b0, &tmp).MulByNonResidue(circuit, &b0)
+
+	// b1 = b0*c1 + b2*c2*u
+	b1.Mul(circuit, &e1.B0, c1)
+	tmp.Mul(circuit, &e1.B2, c2).MulByNonResidue(circuit, &tmp)
+	b1.Add(circuit, &b1, &tmp)
+
+	// b2 = b1*c1 + b0*c2
+	b2.Mul(circuit, &e1.B1, c1)
+	tmp.Mul(circuit, &e1.B0, c2)
+	b2.Add(circuit, &b2, &tmp)
+
+	e.B0, e.B1, e.B2 = b0, b1, b2
+	return e
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls377
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy/bls377/fr"
+)
+
+// G1Gadget point of G1 (in r1cs form), in affine coordinates: y² = x³ + 1
+//
+// The formulas are incomplete: the point at infinity can't be represented, and adding
+// two points which are equal or opposite is not supported (Double must be used instead):
+// Add constrains them to have distinct x
+type G1Gadget struct {
+	X, Y *frontend.Constraint
+}
+
+// NewG1Gadget creates a new instance of G1Gadget
+// if x and y are not of type frontend.Constraint they are allocated (ALLOCATE) in the circuit
+func NewG1Gadget(circuit *frontend.CS, x, y interface{}) G1Gadget {
+	return G1Gadget{
+		circuit.ALLOCATE(x),
+		circuit.ALLOCATE(y),
+	}
+}
+
+// Neg sets p to -p1 and returns p
+func (p *G1Gadget) Neg(circuit *frontend.CS, p1 *G1Gadget) *G1Gadget {
+	p.X, p.Y = p1.X, circuit.MUL(p1.Y, -1)
+	return p
+}
+
+// Add sets p to p1+p2 and returns p, p1 and p2 are constrained to have distinct x (x1 ≠ x2)
+func (p *G1Gadget) Add(circuit *frontend.CS, p1, p2 *G1Gadget) *G1Gadget {
+	// λ = (y2-y1)/(x2-x1), 1/(x2-x1) being computed first: (x2-x1)*λ = y2-y1 alone
+	// would leave λ free when x1 == x2 and y1 == y2
+	inv := circuit.DIV(
+		frontend.LinearCombination{term(circuit.ALLOCATE(1), 1)},
+		frontend.LinearCombination{term(p2.X, 1), term(p1.X, -1)},
+	)
+	lambda := circuit.MUL(
+		frontend.LinearCombination{term(p2.Y, 1), term(p1.Y, -1)},
+		frontend.LinearCombination{term(inv, 1)},
+	)
+	return p.addLine(circuit, p1, p2, lambda)
+}
+
+// Double sets p to 2*p1 and returns p
+func (p *G1Gadget) Double(circuit *frontend.CS, p1 *G1Gadget) *G1Gadget {
+	// λ = 3x²/2y
+	xx := circuit.MUL(p1.X, p1.X)
+	lambda := circuit.DIV(
+		frontend.LinearCombination{term(xx, 3)},
+		frontend.LinearCombination{term(p1.Y, 2)},
+	)
+	return p.addLine(circuit, p1, p1, lambda)
+}
+
+// addLine sets p to p1+p2, λ being the slope of the line through p1 and p2
+func (p *G1Gadget) addLine(circuit *frontend.CS, p1, p2 *G1Gadget, lambda *frontend.Constraint) *G1Gadget {
+	// x3 = λ² - x1 - x2, y3 = λ(x1 - x3) - y1
+	lambdaSquare := circuit.MUL(lambda, lambda)
+	x := linear(circuit, term(lambdaSquare, 1), term(p1.X, -1), term(p2.X, -1))
+	y := circuit.MUL(
+		frontend.LinearCombination{term(lambda, 1)},
+		frontend.LinearCombination{term(p1.X, 1), term(x, -1)},
+	)
+	y = circuit.SUB(y, p1.Y)
+	p.X, p.Y = x, y
+	return p
+}
+
+// ScalarMulAdd sets p to a + s*p1 and returns p, s being a scalar (constraint or constant) of at most nbBits bits
+//
+// s*p1 is computed with a double and add on the bits of 2**nbBits + s, such that s may be zero as long
+// as a is not a small multiple of p1
+func (p *G1Gadget) ScalarMulAdd(circuit *frontend.CS, a, p1 *G1Gadget, s interface{}, nbBits int) *G1Gadget {
+	bits := circuit.TO_BINARY(circuit.ALLOCATE(s), nbBits)
+
+	// acc = (2**nbBits + s)*p1, offset = 2**nbBits*p1
+	acc, offset := *p1, *p1
+	var sum G1Gadget
+	for i := nbBits - 1; i >= 0; i-- {
+		acc.Double(circuit, &acc)
+		sum.Add(circuit, &acc, p1)
+		acc.X = circuit.SELECT(bits[i], sum.X, acc.X)
+		acc.Y = circuit.SELECT(bits[i], sum.Y, acc.Y)
+		offset.Double(circuit, &offset)
+	}
+
+	// a + s*p1 = (a - offset) + acc
+	offset.Neg(circuit, &offset).Add(circuit, a, &offset)
+	return p.Add(circuit, &offset, &acc)
+}
+
+// MustBeOnCurve constrains p to be on the curve y² = x³ + 1
+func (p *G1Gadget) MustBeOnCurve(circuit *frontend.CS) {
+	xx := circuit.MUL(p.X, p.X)
+	xxx := circuit.MUL(xx, p.X)
+	yy := circuit.MUL(p.Y, p.Y)
+	circuit.MUSTBE_EQ(yy, circuit.ADD(xxx, 1))
+}
+
+// MustBeInSubGroup constrains p to be in the subgroup of order r (the scalar field of BLS12-377),
+// checking [r-1]p == -p with a double and add on the bits of r-1, p being on the curve (see MustBeOnCurve).
+// A point of another order makes an addition or a doubling of the double and add unsatisfiable, or the result differ
+func (p *G1Gadget) MustBeInSubGroup(circuit *frontend.CS) {
+	var rMinusOne big.Int
+	rMinusOne.Sub(fr.ElementModulus(), big.NewInt(1))
+
+	acc := *p
+	for i := rMinusOne.BitLen() - 2; i >= 0; i-- {
+		acc.Double(circuit, &acc)
+		if rMinusOne.Bit(i) == 1 {
+			acc.Add(circuit, &acc, p)
+		}
+	}
+
+	var neg G1Gadget
+	acc.MustBeEqual(circuit, neg.Neg(circuit, p))
+}
+
+// MustBeEqual constrains p to be equal to p1
+func (p *G1Gadget) MustBeEqual(circuit *frontend.CS, p1 *G1Gadget) {
+	circuit.MUSTBE_EQ(p.X, p1.X)
+	circuit.MUSTBE_EQ(p.Y, p1.Y)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls377
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy/bls377/fp"
+	"github.com/consensys/gurvy/bls377/fr"
+)
+
+// G2Gadget point of G2 (in r1cs form), in affine coordinates on the twist: y² = x³ + 1/u
+//
+// As for G1Gadget, the formulas are incomplete, the divisions constraining the points added to have distinct x
+type G2Gadget struct {
+	X, Y Fp2Gadget
+}
+
+// NewG2Gadget creates a new instance of G2Gadget
+func NewG2Gadget(x, y Fp2Gadget) G2Gadget {
+	return G2Gadget{x, y}
+}
+
+// Neg sets p to -p1 and returns p
+func (p *G2Gadget) Neg(circuit *frontend.CS, p1 *G2Gadget) *G2Gadget {
+	p.X = p1.X
+	p.Y.Neg(circuit, &p1.Y)
+	return p
+}
+
+// Add sets p to p1+p2 and returns p
+func (p *G2Gadget) Add(circuit *frontend.CS, p1, p2 *G2Gadget) *G2Gadget {
+	p.add(circuit, p1, p2)
+	return p
+}
+
+// Double sets p to 2*p1 and returns p
+func (p *G2Gadget) Double(circuit *frontend.CS, p1 *G2Gadget) *G2Gadget {
+	p.double(circuit, p1)
+	return p
+}
+
+// add sets p to p1+p2 and returns the slope of the line through p1 and p2
+func (p *G2Gadget) add(circuit *frontend.CS, p1, p2 *G2Gadget) Fp2Gadget {
+	// λ = (y2-y1)/(x2-x1)
+	var lambda, dx Fp2Gadget
+	lambda.Sub(circuit, &p2.Y, &p1.Y)
+	dx.Sub(circuit, &p2.X, &p1.X)
+	lambda.Div(circuit, &lambda, &dx)
+	p.addLine(circuit, p1, p2, &lambda)
+	return lambda
+}
+
+// double sets p to 2*p1 and returns the slope of the tangent at p1
+func (p *G2Gadget) double(circuit *frontend.CS, p1 *G2Gadget) Fp2Gadget {
+	// λ = 3x²/2y
+	var lambda, yy Fp2Gadget
+	lambda.Square(circuit, &p1.X).MulByFp(circuit, &lambda, 3)
+	yy.Add(circuit, &p1.Y, &p1.Y)
+	lambda.Div(circuit, &lambda, &yy)
+	p.addLine(circuit, p1, p1, &lambda)
+	return lambda
+}
+
+// addLine sets p to p1+p2, λ being the slope of the line through p1 and p2
+func (p *G2Gadget) addLine(circuit *frontend.CS, p1, p2 *G2Gadget, lambda *Fp2Gadget) {
+	// x3 = λ² - x1 - x2, y3 = λ(x1 - x3) - y1
+	var x, y Fp2Gadget
+	x.Square(circuit, lambda).Sub(circuit, &x, &p1.X).Sub(circuit, &x, &p2.X)
+	y.Sub(circuit, &p1.X, &x).Mul(circuit, &y, lambda).Sub(circuit, &y, &p1.Y)
+	p.X, p.Y = x, y
+}
+
+// MustBeOnCurve constrains p to be on the twist y² = x³ + 1/u
+func (p *G2Gadget) MustBeOnCurve(circuit *frontend.CS) {
+	var left, right Fp2Gadget
+	left.Square(circuit, &p.Y)
+	right.Square(circuit, &p.X).Mul(circuit, &right, &p.X)
+
+	// 1/u = u/5
+	var b big.Int
+	b.ModInverse(big.NewInt(nonResidue), fp.ElementModulus())
+	right.A1 = circuit.ADD(right.A1, b)
+
+	left.MustBeEqual(circuit, &right)
+}
+
+// MustBeInSubGroup constrains p to be in the subgroup of order r, as G1Gadget.MustBeInSubGroup does
+func (p *G2Gadget) MustBeInSubGroup(circuit *frontend.CS) {
+	var rMinusOne big.Int
+	rMinusOne.Sub(fr.ElementModulus(), big.NewInt(1))
+
+	acc := *p
+	for i := rMinusOne.BitLen() - 2; i >= 0; i-- {
+		acc.Double(circuit, &acc)
+		if rMinusOne.Bit(i) == 1 {
+			acc.Add(circuit, &acc, p)
+		}
+	}
+
+	var neg G2Gadget
+	acc.MustBeEqual(circuit, neg.Neg(circuit, p))
+}
+
+// MustBeEqual constrains p to be equal to p1
+func (p *G2Gadget) MustBeEqual(circuit *frontend.CS, p1 *G2Gadget) {
+	p.X.MustBeEqual(circuit, &p1.X)
+	p.Y.MustBeEqual(circuit, &p1.Y)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls377
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils/debug"
+	"github.com/consensys/gurvy/utils"
+)
+
+// seed t of BLS12-377, the Miller loop iterates over its NAF decomposition
+const seed uint64 = 9586122913090633729
+
+// loopCounter NAF decomposition of the seed, loopCounter[loopCounterLen-1] being its most significant digit
+var loopCounter [66]int8
+var loopCounterLen int
+
+func init() {
+	loopCounterLen = utils.NafDecomposition(new(big.Int).SetUint64(seed), loopCounter[:])
+}
+
+// MillerLoop computes the product of the Miller loops of the pairs (P[i], Q[i]) and returns it
+//
+// The lines are evaluated in affine coordinates, up to a factor in Fp2 which is cancelled by the final exponentiation:
+// the result is only meaningful once FinalExponentiation is applied on it.
+func MillerLoop(circuit *frontend.CS, P []G1Gadget, Q []G2Gadget) Fp12Gadget {
+	debug.Assert(len(P) == len(Q), "the number of G1 and G2 points must match")
+
+	// T[i] is the running multiple of Q[i]
+	T := make([]G2Gadget, len(Q))
+	copy(T, Q)
+	QNeg := make([]G2Gadget, len(Q))
+	xPNeg := make([]*frontend.Constraint, len(P))
+	for i := range Q {
+		QNeg[i].Neg(circuit, &Q[i])
+		xPNeg[i] = circuit.MUL(P[i].X, -1)
+	}
+
+	res := NewFp12GadgetOne(circuit)
+	for i := loopCounterLen - 2; i >= 0; i-- {
+		if i != loopCounterLen-2 {
+			res.Square(circuit, &res)
+		}
+
+		for j := range P {
+			// tangent at T
+			prev := T[j]
+			lambda := T[j].double(circuit, &prev)
+			res.mulByLine(circuit, P[j].Y, xPNeg[j], &prev, &lambda)
+
+			// line through 2T and ±Q
+			switch loopCounter[i] {
+			case 1:
+				prev = T[j]
+				lambda = T[j].add(circuit, &prev, &Q[j])
+				res.mulByLine(circuit, P[j].Y, xPNeg[j], &prev, &lambda)
+			case -1:
+				prev = T[j]
+				lambda = T[j].add(circuit, &prev, &QNeg[j])
+				res.mulByLine(circuit, P[j].Y, xPNeg[j], &prev, &lambda)
+			}
+		}
+	}
+
+	return res
+}
+
+// mulByLine multiplies e by the line of slope λ through T, evaluated at P = (-xPNeg, yP)
+func (e *Fp12Gadget) mulByLine(circuit *frontend.CS, yP, xPNeg *frontend.Constraint, T *G2Gadget, lambda *Fp2Gadget) *Fp12Gadget {
+	// the line is yP*v + (-λxP*v + (λxT - yT)*v²)*w, as in gurvy (lineEvalRes, divided by xR - xT)
+	var r1, r2 Fp2Gadget
+	r1.MulByFp(circuit, lambda, xPNeg)
+	r2.Mul(circuit, lambda, &T.X).Sub(circuit, &r2, &T.Y)
+
+	// (a + bw)(l0 + l1w) = (a*l0 + b*l1*v) + (a*l1 + b*l0)w
+	var c0, c1, tmp Fp6Gadget
+	c0.mulBy12(circuit, &e.C1, &r1, &r2).MulByNonResidue(circuit, &c0)
+	tmp.mulByFpV(circuit, &e.C0, yP)
+	c0.Add(circuit, &c0, &tmp)
+
+	c1.mulBy12(circuit, &e.C0, &r1, &r2)
+	tmp.mulByFpV(circuit, &e.C1, yP)
+	c1.Add(circuit, &c1, &tmp)
+
+	e.C0, e.C1 = c0, c1
+	return e
+}
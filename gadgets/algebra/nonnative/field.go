@@ -0,0 +1,409 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nonnative provides gadgets for the arithmetic of a field whose modulus differs from the
+// one of the circuit (eg the base and scalar fields of secp256k1, which are larger than the scalar
+// fields of the curves of the backends).
+//
+// An element is an integer written with limbs of LimbSize bits (little endian), each limb being a
+// constraint whose value is bounded at circuit definition time. The limbs of the inputs are range
+// checked with TO_BINARY, and the operations keep track of the bounds such that no intermediate
+// value wraps around the modulus of the circuit. The results are not reduced modulo the modulus of
+// the field but are congruent to it: Reduce returns the canonical representative.
+//
+// A product is reduced by folding the limbs of weight 2**(LimbSize*NbLimbs) or more using
+// 2**(LimbSize*NbLimbs) = c mod Modulus, the carries being computed with TO_BINARY. The modulus
+// must be close to a power of 2 (c < 2**(LimbSize*(NbLimbs-1))), as pseudo-Mersenne primes are.
+package nonnative
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+// LimbSize number of bits of the limbs of the inputs and of the canonical elements
+const LimbSize = 64
+
+// maxBits bound on the number of bits of the limbs and of the intermediate values, such that they
+// never wrap around the modulus of the circuit (at least 253 bits for the curves of the backends)
+const maxBits = 248
+
+var (
+	ErrModulus = errors.New("the modulus must be of the form 2**(64*n) - c, with 0 < c < 2**(64*(n-1))")
+)
+
+// Field modulus of a non-native field and the constants used for the reduction
+type Field struct {
+	Modulus     big.Int
+	NbLimbs     int
+	c           []big.Int // limbs of 2**(LimbSize*NbLimbs) - Modulus
+	maxLimbBits int       // number of bits of the limbs of the results of Mul, such that they can be multiplied
+}
+
+// NewField returns the field of integers modulo modulus
+func NewField(modulus *big.Int) (Field, error) {
+	if modulus.Sign() <= 0 {
+		return Field{}, ErrModulus
+	}
+	res := Field{
+		NbLimbs: (modulus.BitLen() + LimbSize - 1) / LimbSize,
+	}
+	res.Modulus.Set(modulus)
+
+	var c big.Int
+	c.Lsh(big.NewInt(1), uint(LimbSize*res.NbLimbs)).Sub(&c, modulus)
+	if c.Sign() == 0 || c.BitLen() > LimbSize*(res.NbLimbs-1) {
+		return Field{}, ErrModulus
+	}
+	res.c = toLimbs(&c)
+
+	// the columns of a product of two elements whose limbs have maxLimbBits bits can be folded
+	maxCBits := 0
+	for i := range res.c {
+		if res.c[i].BitLen() > maxCBits {
+			maxCBits = res.c[i].BitLen()
+		}
+	}
+	res.maxLimbBits = (maxBits - maxCBits - bits.Len(uint(res.NbLimbs*len(res.c))) - 1) / 2
+
+	return res, nil
+}
+
+// Element element of a non-native field (in r1cs form): the integer sum(Limbs[i] * 2**(LimbSize*i)),
+// which is congruent to the element but not necessarily reduced.
+// As it is an integer, an element of a field can be used in another one (eg reduced modulo another modulus).
+type Element struct {
+	Limbs  []*frontend.Constraint
+	bounds []big.Int // bounds[i] is an upper bound of the value of Limbs[i]
+}
+
+// NewElement allocates (ALLOCATE) the constant v (reduced modulo the modulus) in the circuit
+func (f Field) NewElement(circuit *frontend.CS, v interface{}) Element {
+	var value big.Int
+	bv := backend.FromInterface(v) // may share its words with v
+	value.Mod(&bv, &f.Modulus)
+
+	limbs := toLimbs(&value)
+	res := Element{
+		Limbs:  make([]*frontend.Constraint, f.NbLimbs),
+		bounds: make([]big.Int, f.NbLimbs),
+	}
+	for i := 0; i < f.NbLimbs; i++ {
+		if i < len(limbs) {
+			res.bounds[i].Set(&limbs[i])
+		}
+		res.Limbs[i] = circuit.ALLOCATE(res.bounds[i])
+	}
+	return res
+}
+
+// SecretInput creates the element of the secret inputs name_0, name_1, ... (its limbs), which are range checked
+func (f Field) SecretInput(circuit *frontend.CS, name string) Element {
+	return f.input(circuit, name, circuit.SECRET_INPUT)
+}
+
+// PublicInput creates the element of the public inputs name_0, name_1, ... (its limbs), which are range checked
+func (f Field) PublicInput(circuit *frontend.CS, name string) Element {
+	return f.input(circuit, name, circuit.PUBLIC_INPUT)
+}
+
+func (f Field) input(circuit *frontend.CS, name string, newInput func(string) *frontend.Constraint) Element {
+	res := Element{
+		Limbs:  make([]*frontend.Constraint, f.NbLimbs),
+		bounds: make([]big.Int, f.NbLimbs),
+	}
+	for i := 0; i < f.NbLimbs; i++ {
+		res.Limbs[i] = newInput(name + "_" + strconv.Itoa(i))
+		circuit.MUSTBE_IN_RANGE(res.Limbs[i], LimbSize)
+		res.bounds[i].Set(limbMax)
+	}
+	return res
+}
+
+// Assign assigns the limbs of v (reduced modulo the modulus) to the inputs name_0, name_1, ...
+// created by SecretInput or PublicInput
+func (f Field) Assign(assignment backend.Assignments, visibility backend.Visibility, name string, v interface{}) {
+	var value big.Int
+	bv := backend.FromInterface(v) // may share its words with v
+	value.Mod(&bv, &f.Modulus)
+
+	limbs := toLimbs(&value)
+	for i := 0; i < f.NbLimbs; i++ {
+		var limb big.Int
+		if i < len(limbs) {
+			limb.Set(&limbs[i])
+		}
+		assignment.Assign(visibility, name+"_"+strconv.Itoa(i), limb)
+	}
+}
+
+// Add returns a+b
+func (f Field) Add(circuit *frontend.CS, a, b Element) Element {
+	a, b = f.fit(circuit, a, 1), f.fit(circuit, b, 1)
+	res := Element{
+		Limbs:  make([]*frontend.Constraint, f.NbLimbs),
+		bounds: make([]big.Int, f.NbLimbs),
+	}
+	for i := 0; i < f.NbLimbs; i++ {
+		res.Limbs[i] = circuit.ADD(a.Limbs[i], b.Limbs[i])
+		res.bounds[i].Add(&a.bounds[i], &b.bounds[i])
+	}
+	return res
+}
+
+// Sub returns a-b
+//
+// A multiple of the modulus whose limbs are larger than the ones of b is added,
+// such that the limbs of the result are positive
+func (f Field) Sub(circuit *frontend.CS, a, b Element) Element {
+	a, b = f.fit(circuit, a, 2), f.fit(circuit, b, 1)
+
+	// pad[i] = 2**bitlen(b.bounds[i]) + e[i], e being such that sum(pad[i] * 2**(LimbSize*i)) = 0 mod Modulus
+	pad := make([]big.Int, f.NbLimbs)
+	var padValue big.Int
+	for i := f.NbLimbs - 1; i >= 0; i-- {
+		pad[i].Lsh(big.NewInt(1), uint(b.bounds[i].BitLen()))
+		padValue.Lsh(&padValue, LimbSize).Add(&padValue, &pad[i])
+	}
+	padValue.Neg(&padValue).Mod(&padValue, &f.Modulus)
+	for i, e := range toLimbs(&padValue) {
+		pad[i].Add(&pad[i], &e)
+	}
+
+	one := circuit.ALLOCATE(1)
+	res := Element{
+		Limbs:  make([]*frontend.Constraint, f.NbLimbs),
+		bounds: make([]big.Int, f.NbLimbs),
+	}
+	for i := 0; i < f.NbLimbs; i++ {
+		res.Limbs[i] = linear(circuit,
+			frontend.Term{Constraint: a.Limbs[i], Coeff: *big.NewInt(1)},
+			frontend.Term{Constraint: b.Limbs[i], Coeff: *big.NewInt(-1)},
+			frontend.Term{Constraint: one, Coeff: pad[i]},
+		)
+		res.bounds[i].Add(&a.bounds[i], &pad[i])
+	}
+	return res
+}
+
+// Neg returns -a
+func (f Field) Neg(circuit *frontend.CS, a Element) Element {
+	return f.Sub(circuit, f.NewElement(circuit, 0), a)
+}
+
+// Mul returns a*b
+func (f Field) Mul(circuit *frontend.CS, a, b Element) Element {
+
+	// the limbs are reduced until the columns of the product can be folded
+	for f.productBits(a, b) > f.maxLimbBits*2 {
+		if maxBoundBits(a.bounds) >= maxBoundBits(b.bounds) {
+			a = f.tighten(circuit, a)
+		} else {
+			b = f.tighten(circuit, b)
+		}
+	}
+
+	// schoolbook multiplication, column k being the sum of the a[i]*b[j] with i+j = k
+	terms := make([][]frontend.Term, 2*f.NbLimbs-1)
+	bounds := make([]big.Int, 2*f.NbLimbs-1)
+	var tmp big.Int
+	for i := 0; i < f.NbLimbs; i++ {
+		for j := 0; j < f.NbLimbs; j++ {
+			terms[i+j] = append(terms[i+j], frontend.Term{Constraint: circuit.MUL(a.Limbs[i], b.Limbs[j]), Coeff: *big.NewInt(1)})
+			tmp.Mul(&a.bounds[i], &b.bounds[j])
+			bounds[i+j].Add(&bounds[i+j], &tmp)
+		}
+	}
+	cols := make([]*frontend.Constraint, len(terms))
+	for k := range terms {
+		cols[k] = linear(circuit, terms[k]...)
+	}
+
+	return f.reduce(circuit, cols, bounds)
+}
+
+// MulConstant returns a*k, k being a small constant (of at most LimbSize bits)
+func (f Field) MulConstant(circuit *frontend.CS, a Element, k interface{}) Element {
+	var constant big.Int
+	bk := backend.FromInterface(k) // may share its words with k
+	constant.Mod(&bk, &f.Modulus)
+	if constant.BitLen() > LimbSize {
+		return f.Mul(circuit, a, f.NewElement(circuit, constant))
+	}
+	a = f.fit(circuit, a, constant.BitLen())
+
+	res := Element{
+		Limbs:  make([]*frontend.Constraint, f.NbLimbs),
+		bounds: make([]big.Int, f.NbLimbs),
+	}
+	for i := 0; i < f.NbLimbs; i++ {
+		res.Limbs[i] = circuit.MUL(a.Limbs[i], constant)
+		res.bounds[i].Mul(&a.bounds[i], &constant)
+	}
+	return res
+}
+
+// Exp returns a**e, e being a constant
+func (f Field) Exp(circuit *frontend.CS, a Element, e *big.Int) Element {
+	res := f.NewElement(circuit, 1)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		if i != e.BitLen()-1 {
+			res = f.Mul(circuit, res, res)
+		}
+		if e.Bit(i) == 1 {
+			if i == e.BitLen()-1 {
+				res = a
+			} else {
+				res = f.Mul(circuit, res, a)
+			}
+		}
+	}
+	return res
+}
+
+// Inverse returns 1/a (0 if a is 0) computed as a**(Modulus-2), the modulus must be prime
+func (f Field) Inverse(circuit *frontend.CS, a Element) Element {
+	var e big.Int
+	e.Sub(&f.Modulus, big.NewInt(2))
+	return f.Exp(circuit, a, &e)
+}
+
+// Select returns a if b is true, else c (b must be boolean constrained)
+func (f Field) Select(circuit *frontend.CS, b *frontend.Constraint, a, c Element) Element {
+	res := Element{
+		Limbs:  make([]*frontend.Constraint, f.NbLimbs),
+		bounds: make([]big.Int, f.NbLimbs),
+	}
+	for i := 0; i < f.NbLimbs; i++ {
+		res.Limbs[i] = circuit.SELECT(b, a.Limbs[i], c.Limbs[i])
+		res.bounds[i].Set(&a.bounds[i])
+		if c.bounds[i].Cmp(&a.bounds[i]) > 0 {
+			res.bounds[i].Set(&c.bounds[i])
+		}
+	}
+	return res
+}
+
+// Reduce returns the canonical representative of a, less than the modulus, whose limbs have LimbSize bits
+func (f Field) Reduce(circuit *frontend.CS, a Element) Element {
+	cols, bounds := a.Limbs, a.bounds
+
+	// the limbs are reduced to LimbSize bits, with an additional carry of 1 bit:
+	// the integer is less than 2**(LimbSize*NbLimbs+1)
+	for {
+		if len(cols) > f.NbLimbs && !(len(cols) == f.NbLimbs+1 && bounds[f.NbLimbs].Cmp(big.NewInt(1)) <= 0) {
+			if c, b, ok := f.fold(circuit, cols, bounds); ok {
+				cols, bounds = c, b
+				continue
+			}
+		}
+		if maxBoundBits(bounds) <= LimbSize && len(cols) <= f.NbLimbs+1 {
+			break
+		}
+		cols, bounds = f.split(circuit, cols, bounds, LimbSize)
+	}
+
+	// x = l + t*2**(LimbSize*NbLimbs) is folded twice in l + t*c: if the first fold has a carry, the
+	// second can't have one (l + c - 2**(LimbSize*NbLimbs) < c), so the result is less than 2**(LimbSize*NbLimbs)
+	if len(cols) > f.NbLimbs {
+		cols, bounds, _ = f.fold(circuit, cols, bounds)
+		cols, bounds = f.split(circuit, cols, bounds, LimbSize)
+		if len(cols) > f.NbLimbs {
+			cols, bounds, _ = f.fold(circuit, cols, bounds)
+			cols, _ = f.split(circuit, cols, bounds, LimbSize)
+			for i := f.NbLimbs; i < len(cols); i++ {
+				circuit.MUSTBE_EQ(cols[i], *big.NewInt(0))
+			}
+			cols = cols[:f.NbLimbs]
+		}
+	}
+	cols = f.pad(circuit, cols)
+
+	// x < 2**(LimbSize*NbLimbs) = Modulus + c, so x mod Modulus is x - Modulus = x + c - 2**(LimbSize*NbLimbs)
+	// if x + c has a carry, x otherwise
+	sum := make([]*frontend.Constraint, f.NbLimbs)
+	sumBounds := make([]big.Int, f.NbLimbs)
+	for i := 0; i < f.NbLimbs; i++ {
+		sum[i] = cols[i]
+		sumBounds[i].Set(limbMax)
+		if i < len(f.c) && f.c[i].Sign() != 0 {
+			sum[i] = circuit.ADD(cols[i], f.c[i])
+			sumBounds[i].Add(&sumBounds[i], &f.c[i])
+		}
+	}
+	sum, _ = f.split(circuit, sum, sumBounds, LimbSize)
+	carry := circuit.ALLOCATE(0)
+	if len(sum) > f.NbLimbs {
+		carry = sum[f.NbLimbs]
+	}
+
+	res := Element{
+		Limbs:  make([]*frontend.Constraint, f.NbLimbs),
+		bounds: make([]big.Int, f.NbLimbs),
+	}
+	for i := 0; i < f.NbLimbs; i++ {
+		res.Limbs[i] = circuit.SELECT(carry, sum[i], cols[i])
+		res.bounds[i].Set(limbMax)
+	}
+	return res
+}
+
+// IsZero returns 1 if a = 0 mod Modulus, 0 otherwise
+func (f Field) IsZero(circuit *frontend.CS, a Element) *frontend.Constraint {
+	a = f.Reduce(circuit, a)
+
+	// the limbs of the canonical representative are positive and small, so their sum is 0 iff they all are
+	terms := make([]frontend.Term, f.NbLimbs)
+	for i := 0; i < f.NbLimbs; i++ {
+		terms[i] = frontend.Term{Constraint: a.Limbs[i], Coeff: *big.NewInt(1)}
+	}
+	return circuit.IS_ZERO(linear(circuit, terms...))
+}
+
+// MustBeEqual constrains a and b to be equal modulo the modulus
+func (f Field) MustBeEqual(circuit *frontend.CS, a, b Element) {
+	d := f.Reduce(circuit, f.Sub(circuit, a, b))
+	for i := 0; i < f.NbLimbs; i++ {
+		circuit.MUSTBE_EQ(d.Limbs[i], *big.NewInt(0))
+	}
+}
+
+// MustBeReduced constrains a to be the canonical representative of its class (less than the modulus)
+func (f Field) MustBeReduced(circuit *frontend.CS, a Element) {
+	r := f.Reduce(circuit, a)
+	for i := 0; i < f.NbLimbs; i++ {
+		circuit.MUSTBE_EQ(a.Limbs[i], r.Limbs[i])
+	}
+}
+
+// ToBinary returns the bits of the canonical representative of a, in little endian
+func (f Field) ToBinary(circuit *frontend.CS, a Element) []*frontend.Constraint {
+	a = f.Reduce(circuit, a)
+	res := make([]*frontend.Constraint, 0, f.Modulus.BitLen())
+	for i := 0; i < f.NbLimbs; i++ {
+		nbBits := LimbSize
+		if i == f.NbLimbs-1 {
+			nbBits = f.Modulus.BitLen() - LimbSize*i
+		}
+		res = append(res, circuit.TO_BINARY(a.Limbs[i], nbBits)...)
+	}
+	return res
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nonnative
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+// moduli of the base and scalar fields of secp256k1
+var moduli = []string{
+	"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F",
+	"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141",
+}
+
+// tag tags the limbs of a name_0, name_1, ...
+func tag(a Element, name string) {
+	for i := range a.Limbs {
+		a.Limbs[i].Tag(name + "_" + strconv.Itoa(i))
+	}
+}
+
+// value returns the integer of the limbs tagged name_0, name_1, ...
+func value(values map[string]big.Int, nbLimbs int, name string) big.Int {
+	var res big.Int
+	for i := nbLimbs - 1; i >= 0; i-- {
+		limb := values[name+"_"+strconv.Itoa(i)]
+		res.Lsh(&res, LimbSize).Add(&res, &limb)
+	}
+	return res
+}
+
+func TestNewField(t *testing.T) {
+	for _, m := range moduli {
+		var modulus big.Int
+		modulus.SetString(m, 16)
+		if _, err := NewField(&modulus); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// BN256 fr is not close enough to a power of 2
+	if _, err := NewField(fr_bn256.ElementModulus()); err != ErrModulus {
+		t.Fatal("expected", ErrModulus, "got", err)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+
+	for _, m := range moduli {
+		var modulus big.Int
+		modulus.SetString(m, 16)
+		f, err := NewField(&modulus)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var pMinusOne big.Int
+		pMinusOne.Sub(&modulus, big.NewInt(1))
+
+		for i := 0; i < 3; i++ {
+
+			// the first iteration uses the largest elements
+			a, b := new(big.Int).Set(&pMinusOne), new(big.Int).Set(&pMinusOne)
+			if i > 0 {
+				a, _ = rand.Int(rand.Reader, &modulus)
+				b, _ = rand.Int(rand.Reader, &modulus)
+			}
+
+			good := backend.NewAssignment()
+			f.Assign(good, backend.Secret, "a", a)
+			f.Assign(good, backend.Public, "b", b)
+
+			circuit := frontend.NewTestEngine(fr_bn256.ElementModulus(), good)
+			ea := f.SecretInput(&circuit, "a")
+			eb := f.PublicInput(&circuit, "b")
+
+			// (a*b) * (a*b - b) * 42 + a, the results of the operations being chained without reduction
+			ab := f.Mul(&circuit, ea, eb)
+			res := f.Mul(&circuit, ab, f.Sub(&circuit, ab, eb))
+			res = f.MulConstant(&circuit, f.Add(&circuit, res, res), 21)
+			res = f.Add(&circuit, res, ea)
+			tag(f.Reduce(&circuit, res), "res")
+			tag(f.Reduce(&circuit, f.Neg(&circuit, ea)), "neg")
+			tag(f.Reduce(&circuit, f.Inverse(&circuit, ea)), "inv")
+			f.IsZero(&circuit, f.Sub(&circuit, ab, ab)).Tag("zero")
+			f.IsZero(&circuit, ab).Tag("nonzero")
+			f.MustBeEqual(&circuit, f.Mul(&circuit, ea, eb), f.Mul(&circuit, eb, ea))
+			f.MustBeReduced(&circuit, ea)
+
+			values, err := circuit.Inspect(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var expected, tmp big.Int
+			expected.Mul(a, b).Mod(&expected, &modulus)
+			tmp.Sub(&expected, b)
+			expected.Mul(&expected, &tmp).Mul(&expected, big.NewInt(42)).Add(&expected, a).Mod(&expected, &modulus)
+			if got := value(values, f.NbLimbs, "res"); got.Cmp(&expected) != 0 {
+				t.Fatal("res: expected", expected.String(), "got", got.String())
+			}
+			expected.Neg(a).Mod(&expected, &modulus)
+			if got := value(values, f.NbLimbs, "neg"); got.Cmp(&expected) != 0 {
+				t.Fatal("neg: expected", expected.String(), "got", got.String())
+			}
+			expected.ModInverse(a, &modulus)
+			if got := value(values, f.NbLimbs, "inv"); got.Cmp(&expected) != 0 {
+				t.Fatal("inv: expected", expected.String(), "got", got.String())
+			}
+			if v := values["zero"]; v.Cmp(big.NewInt(1)) != 0 {
+				t.Fatal("zero: expected 1 got", v.String())
+			}
+			if v := values["nonzero"]; v.Sign() != 0 {
+				t.Fatal("nonzero: expected 0 got", v.String())
+			}
+		}
+	}
+}
+
+func TestMustBeReduced(t *testing.T) {
+
+	var modulus big.Int
+	modulus.SetString(moduli[0], 16)
+	f, err := NewField(&modulus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a = Modulus + 1 has limbs of LimbSize bits, but is not reduced
+	var a big.Int
+	a.Add(&modulus, big.NewInt(1))
+	bad := backend.NewAssignment()
+	for i, limb := range toLimbs(&a) {
+		bad.Assign(backend.Secret, "a_"+strconv.Itoa(i), limb)
+	}
+
+	circuit := frontend.NewTestEngine(fr_bn256.ElementModulus(), bad)
+	f.MustBeReduced(&circuit, f.SecretInput(&circuit, "a"))
+	if _, err := circuit.Inspect(false); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("an element larger than the modulus should not be reduced")
+	}
+}
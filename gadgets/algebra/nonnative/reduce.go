@@ -0,0 +1,205 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nonnative
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// limbMax largest value of a limb of LimbSize bits
+var limbMax = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), LimbSize), big.NewInt(1))
+
+// reduce returns the element of the columns (bounded by bounds), the column i having the weight
+// 2**(LimbSize*i), such that it can be multiplied: it has NbLimbs limbs of at most maxLimbBits bits
+func (f Field) reduce(circuit *frontend.CS, cols []*frontend.Constraint, bounds []big.Int) Element {
+	for {
+		if len(cols) > f.NbLimbs {
+			if c, b, ok := f.fold(circuit, cols, bounds); ok {
+				cols, bounds = c, b
+				continue
+			}
+		}
+		if len(cols) <= f.NbLimbs && maxBoundBits(bounds) <= f.maxLimbBits {
+			break
+		}
+		cols, bounds = f.split(circuit, cols, bounds, f.maxLimbBits)
+	}
+
+	res := Element{
+		Limbs:  f.pad(circuit, cols),
+		bounds: make([]big.Int, f.NbLimbs),
+	}
+	for i := range bounds {
+		res.bounds[i].Set(&bounds[i])
+	}
+	return res
+}
+
+// tighten returns a with limbs of at most maxLimbBits bits
+func (f Field) tighten(circuit *frontend.CS, a Element) Element {
+	return f.reduce(circuit, a.Limbs, a.bounds)
+}
+
+// fit returns a, tightened if needed such that its limbs can grow by extraBits bits without exceeding maxBits
+func (f Field) fit(circuit *frontend.CS, a Element, extraBits int) Element {
+	if maxBoundBits(a.bounds)+extraBits <= maxBits {
+		return a
+	}
+	return f.tighten(circuit, a)
+}
+
+// productBits returns the number of bits of the product of the largest limbs of a and b
+func (f Field) productBits(a, b Element) int {
+	return maxBoundBits(a.bounds) + maxBoundBits(b.bounds)
+}
+
+// fold replaces the columns of weight 2**(LimbSize*NbLimbs) or more using 2**(LimbSize*NbLimbs) = c mod Modulus:
+// the column k >= NbLimbs is multiplied by the limbs of c and added to the columns k-NbLimbs, k-NbLimbs+1, ...
+// ok is false if a bound would exceed maxBits
+func (f Field) fold(circuit *frontend.CS, cols []*frontend.Constraint, bounds []big.Int) (res []*frontend.Constraint, resBounds []big.Int, ok bool) {
+	size := f.NbLimbs
+	for k := f.NbLimbs; k < len(cols); k++ {
+		if k-f.NbLimbs+len(f.c) > size {
+			size = k - f.NbLimbs + len(f.c)
+		}
+	}
+
+	terms := make([][]frontend.Term, size)
+	resBounds = make([]big.Int, size)
+	var tmp big.Int
+	for k := range cols {
+		if k < f.NbLimbs {
+			terms[k] = append(terms[k], frontend.Term{Constraint: cols[k], Coeff: *big.NewInt(1)})
+			resBounds[k].Add(&resBounds[k], &bounds[k])
+			continue
+		}
+		for j := range f.c {
+			if f.c[j].Sign() == 0 {
+				continue
+			}
+			terms[k-f.NbLimbs+j] = append(terms[k-f.NbLimbs+j], frontend.Term{Constraint: cols[k], Coeff: f.c[j]})
+			tmp.Mul(&bounds[k], &f.c[j])
+			resBounds[k-f.NbLimbs+j].Add(&resBounds[k-f.NbLimbs+j], &tmp)
+		}
+	}
+	if maxBoundBits(resBounds) > maxBits {
+		return nil, nil, false
+	}
+
+	res = make([]*frontend.Constraint, size)
+	for i := range terms {
+		switch {
+		case len(terms[i]) == 0:
+			res[i] = circuit.ALLOCATE(0)
+		case len(terms[i]) == 1 && terms[i][0].Coeff.Cmp(big.NewInt(1)) == 0:
+			res[i] = terms[i][0].Constraint
+		default:
+			res[i] = linear(circuit, terms[i]...)
+		}
+	}
+	return res, resBounds, true
+}
+
+// split propagates the carries of the columns: a column bounded by more than 2**limbBits (once the carry of
+// the previous column is added) is split in a limb of LimbSize bits and a carry, computed with TO_BINARY.
+// The carry of the last column is a new column.
+func (f Field) split(circuit *frontend.CS, cols []*frontend.Constraint, bounds []big.Int, limbBits int) ([]*frontend.Constraint, []big.Int) {
+	res := make([]*frontend.Constraint, 0, len(cols)+1)
+	resBounds := make([]big.Int, 0, len(cols)+1)
+
+	var carry *frontend.Constraint
+	var carryBound big.Int
+	for k := 0; k < len(cols) || carry != nil; k++ {
+		var col *frontend.Constraint
+		var bound big.Int
+		if k < len(cols) {
+			col = cols[k]
+			bound.Set(&bounds[k])
+		}
+		if carry != nil {
+			if col == nil {
+				col = carry
+			} else {
+				col = circuit.ADD(col, carry)
+			}
+			bound.Add(&bound, &carryBound)
+			carry = nil
+		}
+
+		if bound.BitLen() <= limbBits || bound.BitLen() <= LimbSize {
+			res = append(res, col)
+			resBounds = append(resBounds, bound)
+			continue
+		}
+
+		b := circuit.TO_BINARY(col, bound.BitLen())
+		bits := make([]interface{}, len(b))
+		for i := range b {
+			bits[i] = b[i]
+		}
+		res = append(res, circuit.FROM_BINARY(bits[:LimbSize]...))
+		resBounds = append(resBounds, *new(big.Int).Set(limbMax))
+		if len(b) == LimbSize+1 {
+			carry = b[LimbSize]
+		} else {
+			carry = circuit.FROM_BINARY(bits[LimbSize:]...)
+		}
+		carryBound.Rsh(&bound, LimbSize)
+	}
+
+	return res, resBounds
+}
+
+// pad returns cols with zeros appended such that it has NbLimbs columns
+func (f Field) pad(circuit *frontend.CS, cols []*frontend.Constraint) []*frontend.Constraint {
+	for len(cols) < f.NbLimbs {
+		cols = append(cols, circuit.ALLOCATE(0))
+	}
+	return cols
+}
+
+// maxBoundBits returns the number of bits of the largest bound
+func maxBoundBits(bounds []big.Int) int {
+	res := 0
+	for i := range bounds {
+		if bounds[i].BitLen() > res {
+			res = bounds[i].BitLen()
+		}
+	}
+	return res
+}
+
+// toLimbs returns the limbs of LimbSize bits of v (positive), in little endian
+func toLimbs(v *big.Int) []big.Int {
+	var res []big.Int
+	var tmp big.Int
+	tmp.Set(v)
+	for tmp.Sign() != 0 {
+		var limb big.Int
+		limb.And(&tmp, limbMax)
+		res = append(res, limb)
+		tmp.Rsh(&tmp, LimbSize)
+	}
+	return res
+}
+
+// linear returns the sum of the terms, as a single constraint
+func linear(circuit *frontend.CS, terms ...frontend.Term) *frontend.Constraint {
+	return circuit.MUL(frontend.LinearCombination(terms), frontend.LinearCombination{frontend.Term{Constraint: circuit.ALLOCATE(1), Coeff: *big.NewInt(1)}})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secp256k1
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/gadgets/algebra/nonnative"
+)
+
+// CurveGadget stores the parameters of secp256k1: y² = x³ + 7 over the field of modulus P,
+// whose base point (BaseX, BaseY) generates a group of prime order N
+type CurveGadget struct {
+	Fp, Fr       nonnative.Field
+	B            big.Int
+	BaseX, BaseY big.Int
+}
+
+// NewCurveGadget returns the parameters of secp256k1
+func NewCurveGadget() (CurveGadget, error) {
+	var res CurveGadget
+	var p, n big.Int
+	p.SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+	n.SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+	res.BaseX.SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+	res.BaseY.SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+	res.B.SetUint64(7)
+
+	var err error
+	if res.Fp, err = nonnative.NewField(&p); err != nil {
+		return CurveGadget{}, err
+	}
+	if res.Fr, err = nonnative.NewField(&n); err != nil {
+		return CurveGadget{}, err
+	}
+	return res, nil
+}
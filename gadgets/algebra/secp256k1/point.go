@@ -0,0 +1,245 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secp256k1
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets/algebra/nonnative"
+)
+
+// PointGadget point of secp256k1 (in r1cs form), in projective coordinates: (X:Y:Z) is the affine
+// point (X/Z, Y/Z), and (0:1:0) is the point at infinity
+//
+// The formulas are complete (cf https://eprint.iacr.org/2015/1060, algorithms 7 and 9): they
+// support the point at infinity and the addition of points which are equal or opposite
+type PointGadget struct {
+	X, Y, Z nonnative.Element
+}
+
+// NewPointGadget creates the point of affine coordinates (x, y)
+// if x and y are not of type nonnative.Element they are allocated (ALLOCATE) in the circuit
+func NewPointGadget(circuit *frontend.CS, x, y interface{}, curve CurveGadget) PointGadget {
+	return PointGadget{
+		X: element(circuit, x, curve),
+		Y: element(circuit, y, curve),
+		Z: curve.Fp.NewElement(circuit, 1),
+	}
+}
+
+// element returns v if it is an element, v allocated in the circuit otherwise
+func element(circuit *frontend.CS, v interface{}, curve CurveGadget) nonnative.Element {
+	if e, ok := v.(nonnative.Element); ok {
+		return e
+	}
+	return curve.Fp.NewElement(circuit, v)
+}
+
+// MustBeOnCurve checks if a point is on secp256k1: Y²Z = X³ + bZ³
+func (p *PointGadget) MustBeOnCurve(circuit *frontend.CS, curve CurveGadget) {
+	fp := curve.Fp
+	zzz := fp.Mul(circuit, fp.Mul(circuit, p.Z, p.Z), p.Z)
+	lhs := fp.Mul(circuit, fp.Mul(circuit, p.Y, p.Y), p.Z)
+	rhs := fp.Add(circuit,
+		fp.Mul(circuit, fp.Mul(circuit, p.X, p.X), p.X),
+		fp.MulConstant(circuit, zzz, curve.B),
+	)
+	fp.MustBeEqual(circuit, lhs, rhs)
+}
+
+// Neg sets p to -p1 and returns p
+func (p *PointGadget) Neg(circuit *frontend.CS, p1 *PointGadget, curve CurveGadget) *PointGadget {
+	p.X, p.Y, p.Z = p1.X, curve.Fp.Neg(circuit, p1.Y), p1.Z
+	return p
+}
+
+// Add sets p to p1+p2 and returns p
+// 12 multiplications and 2 multiplications by the constant 3b (algorithm 7)
+func (p *PointGadget) Add(circuit *frontend.CS, p1, p2 *PointGadget, curve CurveGadget) *PointGadget {
+	fp := curve.Fp
+	var b3 big.Int
+	b3.Mul(&curve.B, big.NewInt(3))
+
+	t0 := fp.Mul(circuit, p1.X, p2.X)
+	t1 := fp.Mul(circuit, p1.Y, p2.Y)
+	t2 := fp.Mul(circuit, p1.Z, p2.Z)
+
+	// t3 = X1*Y2 + Y1*X2, t4 = Y1*Z2 + Z1*Y2, t5 = X1*Z2 + Z1*X2
+	t3 := fp.Sub(circuit, fp.Mul(circuit, fp.Add(circuit, p1.X, p1.Y), fp.Add(circuit, p2.X, p2.Y)), fp.Add(circuit, t0, t1))
+	t4 := fp.Sub(circuit, fp.Mul(circuit, fp.Add(circuit, p1.Y, p1.Z), fp.Add(circuit, p2.Y, p2.Z)), fp.Add(circuit, t1, t2))
+	t5 := fp.Sub(circuit, fp.Mul(circuit, fp.Add(circuit, p1.X, p1.Z), fp.Add(circuit, p2.X, p2.Z)), fp.Add(circuit, t0, t2))
+
+	t0 = fp.MulConstant(circuit, t0, 3)
+	t2 = fp.MulConstant(circuit, t2, b3)
+	t5 = fp.MulConstant(circuit, t5, b3)
+	z := fp.Add(circuit, t1, t2)
+	t1 = fp.Sub(circuit, t1, t2)
+
+	// X3 = t3*t1 - t4*t5, Y3 = t1*z + t0*t5, Z3 = z*t4 + t0*t3
+	x := fp.Sub(circuit, fp.Mul(circuit, t3, t1), fp.Mul(circuit, t4, t5))
+	y := fp.Add(circuit, fp.Mul(circuit, t1, z), fp.Mul(circuit, t0, t5))
+	z = fp.Add(circuit, fp.Mul(circuit, z, t4), fp.Mul(circuit, t0, t3))
+
+	p.X, p.Y, p.Z = x, y, z
+	return p
+}
+
+// Double sets p to 2*p1 and returns p
+// 8 multiplications and 1 multiplication by the constant 3b (algorithm 9)
+func (p *PointGadget) Double(circuit *frontend.CS, p1 *PointGadget, curve CurveGadget) *PointGadget {
+	fp := curve.Fp
+	var b3 big.Int
+	b3.Mul(&curve.B, big.NewInt(3))
+
+	t0 := fp.Mul(circuit, p1.Y, p1.Y)
+	t1 := fp.Mul(circuit, p1.Y, p1.Z)
+	t2 := fp.MulConstant(circuit, fp.Mul(circuit, p1.Z, p1.Z), b3)
+	t3 := fp.MulConstant(circuit, t0, 8)
+
+	// X3 = 2XY(Y² - 9bZ²), Y3 = (Y² - 9bZ²)(Y² + 3bZ²) + 24bY²Z², Z3 = 8Y³Z
+	y := fp.Mul(circuit, t2, t3)
+	z := fp.Mul(circuit, t1, t3)
+	t1 = fp.Add(circuit, t0, t2)
+	t0 = fp.Sub(circuit, t0, fp.MulConstant(circuit, t2, 3))
+	y = fp.Add(circuit, y, fp.Mul(circuit, t0, t1))
+	x := fp.MulConstant(circuit, fp.Mul(circuit, t0, fp.Mul(circuit, p1.X, p1.Y)), 2)
+
+	p.X, p.Y, p.Z = x, y, z
+	return p
+}
+
+// Select sets p to p1 if b is true, p2 otherwise (b must be boolean constrained), and returns p
+func (p *PointGadget) Select(circuit *frontend.CS, b *frontend.Constraint, p1, p2 *PointGadget, curve CurveGadget) *PointGadget {
+	fp := curve.Fp
+	p.X, p.Y, p.Z = fp.Select(circuit, b, p1.X, p2.X), fp.Select(circuit, b, p1.Y, p2.Y), fp.Select(circuit, b, p1.Z, p2.Z)
+	return p
+}
+
+// ToAffine returns the affine coordinates (X/Z, Y/Z) of p, (0, 0) if p is the point at infinity
+func (p *PointGadget) ToAffine(circuit *frontend.CS, curve CurveGadget) (x, y nonnative.Element) {
+	zInv := curve.Fp.Inverse(circuit, p.Z)
+	return curve.Fp.Mul(circuit, p.X, zInv), curve.Fp.Mul(circuit, p.Y, zInv)
+}
+
+// DoubleScalarMulFixedBase computes s1*(x, y) + s2*p2 on secp256k1
+// x, y: affine coordinates of the fixed base point
+// p2: non fixed point (as snark point)
+// s1, s2: scalars as elements of curve.Fr
+// The scalars are processed jointly by windows of 2 bits (Straus): the doublings are shared, and
+// for each window i*(x, y) + j*p2 is selected in a table of the 16 points with i, j = 0..3
+func (p *PointGadget) DoubleScalarMulFixedBase(circuit *frontend.CS, x, y interface{}, s1 nonnative.Element, p2 *PointGadget, s2 nonnative.Element, curve CurveGadget) *PointGadget {
+
+	// multiples of the base, computed in plain go
+	var base [4]fixedPoint
+	base[1].X = backend.FromInterface(x)
+	base[1].Y = backend.FromInterface(y)
+	base[2].double(&base[1], curve)
+	base[3].add(&base[2], &base[1], curve)
+
+	// table[i+4*j] = i*(x, y) + j*p2
+	var table [16]PointGadget
+	table[0] = PointGadget{
+		X: curve.Fp.NewElement(circuit, 0),
+		Y: curve.Fp.NewElement(circuit, 1),
+		Z: curve.Fp.NewElement(circuit, 0),
+	}
+	for i := 1; i < 4; i++ {
+		table[i] = NewPointGadget(circuit, base[i].X, base[i].Y, curve)
+	}
+	table[4] = *p2
+	table[8].Double(circuit, p2, curve)
+	table[12].Add(circuit, &table[8], p2, curve)
+	for j := 4; j < 16; j += 4 {
+		for i := 1; i < 4; i++ {
+			table[i+j].Add(circuit, &table[j], &table[i], curve)
+		}
+	}
+
+	b1 := curve.Fr.ToBinary(circuit, s1)
+	b2 := curve.Fr.ToBinary(circuit, s2)
+
+	var res PointGadget
+	for w := (len(b1)+1)/2 - 1; w >= 0; w-- {
+
+		// the 16 points are selected by the bits of s1 (index mod 4), then by the ones of s2
+		var selected [16]PointGadget
+		copy(selected[:], table[:])
+		for level, b := range []*frontend.Constraint{bit(circuit, b1, 2*w), bit(circuit, b1, 2*w+1), bit(circuit, b2, 2*w), bit(circuit, b2, 2*w+1)} {
+			for k := 0; k < 16>>uint(level+1); k++ {
+				selected[k].Select(circuit, b, &selected[2*k+1], &selected[2*k], curve)
+			}
+		}
+
+		if w == (len(b1)+1)/2-1 {
+			res = selected[0]
+			continue
+		}
+		res.Double(circuit, &res, curve).
+			Double(circuit, &res, curve).
+			Add(circuit, &res, &selected[0], curve)
+	}
+
+	*p = res
+	return p
+}
+
+// bit returns bits[i], or 0 if i is out of range
+func bit(circuit *frontend.CS, bits []*frontend.Constraint, i int) *frontend.Constraint {
+	if i < len(bits) {
+		return bits[i]
+	}
+	return circuit.ALLOCATE(0)
+}
+
+// fixedPoint point of secp256k1 in affine coordinates in plain go, used to compute the
+// constants (eg lookup tables) of the gadgets
+type fixedPoint struct {
+	X, Y big.Int
+}
+
+// add sets p to p1+p2 and returns it, p1 and p2 being distinct and not opposite
+// λ = (y2-y1)/(x2-x1)
+func (p *fixedPoint) add(p1, p2 *fixedPoint, curve CurveGadget) *fixedPoint {
+	var num, den, lambda big.Int
+	num.Sub(&p2.Y, &p1.Y)
+	den.Sub(&p2.X, &p1.X).Mod(&den, &curve.Fp.Modulus).ModInverse(&den, &curve.Fp.Modulus)
+	lambda.Mul(&num, &den).Mod(&lambda, &curve.Fp.Modulus)
+	return p.addLine(p1, p2, &lambda, curve)
+}
+
+// double sets p to 2*p1 and returns it
+// λ = 3x²/2y
+func (p *fixedPoint) double(p1 *fixedPoint, curve CurveGadget) *fixedPoint {
+	var num, den, lambda big.Int
+	num.Mul(&p1.X, &p1.X).Mul(&num, big.NewInt(3))
+	den.Lsh(&p1.Y, 1).ModInverse(&den, &curve.Fp.Modulus)
+	lambda.Mul(&num, &den).Mod(&lambda, &curve.Fp.Modulus)
+	return p.addLine(p1, p1, &lambda, curve)
+}
+
+// addLine sets p to p1+p2, λ being the slope of the line through p1 and p2
+// x3 = λ² - x1 - x2, y3 = λ(x1 - x3) - y1
+func (p *fixedPoint) addLine(p1, p2 *fixedPoint, lambda *big.Int, curve CurveGadget) *fixedPoint {
+	var x, y big.Int
+	x.Mul(lambda, lambda).Sub(&x, &p1.X).Sub(&x, &p2.X).Mod(&x, &curve.Fp.Modulus)
+	y.Sub(&p1.X, &x).Mul(&y, lambda).Sub(&y, &p1.Y).Mod(&y, &curve.Fp.Modulus)
+	p.X.Set(&x)
+	p.Y.Set(&y)
+	return p
+}
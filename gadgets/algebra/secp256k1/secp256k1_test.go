@@ -0,0 +1,181 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secp256k1
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets/algebra/nonnative"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+// scalarMul returns s*p in plain go, nil being the point at infinity
+func scalarMul(p *fixedPoint, s *big.Int, curve CurveGadget) *fixedPoint {
+	var res *fixedPoint
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		if res != nil {
+			res = new(fixedPoint).double(res, curve)
+		}
+		if s.Bit(i) == 1 {
+			if res == nil {
+				res = &fixedPoint{}
+				res.X.Set(&p.X)
+				res.Y.Set(&p.Y)
+			} else {
+				res = new(fixedPoint).add(res, p, curve)
+			}
+		}
+	}
+	return res
+}
+
+// tag tags the limbs of the canonical representative of a as name_0, name_1, ...
+func tag(circuit *frontend.CS, a nonnative.Element, name string, curve CurveGadget) {
+	a = curve.Fp.Reduce(circuit, a)
+	for i := range a.Limbs {
+		a.Limbs[i].Tag(name + "_" + strconv.Itoa(i))
+	}
+}
+
+// tagAffine tags the affine coordinates of p as name_x_i, name_y_i
+func tagAffine(circuit *frontend.CS, p *PointGadget, name string, curve CurveGadget) {
+	x, y := p.ToAffine(circuit, curve)
+	tag(circuit, x, name+"_x", curve)
+	tag(circuit, y, name+"_y", curve)
+}
+
+// checkAffine checks that the values tagged by tagAffine are the coordinates of expected ((0, 0) if nil)
+func checkAffine(t *testing.T, values map[string]big.Int, name string, expected *fixedPoint) {
+	if expected == nil {
+		expected = &fixedPoint{}
+	}
+	for _, c := range []struct {
+		name  string
+		value *big.Int
+	}{{name + "_x", &expected.X}, {name + "_y", &expected.Y}} {
+		var got big.Int
+		for i := 3; i >= 0; i-- {
+			limb := values[c.name+"_"+strconv.Itoa(i)]
+			got.Lsh(&got, nonnative.LimbSize).Add(&got, &limb)
+		}
+		if got.Cmp(c.value) != 0 {
+			t.Fatal(c.name, "expected", c.value.String(), "got", got.String())
+		}
+	}
+}
+
+func TestAddDouble(t *testing.T) {
+
+	curve, err := NewCurveGadget()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var base fixedPoint
+	base.X.Set(&curve.BaseX)
+	base.Y.Set(&curve.BaseY)
+	p1 := scalarMul(&base, big.NewInt(5), curve)
+	p2 := scalarMul(&base, big.NewInt(7), curve)
+
+	good := backend.NewAssignment()
+	curve.Fp.Assign(good, backend.Secret, "x1", p1.X)
+	curve.Fp.Assign(good, backend.Secret, "y1", p1.Y)
+	curve.Fp.Assign(good, backend.Public, "x2", p2.X)
+	curve.Fp.Assign(good, backend.Public, "y2", p2.Y)
+
+	circuit := frontend.NewTestEngine(fr_bn256.ElementModulus(), good)
+	g1 := NewPointGadget(&circuit, curve.Fp.SecretInput(&circuit, "x1"), curve.Fp.SecretInput(&circuit, "y1"), curve)
+	g2 := NewPointGadget(&circuit, curve.Fp.PublicInput(&circuit, "x2"), curve.Fp.PublicInput(&circuit, "y2"), curve)
+	g1.MustBeOnCurve(&circuit, curve)
+	g2.MustBeOnCurve(&circuit, curve)
+
+	var sum, double, same, neg, zero, zeroPlusG1, zeroDouble PointGadget
+	sum.Add(&circuit, &g1, &g2, curve)
+	sum.MustBeOnCurve(&circuit, curve)
+	tagAffine(&circuit, &sum, "sum", curve)
+	double.Double(&circuit, &g1, curve)
+	tagAffine(&circuit, &double, "double", curve)
+	same.Add(&circuit, &g1, &g1, curve)
+	tagAffine(&circuit, &same, "same", curve)
+
+	// g1 - g1 is the point at infinity, which is neutral
+	neg.Neg(&circuit, &g1, curve)
+	zero.Add(&circuit, &g1, &neg, curve)
+	zero.MustBeOnCurve(&circuit, curve)
+	tagAffine(&circuit, &zero, "zero", curve)
+	zeroPlusG1.Add(&circuit, &zero, &g1, curve)
+	tagAffine(&circuit, &zeroPlusG1, "zeroPlusG1", curve)
+	zeroDouble.Double(&circuit, &zero, curve)
+	tagAffine(&circuit, &zeroDouble, "zeroDouble", curve)
+
+	values, err := circuit.Inspect(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAffine(t, values, "sum", scalarMul(&base, big.NewInt(12), curve))
+	checkAffine(t, values, "double", scalarMul(&base, big.NewInt(10), curve))
+	checkAffine(t, values, "same", scalarMul(&base, big.NewInt(10), curve))
+	checkAffine(t, values, "zero", nil)
+	checkAffine(t, values, "zeroPlusG1", p1)
+	checkAffine(t, values, "zeroDouble", nil)
+}
+
+func TestDoubleScalarMulFixedBase(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the scalar multiplication of secp256k1 points in short mode")
+	}
+
+	curve, err := NewCurveGadget()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var base fixedPoint
+	base.X.Set(&curve.BaseX)
+	base.Y.Set(&curve.BaseY)
+	p := scalarMul(&base, big.NewInt(42), curve)
+
+	s1, _ := rand.Int(rand.Reader, &curve.Fr.Modulus)
+	s2, _ := rand.Int(rand.Reader, &curve.Fr.Modulus)
+
+	good := backend.NewAssignment()
+	curve.Fp.Assign(good, backend.Secret, "x", p.X)
+	curve.Fp.Assign(good, backend.Secret, "y", p.Y)
+	curve.Fr.Assign(good, backend.Secret, "s1", s1)
+	curve.Fr.Assign(good, backend.Secret, "s2", s2)
+
+	circuit := frontend.NewTestEngine(fr_bn256.ElementModulus(), good)
+	g := NewPointGadget(&circuit, curve.Fp.SecretInput(&circuit, "x"), curve.Fp.SecretInput(&circuit, "y"), curve)
+	var res PointGadget
+	res.DoubleScalarMulFixedBase(&circuit, curve.BaseX, curve.BaseY, curve.Fr.SecretInput(&circuit, "s1"), &g, curve.Fr.SecretInput(&circuit, "s2"), curve)
+	tagAffine(&circuit, &res, "res", curve)
+
+	values, err := circuit.Inspect(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// s1*base + s2*42*base
+	var s big.Int
+	s.Mul(s2, big.NewInt(42)).Add(&s, s1).Mod(&s, &curve.Fr.Modulus)
+	checkAffine(t, values, "res", scalarMul(&base, &s, curve))
+}
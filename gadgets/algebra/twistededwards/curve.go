@@ -20,6 +20,7 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark/backend"
+	edbls377 "github.com/consensys/gnark/crypto/bls377/twistededwards"
 	"github.com/consensys/gnark/gadgets"
 	"github.com/consensys/gurvy"
 	edbls381 "github.com/consensys/gurvy/bls381/twistededwards"
@@ -38,6 +39,7 @@ func init() {
 	newTwistedEdwards = make(map[gurvy.ID]func() EdCurveGadget)
 	newTwistedEdwards[gurvy.BLS381] = newEdBLS381
 	newTwistedEdwards[gurvy.BN256] = newEdBN256
+	newTwistedEdwards[gurvy.BLS377] = newEdBLS377
 }
 
 // NewEdCurveGadget returns an Edwards curve parameters
@@ -94,3 +96,25 @@ func newEdBLS381() EdCurveGadget {
 	return res
 
 }
+
+func newEdBLS377() EdCurveGadget {
+
+	edcurve := edbls377.GetEdwardsCurve()
+	var cofactorReg big.Int
+	edcurve.Cofactor.ToBigInt(&cofactorReg)
+
+	res := EdCurveGadget{
+		A:        backend.FromInterface(edcurve.A),
+		D:        backend.FromInterface(edcurve.D),
+		Cofactor: backend.FromInterface(cofactorReg),
+		Order:    backend.FromInterface(edcurve.Order),
+		BaseX:    backend.FromInterface(edcurve.Base.X),
+		BaseY:    backend.FromInterface(edcurve.Base.Y),
+		ID:       gurvy.BLS377,
+	}
+	// TODO use the modulus soon-to-be exported by goff
+	res.Modulus.SetString("8444461749428370424248824938781546531375899335154063827935233455917409239041", 10)
+
+	return res
+
+}
@@ -208,21 +208,96 @@ func (p *PointGadget) ScalarMulNonFixedBase(circuit *frontend.CS, p1 *PointGadge
 // x, y: coordinates of the base point
 // curve: parameters of the Edwards curve
 // scal: scalar as a SNARK constraint
-// Standard left to right double and add
+// The scalar is processed by windows of 2 bits, the i-th window selecting k*4^i*base (k=0..3) in a
+// lookup table computed in plain go, so no doubling is done in the circuit:
+// 2 (select lookup table) + 7 (generic addition) + 2 (bool constraints) constraints per 2 bits
 // TODO passing a point a x, y interface{} is a bit ugly, but on the other hand creating a special struct{x, y interface{}} only for general point seems too much
 func (p *PointGadget) ScalarMulFixedBase(circuit *frontend.CS, x, y interface{}, scalar *frontend.Constraint, curve EdCurveGadget) *PointGadget {
 
 	// first unpack the scalar
 	b := circuit.TO_BINARY(scalar, 256)
 
+	// tmp[k] = k*4^i*base for the i-th window
+	// (the coordinates are copied, FromInterface sharing the words of big.Int inputs)
+	var tmp [4]fixedPoint
+	X := backend.FromInterface(x)
+	Y := backend.FromInterface(y)
+	tmp[1].X.Set(&X)
+	tmp[1].Y.Set(&Y)
+
+	var res PointGadget
+	for i := 0; i < len(b)/2; i++ {
+
+		// update lookup table
+		if i > 0 {
+			tmp[1].double(&tmp[1], curve).double(&tmp[1], curve)
+		}
+		tmp[0].X.SetUint64(0)
+		tmp[0].Y.SetUint64(1)
+		tmp[2].double(&tmp[1], curve)
+		tmp[3].add(&tmp[2], &tmp[1], curve)
+
+		// lookup tables for x, y coordinates of the current window (new ones for each window,
+		// the big.Int of the previous tables being referenced by the constraints)
+		var lutx, luty [4]big.Int
+		for k := 0; k < 4; k++ {
+			lutx[k].Set(&tmp[k].X)
+			luty[k].Set(&tmp[k].Y)
+		}
+
+		// select the point to add in the lookup table
+		toAdd := PointGadget{
+			X: circuit.SELECT_LUT(b[2*i+1], b[2*i], lutx),
+			Y: circuit.SELECT_LUT(b[2*i+1], b[2*i], luty),
+		}
+		if i == 0 {
+			res = toAdd
+		} else {
+			res.AddGeneric(circuit, &res, &toAdd, curve)
+		}
+	}
+
+	p.X = res.X
+	p.Y = res.Y
+	return p
+}
+
+// Neg computes the opposite of a point on a twisted Edwards curve: -(x, y) = (-x, y)
+func (p *PointGadget) Neg(circuit *frontend.CS, p1 *PointGadget, curve EdCurveGadget) *PointGadget {
+
+	debug.Assert(p1.X != nil && p1.Y != nil, "point not initialized")
+
+	// the constant is a coefficient of p1.X, no wire is allocated for it
+	var minusOne big.Int
+	minusOne.Sub(&curve.Modulus, big.NewInt(1))
+	p.X = circuit.MUL(p1.X, minusOne)
+	p.Y = p1.Y
+	return p
+}
+
+// DoubleScalarMulFixedBase computes s1*(x, y) + s2*p2 on a twisted Edwards curve
+// x, y: coordinates of the fixed base point
+// p2: non fixed point (as snark point)
+// curve: parameters of the Edwards curve
+// s1, s2: scalars as SNARK constraints
+// Left to right double and add, the doublings being shared by the two scalar multiplications
+func (p *PointGadget) DoubleScalarMulFixedBase(circuit *frontend.CS, x, y interface{}, s1 *frontend.Constraint, p2 *PointGadget, s2 *frontend.Constraint, curve EdCurveGadget) *PointGadget {
+
+	// first unpack the scalars
+	b1 := circuit.TO_BINARY(s1, 256)
+	b2 := circuit.TO_BINARY(s2, 256)
+
 	res := NewPointGadget(circuit, 0, 1)
 
-	for i := len(b) - 1; i >= 0; i-- {
+	for i := len(b1) - 1; i >= 0; i-- {
 		res.Double(circuit, &res, curve)
 		tmp := NewPointGadget(circuit, nil, nil)
 		tmp.AddFixedPoint(circuit, &res, x, y, curve)
-		res.X = circuit.SELECT(b[i], tmp.X, res.X)
-		res.Y = circuit.SELECT(b[i], tmp.Y, res.Y)
+		res.X = circuit.SELECT(b1[i], tmp.X, res.X)
+		res.Y = circuit.SELECT(b1[i], tmp.Y, res.Y)
+		tmp.AddGeneric(circuit, &res, p2, curve)
+		res.X = circuit.SELECT(b2[i], tmp.X, res.X)
+		res.Y = circuit.SELECT(b2[i], tmp.Y, res.Y)
 	}
 
 	p.X = res.X
@@ -230,108 +305,56 @@ func (p *PointGadget) ScalarMulFixedBase(circuit *frontend.CS, x, y interface{},
 	return p
 }
 
-// // ScalarMul computes the scalar multiplication of a point on a twisted Edwards curve
-// func (p *Point) ScalarMul(p1 interface{}, ecurve twistededwards.CurveParams, scalar *frontend.Constraint, n int) *Point {
-
-// 	switch point := p1.(type) {
-// 	// case *twistededwards.Point:
-// 	// 	p.scalarMulFixedBase(point, ecurve, scalar, n)
-// 	// case twistededwards.Point:
-// 	// 	p.scalarMulFixedBase(&point, ecurve, scalar, n)
-// 	case *Point:
-// 		p.scalarMulNonFixedBase(point, scalar, ecurve, n)
-// 	case Point:
-// 		p.scalarMulNonFixedBase(&point, scalar, ecurve, n)
-// 	}
-// 	return p
-// }
-
-// ScalarMulGadget computes the scalar multiplication of a point on a twisted Edwards curve
-// p1: base point (in plain go)
-// c: parameters of the curve
-// scal: scalar as a SNARK constraint
-// n: nbBits of the scalar
-// Without lookup table -> 6 constraints/bit (1 (bool constraint) + 3 (addition with fixed point) + 1 (select constraint) per bit)
-// With loopkup table -> 5.5 constraints/bit (7 (generic addition) + 2(bool constraints) + 2 (select lookup table) per 2 bits)
-// func (p *Point) scalarMulFixedBase(p1 *twistededwards.Point, ecurve twistededwards.CurveParams, scalar *frontend.Constraint, n int) *Point {
-
-// 	debug.Assert(p.circuit != nil, "point not initialized")
-
-// 	circuit := p.circuit
-
-// 	// fir	st unpack the scalar
-// 	b := circuit.TO_BINARY(scalar, n)
-
-// 	// look up tables for x, y coordinates of the current point
-// 	// lut[i] = coords of i*base_point for i=0..3
-// 	var lutx, luty [4]fr.Element
-// 	var tmp [4]twistededwards.Point
-
-// 	// infinity
-// 	tmp[0].X.SetZero()
-// 	tmp[0].Y.SetOne()
-// 	lutx[0] = tmp[0].X
-// 	luty[0] = tmp[0].Y
-
-// 	// p1
-// 	tmp[1] = *p1
-// 	lutx[1] = tmp[1].X
-// 	luty[1] = tmp[1].Y
-
-// 	// 2*p1
-// 	tmp[2].Double(p1, ecurve)
-// 	lutx[2] = tmp[2].X
-// 	luty[2] = tmp[2].Y
-
-// 	// 3*p1
-// 	tmp[3].Add(&tmp[2], &tmp[1], ecurve)
-// 	lutx[3] = tmp[3].X
-// 	luty[3] = tmp[3].Y
-
-// 	curPoint := &Point{circuit: p.circuit}
-// 	curPoint.X = circuit.SELECT_LUT(b[1], b[0], lutx)
-// 	curPoint.Y = circuit.SELECT_LUT(b[1], b[0], luty)
-
-// 	for i := 1; i < n/2; i++ {
-
-// 		// update lookup table
-// 		tmp[1].Double(&tmp[1], ecurve).Double(&tmp[1], ecurve)
-// 		tmp[2].Double(&tmp[2], ecurve).Double(&tmp[2], ecurve)
-// 		tmp[3].Double(&tmp[3], ecurve).Double(&tmp[3], ecurve)
-
-// 		lutx[1] = tmp[1].X
-// 		luty[1] = tmp[1].Y
-
-// 		lutx[2] = tmp[2].X
-// 		luty[2] = tmp[2].Y
-
-// 		lutx[3] = tmp[3].X
-// 		luty[3] = tmp[3].Y
-
-// 		// select the point to add in the lookup table
-// 		toAddx := circuit.SELECT_LUT(b[2*i+1], b[2*i], lutx)
-// 		toAddy := circuit.SELECT_LUT(b[2*i+1], b[2*i], luty)
-// 		toAdd := &Point{circuit: p.circuit, X: toAddx, Y: toAddy}
-
-// 		curPoint.AddGeneric(curPoint, toAdd, ecurve)
-// 	}
-
-// 	if n%2 != 0 {
-// 		tmp[2].Double(&tmp[2], ecurve)
-// 		lutx[2] = tmp[2].X
-// 		luty[2] = tmp[2].Y
-
-// 		var one, zero fr.Element
-// 		zero.SetZero()
-// 		one.SetOne()
-// 		last := &Point{circuit: p.circuit}
-// 		last.Add(curPoint, &tmp[2], ecurve)
-
-// 		curPoint.X = circuit.SELECT(b[n-1], last.X, curPoint.X)
-// 		curPoint.Y = circuit.SELECT(b[n-1], last.Y, curPoint.Y)
-// 	}
-
-// 	p.X = curPoint.X
-// 	p.Y = curPoint.Y
-// 	return p
-// }
+// MulByCofactor multiplies a point by the cofactor of the twisted Edwards curve
+// The cofactor being a small constant, this is done with a few additions instead of a full scalar multiplication
+func (p *PointGadget) MulByCofactor(circuit *frontend.CS, p1 *PointGadget, curve EdCurveGadget) *PointGadget {
+
+	debug.Assert(p1.X != nil && p1.Y != nil, "point not initialized")
+
+	res := PointGadget{p1.X, p1.Y}
+	for i := curve.Cofactor.BitLen() - 2; i >= 0; i-- {
+		res.Double(circuit, &res, curve)
+		if curve.Cofactor.Bit(i) == 1 {
+			res.AddGeneric(circuit, &res, p1, curve)
+		}
+	}
+
+	p.X = res.X
+	p.Y = res.Y
+	return p
+}
+
+// fixedPoint point on a twisted Edwards curve in plain go, used to compute the
+// constants (eg lookup tables) of the gadgets
+type fixedPoint struct {
+	X, Y big.Int
+}
+
+// add sets p to p1+p2 and returns it
+// x3 = (x1*y2+y1*x2)/(1+d*x1*x2*y1*y2), y3 = (y1*y2-a*x1*x2)/(1-d*x1*x2*y1*y2)
+func (p *fixedPoint) add(p1, p2 *fixedPoint, curve EdCurveGadget) *fixedPoint {
+
+	var xx, yy, xy, yx, dxxyy, num, den, x, y big.Int
+	xx.Mul(&p1.X, &p2.X)
+	yy.Mul(&p1.Y, &p2.Y)
+	xy.Mul(&p1.X, &p2.Y)
+	yx.Mul(&p1.Y, &p2.X)
+	dxxyy.Mul(&xx, &yy).Mul(&dxxyy, &curve.D).Mod(&dxxyy, &curve.Modulus)
+
+	num.Add(&xy, &yx)
+	den.Add(big.NewInt(1), &dxxyy).ModInverse(&den, &curve.Modulus)
+	x.Mul(&num, &den).Mod(&x, &curve.Modulus)
+
+	num.Mul(&curve.A, &xx).Sub(&yy, &num)
+	den.Sub(big.NewInt(1), &dxxyy).Mod(&den, &curve.Modulus).ModInverse(&den, &curve.Modulus)
+	y.Mul(&num, &den).Mod(&y, &curve.Modulus)
+
+	p.X.Set(&x)
+	p.Y.Set(&y)
+	return p
+}
+
+// double sets p to 2*p1 and returns it
+func (p *fixedPoint) double(p1 *fixedPoint, curve EdCurveGadget) *fixedPoint {
+	return p.add(p1, p1, curve)
+}
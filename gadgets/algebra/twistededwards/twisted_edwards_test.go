@@ -25,6 +25,7 @@ import (
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy"
 	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	edbn256 "github.com/consensys/gurvy/bn256/twistededwards"
 )
 
 func TestIsOnCurve(t *testing.T) {
@@ -247,3 +248,124 @@ func TestScalarMulNonFixedBase(t *testing.T) {
 
 	assertbn256.CorrectExecution(&r1csbn256, inputs, expectedValues)
 }
+
+func TestNeg(t *testing.T) {
+
+	circuit := frontend.New()
+
+	assertbn256 := groth16_bn256.NewAssert(t)
+
+	// set curve parameters
+	edgadget, err := NewEdCurveGadget(gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// set point in the circuit
+	pointSnark := NewPointGadget(&circuit, circuit.SECRET_INPUT("x"), circuit.SECRET_INPUT("y"))
+
+	inputs := backend.NewAssignment()
+	inputs.Assign(backend.Secret, "x", edgadget.BaseX)
+	inputs.Assign(backend.Secret, "y", edgadget.BaseY)
+
+	// P + (-P) = (0, 1)
+	var neg PointGadget
+	neg.Neg(&circuit, &pointSnark, edgadget)
+	neg.X.Tag("xn")
+	pointSnark.AddGeneric(&circuit, &pointSnark, &neg, edgadget)
+	pointSnark.X.Tag("xg")
+	pointSnark.Y.Tag("yg")
+
+	expectedValues := make(map[string]fr_bn256.Element)
+	var expectedX fr_bn256.Element
+	expectedX.SetBigInt(&edgadget.BaseX).Neg(&expectedX)
+	expectedValues["xn"] = expectedX
+	expectedValues["xg"] = fr_bn256.Element{}
+	expectedValues["yg"] = fr_bn256.One()
+
+	// creates r1cs
+	r1csbn256 := backend_bn256.New(&circuit)
+
+	assertbn256.CorrectExecution(&r1csbn256, inputs, expectedValues)
+}
+
+func TestDoubleScalarMulFixedBase(t *testing.T) {
+
+	circuit := frontend.New()
+
+	assertbn256 := groth16_bn256.NewAssert(t)
+
+	// set curve parameters
+	edgadget, err := NewEdCurveGadget(gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edcurve := edbn256.GetEdwardsCurve()
+
+	// p = 3*base, computes 28242048*base + 4201*p
+	var p edbn256.Point
+	var s fr_bn256.Element
+	p.ScalarMul(&edcurve.Base, *s.SetUint64(3).FromMont())
+
+	pointSnark := NewPointGadget(&circuit, circuit.SECRET_INPUT("x"), circuit.SECRET_INPUT("y"))
+	s1 := circuit.SECRET_INPUT("s1")
+	s2 := circuit.SECRET_INPUT("s2")
+
+	inputs := backend.NewAssignment()
+	inputs.Assign(backend.Secret, "x", p.X)
+	inputs.Assign(backend.Secret, "y", p.Y)
+	inputs.Assign(backend.Secret, "s1", 28242048)
+	inputs.Assign(backend.Secret, "s2", 4201)
+
+	var res PointGadget
+	res.DoubleScalarMulFixedBase(&circuit, edgadget.BaseX, edgadget.BaseY, s1, &pointSnark, s2, edgadget)
+	res.X.Tag("xg")
+	res.Y.Tag("yg")
+
+	var expected edbn256.Point
+	expected.ScalarMul(&edcurve.Base, *s.SetUint64(28242048 + 3*4201).FromMont())
+	expectedValues := make(map[string]fr_bn256.Element)
+	expectedValues["xg"] = expected.X
+	expectedValues["yg"] = expected.Y
+
+	// creates r1cs
+	r1csbn256 := backend_bn256.New(&circuit)
+
+	assertbn256.CorrectExecution(&r1csbn256, inputs, expectedValues)
+}
+
+func TestMulByCofactor(t *testing.T) {
+
+	circuit := frontend.New()
+
+	assertbn256 := groth16_bn256.NewAssert(t)
+
+	// set curve parameters
+	edgadget, err := NewEdCurveGadget(gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edcurve := edbn256.GetEdwardsCurve()
+
+	// set point in the circuit
+	pointSnark := NewPointGadget(&circuit, circuit.SECRET_INPUT("x"), circuit.SECRET_INPUT("y"))
+
+	inputs := backend.NewAssignment()
+	inputs.Assign(backend.Secret, "x", edgadget.BaseX)
+	inputs.Assign(backend.Secret, "y", edgadget.BaseY)
+
+	pointSnark.MulByCofactor(&circuit, &pointSnark, edgadget)
+	pointSnark.X.Tag("xg")
+	pointSnark.Y.Tag("yg")
+
+	var expected edbn256.Point
+	expected.ScalarMul(&edcurve.Base, edcurve.Cofactor)
+	expectedValues := make(map[string]fr_bn256.Element)
+	expectedValues["xg"] = expected.X
+	expectedValues["yg"] = expected.Y
+
+	// creates r1cs
+	r1csbn256 := backend_bn256.New(&circuit)
+
+	assertbn256.CorrectExecution(&r1csbn256, inputs, expectedValues)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	pedersenbls377 "github.com/consensys/gnark/crypto/commitment/pedersen/bls377"
+	pedersenbls381 "github.com/consensys/gnark/crypto/commitment/pedersen/bls381"
+	pedersenbn256 "github.com/consensys/gnark/crypto/commitment/pedersen/bn256"
+	"github.com/consensys/gnark/gadgets/algebra/twistededwards"
+
+	"github.com/consensys/gurvy"
+)
+
+var newPedersen map[gurvy.ID]func(string, int) (PedersenGadget, error)
+
+func init() {
+	newPedersen = make(map[gurvy.ID]func(string, int) (PedersenGadget, error))
+	newPedersen[gurvy.BN256] = newPedersenBN256
+	newPedersen[gurvy.BLS381] = newPedersenBLS381
+	newPedersen[gurvy.BLS377] = newPedersenBLS377
+}
+
+// -------------------------------------------------------------------------------------------------
+// constructors
+
+func newPedersenBN256(seed string, nbGenerators int) (PedersenGadget, error) {
+	params, err := pedersenbn256.NewParams(seed, nbGenerators)
+	if err != nil {
+		return PedersenGadget{}, err
+	}
+	curve, err := twistededwards.NewEdCurveGadget(gurvy.BN256)
+	if err != nil {
+		return PedersenGadget{}, err
+	}
+	res := PedersenGadget{
+		Curve:      curve,
+		Generators: make([]Point, len(params.Generators)),
+	}
+	for i, g := range params.Generators {
+		g.X.ToBigIntRegular(&res.Generators[i].X)
+		g.Y.ToBigIntRegular(&res.Generators[i].Y)
+	}
+	params.H.X.ToBigIntRegular(&res.H.X)
+	params.H.Y.ToBigIntRegular(&res.H.Y)
+	return res, nil
+}
+
+func newPedersenBLS381(seed string, nbGenerators int) (PedersenGadget, error) {
+	params, err := pedersenbls381.NewParams(seed, nbGenerators)
+	if err != nil {
+		return PedersenGadget{}, err
+	}
+	curve, err := twistededwards.NewEdCurveGadget(gurvy.BLS381)
+	if err != nil {
+		return PedersenGadget{}, err
+	}
+	res := PedersenGadget{
+		Curve:      curve,
+		Generators: make([]Point, len(params.Generators)),
+	}
+	for i, g := range params.Generators {
+		g.X.ToBigIntRegular(&res.Generators[i].X)
+		g.Y.ToBigIntRegular(&res.Generators[i].Y)
+	}
+	params.H.X.ToBigIntRegular(&res.H.X)
+	params.H.Y.ToBigIntRegular(&res.H.Y)
+	return res, nil
+}
+
+func newPedersenBLS377(seed string, nbGenerators int) (PedersenGadget, error) {
+	params, err := pedersenbls377.NewParams(seed, nbGenerators)
+	if err != nil {
+		return PedersenGadget{}, err
+	}
+	curve, err := twistededwards.NewEdCurveGadget(gurvy.BLS377)
+	if err != nil {
+		return PedersenGadget{}, err
+	}
+	res := PedersenGadget{
+		Curve:      curve,
+		Generators: make([]Point, len(params.Generators)),
+	}
+	for i, g := range params.Generators {
+		g.X.ToBigIntRegular(&res.Generators[i].X)
+		g.Y.ToBigIntRegular(&res.Generators[i].Y)
+	}
+	params.H.X.ToBigIntRegular(&res.H.X)
+	params.H.Y.ToBigIntRegular(&res.H.Y)
+	return res, nil
+}
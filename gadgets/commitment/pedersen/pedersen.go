@@ -0,0 +1,99 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pedersen provides the gadgets of the Pedersen hash and commitment of
+// crypto/commitment/pedersen, on the twisted Edwards curves of eddsa.
+package pedersen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets"
+	"github.com/consensys/gnark/gadgets/algebra/twistededwards"
+
+	"github.com/consensys/gurvy"
+)
+
+var (
+	ErrMessageSize = errors.New("the message has more elements than there are generators")
+)
+
+// Point coordinates of a generator
+type Point struct {
+	X, Y big.Int
+}
+
+// PedersenGadget contains the generators of the Pedersen gadget and the curve on which they are
+type PedersenGadget struct {
+	Curve      twistededwards.EdCurveGadget
+	Generators []Point // Generators[i] is multiplied by the i-th element of a message
+	H          Point   // blinding generator, multiplied by the randomness of a commitment
+}
+
+// NewPedersenGadget returns a Pedersen gadget with nbGenerators generators derived from seed,
+// than can be used in a circuit
+func NewPedersenGadget(seed string, nbGenerators int, id gurvy.ID) (PedersenGadget, error) {
+	if constructor, ok := newPedersen[id]; ok {
+		return constructor(seed, nbGenerators)
+	}
+	return PedersenGadget{}, gadgets.ErrUnknownCurve
+}
+
+// Hash computes (in r1cs form) the Pedersen hash of data, sum(data[i]*Generators[i])
+func (p PedersenGadget) Hash(circuit *frontend.CS, data ...*frontend.Constraint) (twistededwards.PointGadget, error) {
+	if len(data) > len(p.Generators) {
+		return twistededwards.PointGadget{}, ErrMessageSize
+	}
+
+	if len(data) == 0 {
+		return twistededwards.NewPointGadget(circuit, 0, 1), nil
+	}
+
+	var res twistededwards.PointGadget
+	for i := 0; i < len(data); i++ {
+		var tmp twistededwards.PointGadget
+		tmp.ScalarMulFixedBase(circuit, p.Generators[i].X, p.Generators[i].Y, data[i], p.Curve)
+		if i == 0 {
+			res = tmp
+		} else {
+			res.AddGeneric(circuit, &res, &tmp, p.Curve)
+		}
+	}
+
+	return res, nil
+}
+
+// Commit computes (in r1cs form) the Pedersen commitment to data with the randomness r,
+// sum(data[i]*Generators[i]) + r*H
+func (p PedersenGadget) Commit(circuit *frontend.CS, r *frontend.Constraint, data ...*frontend.Constraint) (twistededwards.PointGadget, error) {
+	if len(data) > len(p.Generators) {
+		return twistededwards.PointGadget{}, ErrMessageSize
+	}
+
+	var res twistededwards.PointGadget
+	res.ScalarMulFixedBase(circuit, p.H.X, p.H.Y, r, p.Curve)
+	if len(data) > 0 {
+		h, err := p.Hash(circuit, data...)
+		if err != nil {
+			return res, err
+		}
+		res.AddGeneric(circuit, &h, &res, p.Curve)
+	}
+
+	return res, nil
+}
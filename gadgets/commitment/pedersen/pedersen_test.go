@@ -0,0 +1,224 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"strconv"
+	"testing"
+
+	backend_bls377 "github.com/consensys/gnark/backend/bls377"
+	backend_bls381 "github.com/consensys/gnark/backend/bls381"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets"
+	"github.com/consensys/gurvy"
+
+	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
+	groth16_bls381 "github.com/consensys/gnark/backend/bls381/groth16"
+	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+
+	pedersenbls377 "github.com/consensys/gnark/crypto/commitment/pedersen/bls377"
+	pedersenbls381 "github.com/consensys/gnark/crypto/commitment/pedersen/bls381"
+	pedersenbn256 "github.com/consensys/gnark/crypto/commitment/pedersen/bn256"
+
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+// number of generators and of committed elements
+const nbElements = 2
+
+// circuit returns a circuit hash = Hash(data0, data1), commitment = Commit(r, data0, data1)
+func circuit(t *testing.T, id gurvy.ID) frontend.CS {
+	pedersenGadget, err := NewPedersenGadget("seed", nbElements, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	circuit := frontend.New()
+	data := make([]*frontend.Constraint, nbElements)
+	for i := 0; i < nbElements; i++ {
+		data[i] = circuit.SECRET_INPUT("data" + strconv.Itoa(i))
+	}
+	r := circuit.SECRET_INPUT("r")
+
+	hash, err := pedersenGadget.Hash(&circuit, data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash.X.Tag("hash_x")
+	hash.Y.Tag("hash_y")
+
+	commitment, err := pedersenGadget.Commit(&circuit, r, data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment.X.Tag("commitment_x")
+	commitment.Y.Tag("commitment_y")
+
+	return circuit
+}
+
+func TestPedersenBN256(t *testing.T) {
+
+	assertbn256 := groth16_bn256.NewAssert(t)
+
+	circuit := circuit(t, gurvy.BN256)
+
+	// running Pedersen (Go)
+	params, err := pedersenbn256.NewParams("seed", nbElements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := backend.NewAssignment()
+	data := make([]fr_bn256.Element, nbElements)
+	for i := 0; i < nbElements; i++ {
+		data[i].SetRandom()
+		inputs.Assign(backend.Secret, "data"+strconv.Itoa(i), data[i])
+	}
+	var r fr_bn256.Element
+	r.SetRandom()
+	inputs.Assign(backend.Secret, "r", r)
+
+	hash, err := params.Hash(data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment, err := params.Commit(r, data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedValues := make(map[string]fr_bn256.Element)
+	expectedValues["hash_x"] = hash.X
+	expectedValues["hash_y"] = hash.Y
+	expectedValues["commitment_x"] = commitment.X
+	expectedValues["commitment_y"] = commitment.Y
+
+	// creates r1cs
+	r1csbn256 := backend_bn256.New(&circuit)
+
+	assertbn256.CorrectExecution(&r1csbn256, inputs, expectedValues)
+
+}
+
+func TestPedersenBLS381(t *testing.T) {
+
+	assertbls381 := groth16_bls381.NewAssert(t)
+
+	circuit := circuit(t, gurvy.BLS381)
+
+	// running Pedersen (Go)
+	params, err := pedersenbls381.NewParams("seed", nbElements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := backend.NewAssignment()
+	data := make([]fr_bls381.Element, nbElements)
+	for i := 0; i < nbElements; i++ {
+		data[i].SetRandom()
+		inputs.Assign(backend.Secret, "data"+strconv.Itoa(i), data[i])
+	}
+	var r fr_bls381.Element
+	r.SetRandom()
+	inputs.Assign(backend.Secret, "r", r)
+
+	hash, err := params.Hash(data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment, err := params.Commit(r, data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedValues := make(map[string]fr_bls381.Element)
+	expectedValues["hash_x"] = hash.X
+	expectedValues["hash_y"] = hash.Y
+	expectedValues["commitment_x"] = commitment.X
+	expectedValues["commitment_y"] = commitment.Y
+
+	// creates r1cs
+	r1csbls381 := backend_bls381.New(&circuit)
+
+	assertbls381.CorrectExecution(&r1csbls381, inputs, expectedValues)
+
+}
+
+func TestPedersenBLS377(t *testing.T) {
+
+	assertbls377 := groth16_bls377.NewAssert(t)
+
+	circuit := circuit(t, gurvy.BLS377)
+
+	// running Pedersen (Go)
+	params, err := pedersenbls377.NewParams("seed", nbElements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := backend.NewAssignment()
+	data := make([]fr_bls377.Element, nbElements)
+	for i := 0; i < nbElements; i++ {
+		data[i].SetRandom()
+		inputs.Assign(backend.Secret, "data"+strconv.Itoa(i), data[i])
+	}
+	var r fr_bls377.Element
+	r.SetRandom()
+	inputs.Assign(backend.Secret, "r", r)
+
+	hash, err := params.Hash(data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment, err := params.Commit(r, data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedValues := make(map[string]fr_bls377.Element)
+	expectedValues["hash_x"] = hash.X
+	expectedValues["hash_y"] = hash.Y
+	expectedValues["commitment_x"] = commitment.X
+	expectedValues["commitment_y"] = commitment.Y
+
+	// creates r1cs
+	r1csbls377 := backend_bls377.New(&circuit)
+
+	assertbls377.CorrectExecution(&r1csbls377, inputs, expectedValues)
+
+}
+
+func TestPedersenErrors(t *testing.T) {
+	pedersenGadget, err := NewPedersenGadget("seed", 1, gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := frontend.New()
+	data := []*frontend.Constraint{circuit.SECRET_INPUT("data0"), circuit.SECRET_INPUT("data1")}
+	if _, err := pedersenGadget.Hash(&circuit, data...); err != ErrMessageSize {
+		t.Fatal("expected ErrMessageSize")
+	}
+	if _, err := pedersenGadget.Commit(&circuit, circuit.SECRET_INPUT("r"), data...); err != ErrMessageSize {
+		t.Fatal("expected ErrMessageSize")
+	}
+	if _, err := NewPedersenGadget("seed", 0, gurvy.BN256); err != pedersenbn256.ErrNbGenerators {
+		t.Fatal("expected ErrNbGenerators")
+	}
+	if _, err := NewPedersenGadget("seed", 1, gurvy.UNKNOWN); err != gadgets.ErrUnknownCurve {
+		t.Fatal("expected ErrUnknownCurve")
+	}
+}
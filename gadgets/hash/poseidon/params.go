@@ -0,0 +1,117 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/crypto/hash/poseidon/bls377"
+	"github.com/consensys/gnark/crypto/hash/poseidon/bls381"
+	"github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+
+	"github.com/consensys/gurvy"
+)
+
+var newPoseidon map[gurvy.ID]func(string, int) (PoseidonGadget, error)
+
+func init() {
+	newPoseidon = make(map[gurvy.ID]func(string, int) (PoseidonGadget, error))
+	newPoseidon[gurvy.BN256] = newPoseidonBN256
+	newPoseidon[gurvy.BLS381] = newPoseidonBLS381
+	newPoseidon[gurvy.BLS377] = newPoseidonBLS377
+}
+
+// -------------------------------------------------------------------------------------------------
+// constructors
+
+func newPoseidonBLS377(seed string, width int) (PoseidonGadget, error) {
+	params, err := bls377.NewParams(seed, width)
+	if err != nil {
+		return PoseidonGadget{}, err
+	}
+	res := PoseidonGadget{
+		Width:           params.Width,
+		NbFullRounds:    params.NbFullRounds,
+		NbPartialRounds: params.NbPartialRounds,
+		alpha:           bls377.Alpha,
+	}
+	for _, row := range params.RoundConstants {
+		res.RoundConstants = append(res.RoundConstants, make([]big.Int, len(row)))
+		for j, v := range row {
+			v.ToBigIntRegular(&res.RoundConstants[len(res.RoundConstants)-1][j])
+		}
+	}
+	for _, row := range params.MDS {
+		res.MDS = append(res.MDS, make([]big.Int, len(row)))
+		for j, v := range row {
+			v.ToBigIntRegular(&res.MDS[len(res.MDS)-1][j])
+		}
+	}
+	return res, nil
+}
+
+func newPoseidonBLS381(seed string, width int) (PoseidonGadget, error) {
+	params, err := bls381.NewParams(seed, width)
+	if err != nil {
+		return PoseidonGadget{}, err
+	}
+	res := PoseidonGadget{
+		Width:           params.Width,
+		NbFullRounds:    params.NbFullRounds,
+		NbPartialRounds: params.NbPartialRounds,
+		alpha:           bls381.Alpha,
+	}
+	for _, row := range params.RoundConstants {
+		res.RoundConstants = append(res.RoundConstants, make([]big.Int, len(row)))
+		for j, v := range row {
+			v.ToBigIntRegular(&res.RoundConstants[len(res.RoundConstants)-1][j])
+		}
+	}
+	for _, row := range params.MDS {
+		res.MDS = append(res.MDS, make([]big.Int, len(row)))
+		for j, v := range row {
+			v.ToBigIntRegular(&res.MDS[len(res.MDS)-1][j])
+		}
+	}
+	return res, nil
+}
+
+func newPoseidonBN256(seed string, width int) (PoseidonGadget, error) {
+	params, err := bn256.NewParams(seed, width)
+	if err != nil {
+		return PoseidonGadget{}, err
+	}
+	res := PoseidonGadget{
+		Width:           params.Width,
+		NbFullRounds:    params.NbFullRounds,
+		NbPartialRounds: params.NbPartialRounds,
+		alpha:           bn256.Alpha,
+	}
+	for _, row := range params.RoundConstants {
+		res.RoundConstants = append(res.RoundConstants, make([]big.Int, len(row)))
+		for j, v := range row {
+			v.ToBigIntRegular(&res.RoundConstants[len(res.RoundConstants)-1][j])
+		}
+	}
+	for _, row := range params.MDS {
+		res.MDS = append(res.MDS, make([]big.Int, len(row)))
+		for j, v := range row {
+			v.ToBigIntRegular(&res.MDS[len(res.MDS)-1][j])
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets"
+
+	"github.com/consensys/gurvy"
+)
+
+// PoseidonGadget contains the params of the Poseidon gadget and the curves on which it is implemented
+type PoseidonGadget struct {
+	Width           int
+	NbFullRounds    int
+	NbPartialRounds int
+	RoundConstants  [][]big.Int
+	MDS             [][]big.Int
+	alpha           int
+}
+
+// NewPoseidonGadget returns a Poseidon gadget with a permutation on width elements (2 to 9),
+// than can be used in a circuit
+func NewPoseidonGadget(seed string, width int, id gurvy.ID) (PoseidonGadget, error) {
+	if constructor, ok := newPoseidon[id]; ok {
+		return constructor(seed, width)
+	}
+	return PoseidonGadget{}, gadgets.ErrUnknownCurve
+}
+
+// Hash hash (in r1cs form) using the Poseidon sponge:
+// the first element of the state is initialized with len(data), the Width-1
+// others absorb the data, and the result is the second element of the state
+func (h PoseidonGadget) Hash(circuit *frontend.CS, data ...*frontend.Constraint) *frontend.Constraint {
+
+	state := make([]*frontend.Constraint, h.Width)
+	state[0] = circuit.ALLOCATE(len(data))
+	zero := circuit.ALLOCATE(0)
+	for i := 1; i < h.Width; i++ {
+		state[i] = zero
+	}
+
+	rate := h.Width - 1
+	nbBlocks := (len(data) + rate - 1) / rate
+	if nbBlocks == 0 {
+		nbBlocks = 1
+	}
+
+	for b := 0; b < nbBlocks; b++ {
+		for i := 0; i < rate && b*rate+i < len(data); i++ {
+			state[1+i] = circuit.ADD(state[1+i], data[b*rate+i])
+		}
+		state = h.permutation(circuit, state)
+	}
+
+	return state[1]
+}
+
+// permutation of the state expressed as r1cs
+func (h PoseidonGadget) permutation(circuit *frontend.CS, state []*frontend.Constraint) []*frontend.Constraint {
+
+	one := circuit.ALLOCATE(1)
+	half := h.NbFullRounds / 2
+
+	for r := 0; r < h.NbFullRounds+h.NbPartialRounds; r++ {
+		full := r < half || r >= half+h.NbPartialRounds
+
+		// round constants and S-box, on the first element only for partial rounds
+		for i := 0; i < h.Width; i++ {
+			if full || i == 0 {
+				state[i] = h.sbox(circuit, circuit.ADD(state[i], h.RoundConstants[r][i]))
+			}
+		}
+
+		// mix, each element being a single linear combination (one constraint).
+		// In partial rounds the round constants of the elements that skipped the S-box are added here
+		next := make([]*frontend.Constraint, h.Width)
+		for i := 0; i < h.Width; i++ {
+			lc := make(frontend.LinearCombination, 0, h.Width+1)
+			var constant, tmp big.Int
+			for j := 0; j < h.Width; j++ {
+				lc = append(lc, frontend.Term{Constraint: state[j], Coeff: h.MDS[i][j]})
+				if !full && j != 0 {
+					tmp.Mul(&h.MDS[i][j], &h.RoundConstants[r][j])
+					constant.Add(&constant, &tmp)
+				}
+			}
+			if constant.Sign() != 0 {
+				lc = append(lc, frontend.Term{Constraint: one, Coeff: constant})
+			}
+			next[i] = circuit.MUL(lc, frontend.LinearCombination{frontend.Term{Constraint: one, Coeff: *big.NewInt(1)}})
+		}
+		state = next
+	}
+
+	return state
+}
+
+// sbox returns x^alpha, by square and multiply
+func (h PoseidonGadget) sbox(circuit *frontend.CS, x *frontend.Constraint) *frontend.Constraint {
+	var res *frontend.Constraint
+	square := x
+	for e := h.alpha; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			if res == nil {
+				res = square
+			} else {
+				res = circuit.MUL(res, square)
+			}
+		}
+		if e > 1 {
+			square = circuit.MUL(square, square)
+		}
+	}
+	return res
+}
@@ -0,0 +1,181 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"strconv"
+	"testing"
+
+	backend_bls377 "github.com/consensys/gnark/backend/bls377"
+	backend_bls381 "github.com/consensys/gnark/backend/bls381"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets"
+	"github.com/consensys/gurvy"
+
+	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
+	groth16_bls381 "github.com/consensys/gnark/backend/bls381/groth16"
+	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+
+	poseidonbls377 "github.com/consensys/gnark/crypto/hash/poseidon/bls377"
+	poseidonbls381 "github.com/consensys/gnark/crypto/hash/poseidon/bls381"
+	poseidonbn256 "github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+// widths and number of hashed elements tested, the later covering partial and multiple absorptions
+var (
+	testWidths     = []int{2, 3, 5, 9}
+	testNbElements = []int{1, 2, 4, 9}
+)
+
+// circuit returns a circuit res = hash(data0, data1, ...)
+func circuit(t *testing.T, id gurvy.ID, width, nbElements int) frontend.CS {
+	poseidonGadget, err := NewPoseidonGadget("seed", width, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	circuit := frontend.New()
+	data := make([]*frontend.Constraint, nbElements)
+	for i := 0; i < nbElements; i++ {
+		data[i] = circuit.PUBLIC_INPUT("data" + strconv.Itoa(i))
+	}
+	result := poseidonGadget.Hash(&circuit, data...)
+	result.Tag("res")
+
+	return circuit
+}
+
+func TestPoseidonBN256(t *testing.T) {
+
+	assertbn256 := groth16_bn256.NewAssert(t)
+
+	for _, width := range testWidths {
+		for _, nbElements := range testNbElements {
+			circuit := circuit(t, gurvy.BN256, width, nbElements)
+
+			// running Poseidon (Go)
+			inputs := backend.NewAssignment()
+			var msg []byte
+			for i := 0; i < nbElements; i++ {
+				var data fr_bn256.Element
+				data.SetRandom()
+				inputs.Assign(backend.Public, "data"+strconv.Itoa(i), data)
+				msg = append(msg, data.Bytes()...)
+			}
+			b, err := poseidonbn256.Sum("seed", width, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedValues := make(map[string]fr_bn256.Element)
+			var tmp fr_bn256.Element
+			tmp.SetBytes(b)
+			expectedValues["res"] = tmp
+
+			// creates r1cs
+			r1csbn256 := backend_bn256.New(&circuit)
+
+			assertbn256.CorrectExecution(&r1csbn256, inputs, expectedValues)
+		}
+	}
+
+}
+
+func TestPoseidonBLS381(t *testing.T) {
+
+	assertbls381 := groth16_bls381.NewAssert(t)
+
+	for _, width := range testWidths {
+		for _, nbElements := range testNbElements {
+			circuit := circuit(t, gurvy.BLS381, width, nbElements)
+
+			// running Poseidon (Go)
+			inputs := backend.NewAssignment()
+			var msg []byte
+			for i := 0; i < nbElements; i++ {
+				var data fr_bls381.Element
+				data.SetRandom()
+				inputs.Assign(backend.Public, "data"+strconv.Itoa(i), data)
+				msg = append(msg, data.Bytes()...)
+			}
+			b, err := poseidonbls381.Sum("seed", width, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedValues := make(map[string]fr_bls381.Element)
+			var tmp fr_bls381.Element
+			tmp.SetBytes(b)
+			expectedValues["res"] = tmp
+
+			// creates r1cs
+			r1csbls381 := backend_bls381.New(&circuit)
+
+			assertbls381.CorrectExecution(&r1csbls381, inputs, expectedValues)
+		}
+	}
+
+}
+
+func TestPoseidonBLS377(t *testing.T) {
+
+	assertbls377 := groth16_bls377.NewAssert(t)
+
+	for _, width := range testWidths {
+		for _, nbElements := range testNbElements {
+			circuit := circuit(t, gurvy.BLS377, width, nbElements)
+
+			// running Poseidon (Go)
+			inputs := backend.NewAssignment()
+			var msg []byte
+			for i := 0; i < nbElements; i++ {
+				var data fr_bls377.Element
+				data.SetRandom()
+				inputs.Assign(backend.Public, "data"+strconv.Itoa(i), data)
+				msg = append(msg, data.Bytes()...)
+			}
+			b, err := poseidonbls377.Sum("seed", width, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedValues := make(map[string]fr_bls377.Element)
+			var tmp fr_bls377.Element
+			tmp.SetBytes(b)
+			expectedValues["res"] = tmp
+
+			// creates r1cs
+			r1csbls377 := backend_bls377.New(&circuit)
+
+			assertbls377.CorrectExecution(&r1csbls377, inputs, expectedValues)
+		}
+	}
+
+}
+
+func TestPoseidonUnsupported(t *testing.T) {
+	if _, err := NewPoseidonGadget("seed", 10, gurvy.BN256); err != poseidonbn256.ErrUnsupportedWidth {
+		t.Fatal("expected ErrUnsupportedWidth")
+	}
+	if _, err := NewPoseidonGadget("seed", 3, gurvy.UNKNOWN); err != gadgets.ErrUnknownCurve {
+		t.Fatal("expected ErrUnknownCurve")
+	}
+}
@@ -0,0 +1,272 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sha256
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Size the size of a SHA-256 checksum in bits
+const Size = 256
+
+// BlockSize the block size of SHA-256 in bits
+const BlockSize = 512
+
+var iv = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var k = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+// word 32 bits word, word[0] being the least significant bit
+type word [32]*frontend.Constraint
+
+// gadget holds the constant bits, which are folded when possible instead of creating constraints
+type gadget struct {
+	circuit   *frontend.CS
+	zero, one *frontend.Constraint
+}
+
+// Sum returns the SHA-256 checksum (in r1cs form) of msg, given as bits: 8 bits per byte, most significant bit first.
+// The checksum is returned as 256 boolean constraints, in the same order.
+func Sum(circuit *frontend.CS, msg ...*frontend.Constraint) []*frontend.Constraint {
+	g := gadget{
+		circuit: circuit,
+		zero:    circuit.ALLOCATE(0),
+		one:     circuit.ALLOCATE(1),
+	}
+
+	// ensure msg is made of bits
+	for _, b := range msg {
+		circuit.MUSTBE_BOOLEAN(b)
+	}
+
+	// padding: msg || 1 || 0...0 || len(msg) on 64 bits, the total being a multiple of BlockSize
+	padded := append([]*frontend.Constraint{}, msg...)
+	padded = append(padded, g.one)
+	for len(padded)%BlockSize != BlockSize-64 {
+		padded = append(padded, g.zero)
+	}
+	for i := 63; i >= 0; i-- {
+		padded = append(padded, g.constantBit(uint64(len(msg))>>uint(i)))
+	}
+
+	var h [8]word
+	for i := 0; i < 8; i++ {
+		h[i] = g.constant(iv[i])
+	}
+
+	for start := 0; start < len(padded); start += BlockSize {
+		h = g.compress(h, padded[start:start+BlockSize])
+	}
+
+	res := make([]*frontend.Constraint, 0, Size)
+	for i := 0; i < 8; i++ {
+		for j := 31; j >= 0; j-- {
+			res = append(res, h[i][j])
+		}
+	}
+	return res
+}
+
+// compress processes a block of 512 bits
+func (g *gadget) compress(h [8]word, block []*frontend.Constraint) [8]word {
+
+	// message schedule
+	var w [64]word
+	for i := 0; i < 16; i++ {
+		for j := 0; j < 32; j++ {
+			w[i][j] = block[i*32+31-j]
+		}
+	}
+	for i := 16; i < 64; i++ {
+		s0 := g.xor3(rotr(w[i-15], 7), rotr(w[i-15], 18), g.shr(w[i-15], 3))
+		s1 := g.xor3(rotr(w[i-2], 17), rotr(w[i-2], 19), g.shr(w[i-2], 10))
+		w[i] = g.add(0, w[i-16], s0, w[i-7], s1)
+	}
+
+	a, b, c, d, e, f, gg, hh := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+
+	for i := 0; i < 64; i++ {
+		S1 := g.xor3(rotr(e, 6), rotr(e, 11), rotr(e, 25))
+		ch := g.ch(e, f, gg)
+		S0 := g.xor3(rotr(a, 2), rotr(a, 13), rotr(a, 22))
+		maj := g.maj(a, b, c)
+
+		// t1 = hh + S1 + ch + k[i] + w[i], t2 = S0 + maj
+		// e = d + t1 and a = t1 + t2 are computed at once to save the decomposition of t1
+		newE := g.add(k[i], d, hh, S1, ch, w[i])
+		newA := g.add(k[i], hh, S1, ch, w[i], S0, maj)
+
+		hh, gg, f, e, d, c, b, a = gg, f, e, newE, c, b, a, newA
+	}
+
+	return [8]word{
+		g.add(0, h[0], a),
+		g.add(0, h[1], b),
+		g.add(0, h[2], c),
+		g.add(0, h[3], d),
+		g.add(0, h[4], e),
+		g.add(0, h[5], f),
+		g.add(0, h[6], gg),
+		g.add(0, h[7], hh),
+	}
+}
+
+// add returns the sum modulo 2**32 of a constant and words
+func (g *gadget) add(constant uint32, words ...word) word {
+	var lc frontend.LinearCombination
+	sum := uint64(constant)
+	for _, w := range words {
+		for i := 0; i < 32; i++ {
+			switch w[i] {
+			case g.zero:
+			case g.one:
+				sum += 1 << uint(i)
+			default:
+				lc = append(lc, frontend.Term{Constraint: w[i], Coeff: *new(big.Int).Lsh(big.NewInt(1), uint(i))})
+			}
+		}
+	}
+	if len(lc) == 0 {
+		return g.constant(uint32(sum))
+	}
+	if sum != 0 {
+		lc = append(lc, frontend.Term{Constraint: g.one, Coeff: *new(big.Int).SetUint64(sum)})
+	}
+
+	// the sum is at most len(words)+1 times 2**32, the carry bits are discarded
+	value := g.circuit.MUL(lc, frontend.LinearCombination{frontend.Term{Constraint: g.one, Coeff: *big.NewInt(1)}})
+	nbBits := 32 + bits.Len(uint(len(words)))
+	var res word
+	copy(res[:], g.circuit.TO_BINARY(value, nbBits)[:32])
+	return res
+}
+
+// ch returns (e and f) xor (not e and gg), bit by bit
+func (g *gadget) ch(e, f, gg word) word {
+	var res word
+	for i := 0; i < 32; i++ {
+		res[i] = g.selectBit(e[i], f[i], gg[i])
+	}
+	return res
+}
+
+// maj returns (a and b) xor (a and c) xor (b and c), bit by bit
+func (g *gadget) maj(a, b, c word) word {
+	var res word
+	for i := 0; i < 32; i++ {
+		// if a != b the majority is c, else it is a
+		res[i] = g.selectBit(g.xor(a[i], b[i]), c[i], a[i])
+	}
+	return res
+}
+
+// xor3 returns a xor b xor c
+func (g *gadget) xor3(a, b, c word) word {
+	var res word
+	for i := 0; i < 32; i++ {
+		res[i] = g.xor(g.xor(a[i], b[i]), c[i])
+	}
+	return res
+}
+
+// rotr rotates w by n bits to the right
+func rotr(w word, n int) word {
+	var res word
+	for i := 0; i < 32; i++ {
+		res[i] = w[(i+n)%32]
+	}
+	return res
+}
+
+// shr shifts w by n bits to the right
+func (g *gadget) shr(w word, n int) word {
+	var res word
+	for i := 0; i < 32; i++ {
+		if i+n < 32 {
+			res[i] = w[i+n]
+		} else {
+			res[i] = g.zero
+		}
+	}
+	return res
+}
+
+func (g *gadget) xor(a, b *frontend.Constraint) *frontend.Constraint {
+	switch {
+	case a == g.zero:
+		return b
+	case b == g.zero:
+		return a
+	case a == g.one:
+		return g.not(b)
+	case b == g.one:
+		return g.not(a)
+	case a == b:
+		return g.zero
+	}
+	return g.circuit.XOR(a, b)
+}
+
+func (g *gadget) not(a *frontend.Constraint) *frontend.Constraint {
+	switch a {
+	case g.zero:
+		return g.one
+	case g.one:
+		return g.zero
+	}
+	return g.circuit.NOT(a)
+}
+
+// selectBit returns x if b is set, y otherwise
+func (g *gadget) selectBit(b, x, y *frontend.Constraint) *frontend.Constraint {
+	switch {
+	case b == g.one || x == y:
+		return x
+	case b == g.zero:
+		return y
+	}
+	return g.circuit.SELECT(b, x, y)
+}
+
+func (g *gadget) constantBit(b uint64) *frontend.Constraint {
+	if b&1 == 1 {
+		return g.one
+	}
+	return g.zero
+}
+
+func (g *gadget) constant(v uint32) word {
+	var res word
+	for i := 0; i < 32; i++ {
+		res[i] = g.constantBit(uint64(v >> uint(i)))
+	}
+	return res
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sha256
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	"github.com/consensys/gnark/frontend"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+// sumCircuit defines the circuit h = sha256(m0, m1, ...), the checksum is tagged in 32 bits words h0, h1, ...
+func sumCircuit(t *testing.T, circuit *frontend.CS, nbBits int) {
+	bits := make([]*frontend.Constraint, nbBits)
+	for i := 0; i < len(bits); i++ {
+		bits[i] = circuit.SECRET_INPUT("m" + strconv.Itoa(i))
+	}
+	res := Sum(circuit, bits...)
+	if len(res) != Size {
+		t.Fatal("checksum should be 256 bits")
+	}
+	for i := 0; i < 8; i++ {
+		w := make([]interface{}, 32)
+		for j := 0; j < 32; j++ {
+			w[j] = res[i*32+31-j]
+		}
+		circuit.FROM_BINARY(w...).Tag("h" + strconv.Itoa(i))
+	}
+}
+
+func TestSHA256(t *testing.T) {
+
+	assert := groth16_bn256.NewAssert(t)
+
+	// test vectors from FIPS 180-2, the 56 bytes one spans 2 blocks once padded
+	vectors := []string{
+		"",
+		"abc",
+		"abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq",
+	}
+
+	for _, msg := range vectors {
+
+		// inputs
+		good := backend.NewAssignment()
+		for i := 0; i < 8*len(msg); i++ {
+			good.Assign(backend.Secret, "m"+strconv.Itoa(i), int((msg[i/8]>>uint(7-i%8))&1))
+		}
+
+		// expected checksum
+		expected := sha256.Sum256([]byte(msg))
+		expectedValues := make(map[string]fr_bn256.Element)
+		for i := 0; i < 8; i++ {
+			var v fr_bn256.Element
+			v.SetUint64(uint64(binary.BigEndian.Uint32(expected[i*4 : i*4+4])))
+			expectedValues["h"+strconv.Itoa(i)] = v
+		}
+
+		// the full groth16 round trip being slow, it is run on a single vector,
+		// the others are executed by the test engine
+		if msg == "abc" {
+			circuit := frontend.New()
+			sumCircuit(t, &circuit, 8*len(msg))
+			r1cs := backend_bn256.New(&circuit)
+			assert.Solved(&r1cs, good, expectedValues)
+			continue
+		}
+
+		circuit := frontend.NewTestEngine(fr_bn256.ElementModulus(), good)
+		sumCircuit(t, &circuit, 8*len(msg))
+		values, err := circuit.Inspect(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for tag, v := range expectedValues {
+			var expectedValue big.Int
+			v.ToBigIntRegular(&expectedValue)
+			if value := values[tag]; value.Cmp(&expectedValue) != 0 {
+				t.Fatal(tag, "expected", expectedValue.String(), "got", value.String())
+			}
+		}
+
+		// non boolean message
+		if len(msg) > 0 {
+			bad := backend.NewAssignment()
+			bad.Assign(backend.Secret, "m0", 2)
+			for i := 1; i < 8*len(msg); i++ {
+				bad.Assign(backend.Secret, "m"+strconv.Itoa(i), int((msg[i/8]>>uint(7-i%8))&1))
+			}
+			circuit := frontend.NewTestEngine(fr_bn256.ElementModulus(), bad)
+			sumCircuit(t, &circuit, 8*len(msg))
+			if _, err := circuit.Inspect(false); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+				t.Fatal("a non boolean message should not be solved")
+			}
+		}
+	}
+}
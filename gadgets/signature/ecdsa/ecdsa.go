@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecdsa
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets/algebra/nonnative"
+	"github.com/consensys/gnark/gadgets/algebra/secp256k1"
+)
+
+var (
+	ErrNbLimbs = errors.New("the elements of the signature and the message must have the number of limbs of the scalar field")
+)
+
+// PublicKeyGadget stores an ecdsa public key (a point of secp256k1) in a r1cs
+type PublicKeyGadget struct {
+	Q     secp256k1.PointGadget
+	Curve secp256k1.CurveGadget
+}
+
+// SignatureGadget stores a signature (r, s) as a gadget, r and s being elements of Curve.Fr
+type SignatureGadget struct {
+	R, S nonnative.Element
+}
+
+// Verify verifies an ecdsa signature of msg, the hash of the message as an element of Curve.Fr
+// cf https://en.wikipedia.org/wiki/Elliptic_Curve_Digital_Signature_Algorithm
+//
+// r and s must be canonical and non zero, and the public key must be on the curve;
+// then u1 = msg/s, u2 = r/s and the x coordinate of u1*G + u2*Q must be r modulo the order
+func Verify(circuit *frontend.CS, sig SignatureGadget, msg nonnative.Element, pubKey PublicKeyGadget) error {
+
+	curve := pubKey.Curve
+	fr := curve.Fr
+	for _, e := range []nonnative.Element{sig.R, sig.S, msg} {
+		if len(e.Limbs) != fr.NbLimbs {
+			return ErrNbLimbs
+		}
+	}
+
+	fr.MustBeReduced(circuit, sig.R)
+	fr.MustBeReduced(circuit, sig.S)
+	circuit.MUSTBE_EQ(fr.IsZero(circuit, sig.R), *big.NewInt(0))
+	circuit.MUSTBE_EQ(fr.IsZero(circuit, sig.S), *big.NewInt(0))
+	pubKey.Q.MustBeOnCurve(circuit, curve)
+
+	w := fr.Inverse(circuit, sig.S)
+	u1 := fr.Mul(circuit, msg, w)
+	u2 := fr.Mul(circuit, sig.R, w)
+
+	var p secp256k1.PointGadget
+	p.DoubleScalarMulFixedBase(circuit, curve.BaseX, curve.BaseY, u1, &pubKey.Q, u2, curve)
+
+	// x = X/Z is 0 if p is the point at infinity, which is rejected as r is not 0;
+	// its canonical representative (less than the modulus of Fp) is reduced modulo the order
+	x, _ := p.ToAffine(circuit, curve)
+	fr.MustBeEqual(circuit, curve.Fp.Reduce(circuit, x), sig.R)
+
+	return nil
+}
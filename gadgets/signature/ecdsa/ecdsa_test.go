@@ -0,0 +1,176 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecdsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets/algebra/nonnative"
+	"github.com/consensys/gnark/gadgets/algebra/secp256k1"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+// point affine point of secp256k1 in plain go, nil being the point at infinity
+type point struct {
+	x, y big.Int
+}
+
+func add(p1, p2 *point, curve secp256k1.CurveGadget) *point {
+	if p1 == nil {
+		return p2
+	}
+	if p2 == nil {
+		return p1
+	}
+	p := &curve.Fp.Modulus
+	var num, den, lambda big.Int
+	if p1.x.Cmp(&p2.x) == 0 {
+		if p1.y.Cmp(&p2.y) != 0 || p1.y.Sign() == 0 {
+			return nil
+		}
+		num.Mul(&p1.x, &p1.x).Mul(&num, big.NewInt(3))
+		den.Lsh(&p1.y, 1)
+	} else {
+		num.Sub(&p2.y, &p1.y)
+		den.Sub(&p2.x, &p1.x)
+	}
+	den.Mod(&den, p).ModInverse(&den, p)
+	lambda.Mul(&num, &den).Mod(&lambda, p)
+
+	res := &point{}
+	res.x.Mul(&lambda, &lambda).Sub(&res.x, &p1.x).Sub(&res.x, &p2.x).Mod(&res.x, p)
+	res.y.Sub(&p1.x, &res.x).Mul(&res.y, &lambda).Sub(&res.y, &p1.y).Mod(&res.y, p)
+	return res
+}
+
+func scalarMul(p *point, s *big.Int, curve secp256k1.CurveGadget) *point {
+	var res *point
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = add(res, res, curve)
+		if s.Bit(i) == 1 {
+			res = add(res, p, curve)
+		}
+	}
+	return res
+}
+
+// sign returns the public key of the private key d and the signature (r, s) of the hash e
+func sign(d, e *big.Int, curve secp256k1.CurveGadget) (q *point, r, s big.Int) {
+	n := &curve.Fr.Modulus
+	g := &point{}
+	g.x.Set(&curve.BaseX)
+	g.y.Set(&curve.BaseY)
+	q = scalarMul(g, d, curve)
+
+	for r.Sign() == 0 || s.Sign() == 0 {
+		k, err := rand.Int(rand.Reader, n)
+		if err != nil || k.Sign() == 0 {
+			continue
+		}
+		r.Mod(&scalarMul(g, k, curve).x, n)
+		s.Mul(&r, d).Add(&s, e).Mul(&s, new(big.Int).ModInverse(k, n)).Mod(&s, n)
+	}
+	return q, r, s
+}
+
+// verifyCircuit defines the circuit verifying the signature (r, s) of the hash e by the public key (qx, qy)
+func verifyCircuit(circuit *frontend.CS, curve secp256k1.CurveGadget) error {
+	pubKey := PublicKeyGadget{
+		Q:     secp256k1.NewPointGadget(circuit, curve.Fp.PublicInput(circuit, "qx"), curve.Fp.PublicInput(circuit, "qy"), curve),
+		Curve: curve,
+	}
+	sig := SignatureGadget{
+		R: curve.Fr.SecretInput(circuit, "r"),
+		S: curve.Fr.SecretInput(circuit, "s"),
+	}
+	return Verify(circuit, sig, curve.Fr.PublicInput(circuit, "e"), pubKey)
+}
+
+func TestVerify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the verification of secp256k1 signatures in short mode")
+	}
+
+	curve, err := secp256k1.NewCurveGadget()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sign the sha256 hash of a message
+	d, _ := rand.Int(rand.Reader, &curve.Fr.Modulus)
+	h := sha256.Sum256([]byte("gnark"))
+	var e big.Int
+	e.SetBytes(h[:])
+	q, r, s := sign(d, &e, curve)
+
+	assign := func(e, r, s *big.Int) backend.Assignments {
+		res := backend.NewAssignment()
+		curve.Fp.Assign(res, backend.Public, "qx", q.x)
+		curve.Fp.Assign(res, backend.Public, "qy", q.y)
+		curve.Fr.Assign(res, backend.Public, "e", e)
+		curve.Fr.Assign(res, backend.Secret, "r", r)
+		curve.Fr.Assign(res, backend.Secret, "s", s)
+		return res
+	}
+
+	// valid signature
+	circuit := frontend.NewTestEngine(fr_bn256.ElementModulus(), assign(&e, &r, &s))
+	if err := verifyCircuit(&circuit, curve); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := circuit.Inspect(false); err != nil {
+		t.Fatal(err)
+	}
+
+	// signature of another message
+	var wrong big.Int
+	wrong.Add(&e, big.NewInt(1))
+	circuit = frontend.NewTestEngine(fr_bn256.ElementModulus(), assign(&wrong, &r, &s))
+	if err := verifyCircuit(&circuit, curve); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := circuit.Inspect(false); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("the signature of another message should not be verified")
+	}
+}
+
+func TestNbLimbs(t *testing.T) {
+
+	curve, err := secp256k1.NewCurveGadget()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	circuit := frontend.New()
+	pubKey := PublicKeyGadget{
+		Q:     secp256k1.NewPointGadget(&circuit, curve.BaseX, curve.BaseY, curve),
+		Curve: curve,
+	}
+	sig := SignatureGadget{
+		R: curve.Fr.NewElement(&circuit, 1),
+		S: curve.Fr.NewElement(&circuit, 1),
+	}
+	if err := Verify(&circuit, sig, nonnative.Element{}, pubKey); err != ErrNbLimbs {
+		t.Fatal("expected", ErrNbLimbs, "got", err)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eddsa
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets/algebra/twistededwards"
+	"github.com/consensys/gnark/gadgets/hash/mimc"
+)
+
+// batchChallengeBits number of bits of the coefficients of the random linear combination
+const batchChallengeBits = 128
+
+var (
+	ErrBatchSize  = errors.New("the numbers of signatures, messages and public keys differ")
+	ErrBatchCurve = errors.New("the public keys of a batch must be on the same curve")
+)
+
+// BatchVerify verifies the eddsa signatures sigs[i] of msgs[i] by pubKeys[i]
+//
+// Instead of checking cofactor*S*B == cofactor*(R+H(R,A,M)*A) for each signature, it checks
+// cofactor * Σ z_i*(S_i*B - H(R_i,A_i,M_i)*A_i - R_i) == 0, where z_0 = 1 and z_i is the
+// low batchChallengeBits bits of z^i, z being a Fiat-Shamir challenge hashing the signatures.
+// The doublings are shared between the scalar multiplications, and the cofactor multiplication
+// is done once for the batch.
+func BatchVerify(circuit *frontend.CS, sigs []SignatureGadget, msgs []*frontend.Constraint, pubKeys []PublicKeyGadget) error {
+
+	if len(sigs) != len(msgs) || len(sigs) != len(pubKeys) {
+		return ErrBatchSize
+	}
+	if len(sigs) == 0 {
+		return nil
+	}
+	curve := pubKeys[0].Curve
+	for i := range pubKeys {
+		if pubKeys[i].Curve.ID != curve.ID {
+			return ErrBatchCurve
+		}
+	}
+
+	mimcGadget, err := mimc.NewMiMCGadget("seed", curve.ID)
+	if err != nil {
+		return err
+	}
+
+	// points[i] = S_i*B - H(R_i,A_i,M_i)*A_i - R_i, which is of small order if the signature is valid
+	points := make([]twistededwards.PointGadget, len(sigs))
+	challengeData := make([]*frontend.Constraint, 0, 2*len(sigs))
+	for i := range sigs {
+
+		// compute H(R, A, M), all parameters in data are in Montgomery form
+		hramAllocated := mimcGadget.Hash(circuit,
+			sigs[i].R.A.X,
+			sigs[i].R.A.Y,
+			pubKeys[i].A.X,
+			pubKeys[i].A.Y,
+			msgs[i],
+		)
+
+		// H(R, A, M) binds R, A and M, the challenge binds it and S
+		challengeData = append(challengeData, hramAllocated, sigs[i].S)
+
+		var negA, negR twistededwards.PointGadget
+		negA.Neg(circuit, &pubKeys[i].A, curve)
+		negR.Neg(circuit, &sigs[i].R.A, curve)
+		points[i].DoubleScalarMulFixedBase(circuit, curve.BaseX, curve.BaseY, sigs[i].S, &negA, hramAllocated, curve).
+			AddGeneric(circuit, &points[i], &negR, curve)
+	}
+
+	// z_i = z^i, of which the low batchChallengeBits bits are used
+	z := mimcGadget.Hash(circuit, challengeData...)
+	coeffs := make([][]*frontend.Constraint, len(sigs))
+	zi := z
+	for i := 1; i < len(sigs); i++ {
+		if i > 1 {
+			zi = circuit.MUL(zi, z)
+		}
+		coeffs[i] = circuit.TO_BINARY(zi, 256)[:batchChallengeBits]
+	}
+
+	// res = Σ z_i*points[i], the doublings being shared (Straus)
+	res := twistededwards.NewPointGadget(circuit, 0, 1)
+	if len(sigs) > 1 {
+		for j := batchChallengeBits - 1; j >= 0; j-- {
+			res.Double(circuit, &res, curve)
+			for i := 1; i < len(sigs); i++ {
+				tmp := twistededwards.NewPointGadget(circuit, nil, nil)
+				tmp.AddGeneric(circuit, &res, &points[i], curve)
+				res.X = circuit.SELECT(coeffs[i][j], tmp.X, res.X)
+				res.Y = circuit.SELECT(coeffs[i][j], tmp.Y, res.Y)
+			}
+		}
+	}
+	res.AddGeneric(circuit, &res, &points[0], curve)
+
+	// cofactor*res must be the neutral element (0, 1)
+	res.MulByCofactor(circuit, &res, curve)
+	circuit.MUSTBE_EQ(res.X, *big.NewInt(0))
+	circuit.MUSTBE_EQ(res.Y, *big.NewInt(1))
+
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eddsa
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	backend_bn256 "github.com/consensys/gnark/backend/bn256"
+	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	mimc_bn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	eddsa_bn256 "github.com/consensys/gnark/crypto/signature/eddsa/bn256"
+	"github.com/consensys/gnark/frontend"
+	twistededwards_gadget "github.com/consensys/gnark/gadgets/algebra/twistededwards"
+	"github.com/consensys/gurvy"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+// batchCircuit allocates n signatures, messages and public keys as public inputs
+func batchCircuit(t testing.TB, circuit *frontend.CS, n int) ([]SignatureGadget, []*frontend.Constraint, []PublicKeyGadget) {
+	paramsGadget, err := twistededwards_gadget.NewEdCurveGadget(gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs := make([]SignatureGadget, n)
+	msgs := make([]*frontend.Constraint, n)
+	pubKeys := make([]PublicKeyGadget, n)
+	for i := 0; i < n; i++ {
+		id := strconv.Itoa(i)
+		pubKeys[i].A.X = circuit.PUBLIC_INPUT("pubkeyX" + id)
+		pubKeys[i].A.Y = circuit.PUBLIC_INPUT("pubkeyY" + id)
+		pubKeys[i].Curve = paramsGadget
+		sigs[i].R.A.X = circuit.PUBLIC_INPUT("sigRX" + id)
+		sigs[i].R.A.Y = circuit.PUBLIC_INPUT("sigRY" + id)
+		sigs[i].S = circuit.PUBLIC_INPUT("sigS" + id)
+		msgs[i] = circuit.PUBLIC_INPUT("message" + id)
+	}
+	return sigs, msgs, pubKeys
+}
+
+// signBatch signs n messages with n keys, and returns the corresponding assignment
+func signBatch(t *testing.T, n int) backend.Assignments {
+	res := backend.NewAssignment()
+	for i := 0; i < n; i++ {
+		id := strconv.Itoa(i)
+
+		var seed [32]byte
+		copy(seed[:], "eddsa"+id)
+		hFunc := mimc_bn256.NewMiMC("seed")
+		pubKey, privKey := eddsa_bn256.New(seed, hFunc)
+
+		var msg fr_bn256.Element
+		msg.SetUint64(uint64(42 + i))
+		signature, err := eddsa_bn256.Sign(msg, pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res.Assign(backend.Public, "message"+id, msg)
+		res.Assign(backend.Public, "pubkeyX"+id, pubKey.A.X)
+		res.Assign(backend.Public, "pubkeyY"+id, pubKey.A.Y)
+		res.Assign(backend.Public, "sigRX"+id, signature.R.X)
+		res.Assign(backend.Public, "sigRY"+id, signature.R.Y)
+		res.Assign(backend.Public, "sigS"+id, signature.S)
+	}
+	return res
+}
+
+func TestBatchVerify(t *testing.T) {
+
+	assert := groth16_bn256.NewAssert(t)
+
+	const n = 2
+	circuit := frontend.New()
+	sigs, msgs, pubKeys := batchCircuit(t, &circuit, n)
+	if err := BatchVerify(&circuit, sigs, msgs, pubKeys); err != nil {
+		t.Fatal(err)
+	}
+	r1cs := backend_bn256.New(&circuit)
+
+	// all the signatures are valid
+	good := signBatch(t, n)
+	assert.CorrectExecution(&r1cs, good, nil)
+
+	// one of the messages is wrong
+	bad := signBatch(t, n)
+	delete(bad, "message1")
+	bad.Assign(backend.Public, "message1", 1)
+	assert.NotSolved(&r1cs, bad)
+
+	// two signatures are swapped
+	bad = signBatch(t, n)
+	for _, name := range []string{"sigRX", "sigRY", "sigS"} {
+		bad[name+"0"], bad[name+"1"] = bad[name+"1"], bad[name+"0"]
+	}
+	assert.NotSolved(&r1cs, bad)
+}
+
+func TestBatchVerifySize(t *testing.T) {
+	circuit := frontend.New()
+	sigs, msgs, pubKeys := batchCircuit(t, &circuit, 2)
+	if err := BatchVerify(&circuit, sigs, msgs[:1], pubKeys); err != ErrBatchSize {
+		t.Fatal("expected ErrBatchSize, got", err)
+	}
+}
+
+// benchmarks: the number of constraints per signature, verified one by one or by batches
+
+func benchmarkConstraints(b *testing.B, n int, batch bool) {
+	var nbConstraints int
+	for i := 0; i < b.N; i++ {
+		circuit := frontend.New()
+		sigs, msgs, pubKeys := batchCircuit(b, &circuit, n)
+		if batch {
+			if err := BatchVerify(&circuit, sigs, msgs, pubKeys); err != nil {
+				b.Fatal(err)
+			}
+		} else {
+			for j := range sigs {
+				if err := Verify(&circuit, sigs[j], msgs[j], pubKeys[j]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		nbConstraints = circuit.ToR1CS().NbConstraints
+	}
+	b.ReportMetric(float64(nbConstraints)/float64(n), "constraints/signature")
+}
+
+func BenchmarkVerifyConstraints(b *testing.B) {
+	for _, n := range []int{1, 4, 16} {
+		b.Run(strconv.Itoa(n)+"/verify", func(b *testing.B) { benchmarkConstraints(b, n, false) })
+		b.Run(strconv.Itoa(n)+"/batch", func(b *testing.B) { benchmarkConstraints(b, n, true) })
+	}
+}
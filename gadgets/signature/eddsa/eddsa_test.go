@@ -20,13 +20,18 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark/backend"
+	backend_bls377 "github.com/consensys/gnark/backend/bls377"
+	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
 	backend_bn256 "github.com/consensys/gnark/backend/bn256"
 	groth16_bn256 "github.com/consensys/gnark/backend/bn256/groth16"
+	mimc_bls377 "github.com/consensys/gnark/crypto/hash/mimc/bls377"
 	mimc_bn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	eddsa_bls377 "github.com/consensys/gnark/crypto/signature/eddsa/bls377"
 	eddsa_bn256 "github.com/consensys/gnark/crypto/signature/eddsa/bn256"
 	"github.com/consensys/gnark/frontend"
 	twistededwards_gadget "github.com/consensys/gnark/gadgets/algebra/twistededwards"
 	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
 	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
 )
 
@@ -112,3 +117,86 @@ func TestEddsaGadget(t *testing.T) {
 	bad.Assign(backend.Public, "sigS", signature.S)
 	assert.NotSolved(&r1cs, bad)
 }
+
+func TestEddsaGadgetBLS377(t *testing.T) {
+
+	assert := groth16_bls377.NewAssert(t)
+
+	var seed [32]byte
+	s := []byte("eddsa")
+	for i, v := range s {
+		seed[i] = v
+	}
+
+	hFunc := mimc_bls377.NewMiMC("seed")
+
+	// create eddsa obj and sign a message
+	pubKey, privKey := eddsa_bls377.New(seed, hFunc)
+	var msg fr_bls377.Element
+	msg.SetString("4471765074615574846010125752507885313883731157696221292364954764414829703597")
+	signature, err := eddsa_bls377.Sign(msg, pubKey, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := eddsa_bls377.Verify(signature, msg, pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verifying the signature should return true")
+	}
+
+	// Set the eddsa circuit and the gadget
+	circuit := frontend.New()
+
+	paramsGadget, err := twistededwards_gadget.NewEdCurveGadget(gurvy.BLS377)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Allocate the data in the circuit
+	var pubKeyAllocated PublicKeyGadget
+	pubKeyAllocated.A.X = circuit.PUBLIC_INPUT("pubkeyX")
+	pubKeyAllocated.A.Y = circuit.PUBLIC_INPUT("pubkeyY")
+	pubKeyAllocated.Curve = paramsGadget
+
+	var sigAllocated SignatureGadget
+	sigAllocated.R.A.X = circuit.PUBLIC_INPUT("sigRX")
+	sigAllocated.R.A.Y = circuit.PUBLIC_INPUT("sigRY")
+
+	sigAllocated.S = circuit.PUBLIC_INPUT("sigS")
+
+	msgAllocated := circuit.PUBLIC_INPUT("message")
+
+	// verify the signature in the circuit
+	Verify(&circuit, sigAllocated, msgAllocated, pubKeyAllocated)
+
+	// verification with the correct message
+	good := backend.NewAssignment()
+	good.Assign(backend.Public, "message", msg)
+
+	good.Assign(backend.Public, "pubkeyX", pubKey.A.X)
+	good.Assign(backend.Public, "pubkeyY", pubKey.A.Y)
+
+	good.Assign(backend.Public, "sigRX", signature.R.X)
+	good.Assign(backend.Public, "sigRY", signature.R.Y)
+
+	good.Assign(backend.Public, "sigS", signature.S)
+
+	r1cs := backend_bls377.New(&circuit)
+
+	assert.CorrectExecution(&r1cs, good, nil)
+
+	// verification with incorrect message
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Public, "message", "4471765074615574846010125752507885313883731157696221292364954764414829703598")
+
+	bad.Assign(backend.Public, "pubkeyX", pubKey.A.X)
+	bad.Assign(backend.Public, "pubkeyY", pubKey.A.Y)
+
+	bad.Assign(backend.Public, "sigRX", signature.R.X)
+	bad.Assign(backend.Public, "sigRY", signature.R.Y)
+
+	bad.Assign(backend.Public, "sigS", signature.S)
+	assert.NotSolved(&r1cs, bad)
+}
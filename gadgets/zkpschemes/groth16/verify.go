@@ -0,0 +1,156 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package groth16 provides a gadget verifying a Groth16 proof on BLS12-377 (backend/bls377/groth16).
+//
+// The circuit operates on the base field of BLS12-377, which is the scalar field of BW6-761:
+// it is meant to be proven on BW6-761, eg to aggregate several BLS12-377 proofs in one.
+package groth16
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/backend"
+	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/gadgets/algebra/bls377"
+	"github.com/consensys/gurvy/bls377/fr"
+)
+
+// ProofGadget Groth16 proof (in r1cs form), cf groth16.Proof
+type ProofGadget struct {
+	Ar, Krs bls377.G1Gadget
+	Bs      bls377.G2Gadget
+}
+
+// VerifyingKeyGadget Groth16 verifying key (in r1cs form), cf groth16.VerifyingKey
+// Only the parts used by Verify are present
+type VerifyingKeyGadget struct {
+	// e(α, β)
+	E bls377.Fp12Gadget
+
+	// -[γ]2, -[δ]2
+	G2 struct {
+		GammaNeg, DeltaNeg bls377.G2Gadget
+	}
+
+	// [Kvk]1
+	G1 struct {
+		K []bls377.G1Gadget // The indexes correspond to the public wires
+	}
+
+	PublicInputs []string // maps the name of the public input to it's index in K
+}
+
+// NewProofGadget declares the coordinates of a proof as secret inputs of the circuit,
+// their names being prefixed by name (see AssignProof)
+func NewProofGadget(circuit *frontend.CS, name string) ProofGadget {
+	g1 := func(point string) bls377.G1Gadget {
+		return bls377.NewG1Gadget(circuit,
+			circuit.SECRET_INPUT(name+"."+point+".X"),
+			circuit.SECRET_INPUT(name+"."+point+".Y"),
+		)
+	}
+	return ProofGadget{
+		Ar:  g1("Ar"),
+		Krs: g1("Krs"),
+		Bs: bls377.NewG2Gadget(
+			bls377.NewFp2Gadget(circuit, circuit.SECRET_INPUT(name+".Bs.X.A0"), circuit.SECRET_INPUT(name+".Bs.X.A1")),
+			bls377.NewFp2Gadget(circuit, circuit.SECRET_INPUT(name+".Bs.Y.A0"), circuit.SECRET_INPUT(name+".Bs.Y.A1")),
+		),
+	}
+}
+
+// AssignProof assigns the coordinates of proof to the secret inputs declared by NewProofGadget
+func AssignProof(assignment backend.Assignments, name string, proof *groth16_bls377.Proof) {
+	assignment.Assign(backend.Secret, name+".Ar.X", proof.Ar.X)
+	assignment.Assign(backend.Secret, name+".Ar.Y", proof.Ar.Y)
+	assignment.Assign(backend.Secret, name+".Krs.X", proof.Krs.X)
+	assignment.Assign(backend.Secret, name+".Krs.Y", proof.Krs.Y)
+	assignment.Assign(backend.Secret, name+".Bs.X.A0", proof.Bs.X.A0)
+	assignment.Assign(backend.Secret, name+".Bs.X.A1", proof.Bs.X.A1)
+	assignment.Assign(backend.Secret, name+".Bs.Y.A0", proof.Bs.Y.A0)
+	assignment.Assign(backend.Secret, name+".Bs.Y.A1", proof.Bs.Y.A1)
+}
+
+// NewVerifyingKeyGadget allocates vk as constants of the circuit
+func NewVerifyingKeyGadget(circuit *frontend.CS, vk *groth16_bls377.VerifyingKey) VerifyingKeyGadget {
+	var res VerifyingKeyGadget
+	res.E = bls377.NewFp12Gadget(circuit, vk.E)
+	res.G2.GammaNeg = bls377.NewG2Gadget(
+		bls377.NewFp2Gadget(circuit, vk.G2.GammaNeg.X.A0, vk.G2.GammaNeg.X.A1),
+		bls377.NewFp2Gadget(circuit, vk.G2.GammaNeg.Y.A0, vk.G2.GammaNeg.Y.A1),
+	)
+	res.G2.DeltaNeg = bls377.NewG2Gadget(
+		bls377.NewFp2Gadget(circuit, vk.G2.DeltaNeg.X.A0, vk.G2.DeltaNeg.X.A1),
+		bls377.NewFp2Gadget(circuit, vk.G2.DeltaNeg.Y.A0, vk.G2.DeltaNeg.Y.A1),
+	)
+	res.G1.K = make([]bls377.G1Gadget, len(vk.G1.K))
+	for i := range vk.G1.K {
+		res.G1.K[i] = bls377.NewG1Gadget(circuit, vk.G1.K[i].X, vk.G1.K[i].Y)
+	}
+	res.PublicInputs = vk.PublicInputs
+	return res
+}
+
+// Verify constrains proof to be a valid proof for vk and the public inputs, mirroring groth16.Verify:
+// e(Ar, Bs) * e(Krs, -[δ]2) * e(Σx.[Kvk(t)]1, -[γ]2) == e(α, β)
+//
+// inputs maps the names of the public inputs (vk.PublicInputs) to constraints holding their values.
+// An error is returned if one of them is missing.
+//
+// The points of the proof are constrained to be on the curve (or the twist) and in the subgroup of order r,
+// as the formulas of the gadgets are incomplete and the pairing is only defined on these subgroups.
+func Verify(circuit *frontend.CS, proof *ProofGadget, vk *VerifyingKeyGadget, inputs map[string]*frontend.Constraint) error {
+
+	// Σx.[Kvk(t)]1, starting with the term of the ONE_WIRE
+	var kSum *bls377.G1Gadget
+	for i, name := range vk.PublicInputs {
+		if name == backend.OneWire {
+			kSum = &bls377.G1Gadget{X: vk.G1.K[i].X, Y: vk.G1.K[i].Y}
+		}
+	}
+	if kSum == nil {
+		return fmt.Errorf("%q: %w", backend.OneWire, backend.ErrInputNotSet)
+	}
+	nbBits := fr.ElementModulus().BitLen()
+	for i, name := range vk.PublicInputs {
+		if name == backend.OneWire {
+			continue
+		}
+		input, ok := inputs[name]
+		if !ok {
+			return fmt.Errorf("%q: %w", name, backend.ErrInputNotSet)
+		}
+		kSum.ScalarMulAdd(circuit, kSum, &vk.G1.K[i], input, nbBits)
+	}
+
+	proof.Ar.MustBeOnCurve(circuit)
+	proof.Krs.MustBeOnCurve(circuit)
+	proof.Bs.MustBeOnCurve(circuit)
+	proof.Ar.MustBeInSubGroup(circuit)
+	proof.Krs.MustBeInSubGroup(circuit)
+	proof.Bs.MustBeInSubGroup(circuit)
+
+	res := bls377.MillerLoop(circuit,
+		[]bls377.G1Gadget{proof.Krs, proof.Ar, *kSum},
+		[]bls377.G2Gadget{vk.G2.DeltaNeg, proof.Bs, vk.G2.GammaNeg},
+	)
+	res.FinalExponentiation(circuit, &res)
+	res.MustBeEqual(circuit, &vk.E)
+
+	return nil
+}
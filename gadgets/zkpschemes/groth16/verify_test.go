@@ -0,0 +1,131 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groth16
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	backend_bls377 "github.com/consensys/gnark/backend/bls377"
+	groth16_bls377 "github.com/consensys/gnark/backend/bls377/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy/bls377/fp"
+)
+
+// innerProof returns a proof of knowledge of x such that x**3 + x + 5 == y, for y = 35
+func innerProof(t *testing.T) (*groth16_bls377.Proof, *groth16_bls377.VerifyingKey) {
+	circuit := frontend.New()
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.PUBLIC_INPUT("y")
+	circuit.MUSTBE_EQ(y, circuit.ADD(circuit.MUL(x, x, x), x, 5))
+	r1cs := backend_bls377.New(&circuit)
+
+	var pk groth16_bls377.ProvingKey
+	var vk groth16_bls377.VerifyingKey
+	groth16_bls377.Setup(&r1cs, &pk, &vk)
+
+	assignment := backend.NewAssignment()
+	assignment.Assign(backend.Secret, "x", 3)
+	assignment.Assign(backend.Public, "y", 35)
+	proof, err := groth16_bls377.Prove(&r1cs, &pk, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := groth16_bls377.Verify(proof, &vk, assignment); !ok || err != nil {
+		t.Fatal("the inner proof should be valid", err)
+	}
+	return proof, &vk
+}
+
+// verifierCircuit verifies a proof of the inner circuit, given as secret inputs, y being a public input
+func verifierCircuit(circuit *frontend.CS, vk *groth16_bls377.VerifyingKey) error {
+	proof := NewProofGadget(circuit, "proof")
+	vkGadget := NewVerifyingKeyGadget(circuit, vk)
+	inputs := map[string]*frontend.Constraint{
+		"y": circuit.PUBLIC_INPUT("y"),
+	}
+	return Verify(circuit, &proof, &vkGadget, inputs)
+}
+
+func TestVerify(t *testing.T) {
+	proof, vk := innerProof(t)
+
+	good := backend.NewAssignment()
+	AssignProof(good, "proof", proof)
+	good.Assign(backend.Public, "y", 35)
+
+	// wrong public input
+	badInput := backend.NewAssignment()
+	AssignProof(badInput, "proof", proof)
+	badInput.Assign(backend.Public, "y", 36)
+
+	// tampered proof: Ar is replaced by another point of the curve
+	tampered := *proof
+	tampered.Ar = proof.Krs
+	badProof := backend.NewAssignment()
+	AssignProof(badProof, "proof", &tampered)
+	badProof.Assign(backend.Public, "y", 35)
+
+	// the test engine
+	for _, c := range []struct {
+		name       string
+		assignment backend.Assignments
+		solved     bool
+	}{
+		{"good", good, true},
+		{"wrong input", badInput, false},
+		{"tampered proof", badProof, false},
+	} {
+		circuit := frontend.NewTestEngine(fp.ElementModulus(), c.assignment)
+		if err := verifierCircuit(&circuit, vk); err != nil {
+			t.Fatal(err)
+		}
+		_, err := circuit.Inspect(false)
+		if c.solved && err != nil {
+			t.Fatal(c.name, err)
+		}
+		if !c.solved && !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+			t.Fatal(c.name, "expected an unsatisfied constraint, got", err)
+		}
+	}
+
+	// the R1CS, solved modulo the base field of BLS12-377 (the scalar field of BW6-761)
+	circuit := frontend.New()
+	if err := verifierCircuit(&circuit, vk); err != nil {
+		t.Fatal(err)
+	}
+	r1cs := circuit.ToR1CS()
+	if err := r1cs.IsSolved(good, fp.ElementModulus()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r1cs.IsSolved(badProof, fp.ElementModulus()); !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("expected an unsatisfied constraint, got", err)
+	}
+}
+
+func TestVerifyMissingInput(t *testing.T) {
+	_, vk := innerProof(t)
+
+	circuit := frontend.New()
+	proof := NewProofGadget(&circuit, "proof")
+	vkGadget := NewVerifyingKeyGadget(&circuit, vk)
+	err := Verify(&circuit, &proof, &vkGadget, map[string]*frontend.Constraint{})
+	if !errors.Is(err, backend.ErrInputNotSet) {
+		t.Fatal("expected ErrInputNotSet, got", err)
+	}
+}
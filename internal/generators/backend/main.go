@@ -31,6 +31,12 @@ func main() {
 		if err := generator.GenerateGroth16(d); err != nil {
 			panic(err)
 		}
+		if err := os.MkdirAll(d.RootPath+"plonk", 0700); err != nil {
+			panic(err)
+		}
+		if err := generator.GeneratePLONK(d); err != nil {
+			panic(err)
+		}
 	}
 
 }
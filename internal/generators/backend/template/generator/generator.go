@@ -87,6 +87,66 @@ func GenerateGroth16(d GenerateData) error {
 		}
 	}
 
+	{
+		// batch verify
+		src := []string{
+			templates.ImportCurve,
+			zkpschemes.Groth16BatchVerify,
+		}
+		if err := bavard.Generate(d.RootPath+"groth16/batch_verify.go", src, d,
+			bavard.Package("groth16"),
+			bavard.Apache2("ConsenSys AG", 2020),
+			bavard.GeneratedBy("gnark/internal/generators"),
+		); err != nil {
+			return err
+		}
+	}
+
+	{
+		// mpc ceremony
+		src := []string{
+			templates.ImportCurve,
+			zkpschemes.Groth16MPC,
+		}
+		if err := bavard.Generate(d.RootPath+"groth16/mpc.go", src, d,
+			bavard.Package("groth16"),
+			bavard.Apache2("ConsenSys AG", 2020),
+			bavard.GeneratedBy("gnark/internal/generators"),
+		); err != nil {
+			return err
+		}
+	}
+
+	{
+		// marshal
+		src := []string{
+			templates.ImportCurve,
+			zkpschemes.Groth16Marshal,
+		}
+		if err := bavard.Generate(d.RootPath+"groth16/marshal.go", src, d,
+			bavard.Package("groth16"),
+			bavard.Apache2("ConsenSys AG", 2020),
+			bavard.GeneratedBy("gnark/internal/generators"),
+		); err != nil {
+			return err
+		}
+	}
+
+	{
+		// generate binary encoding
+		src := []string{
+			templates.ImportCurve,
+			representations.Encoding,
+		}
+		if err := bavard.Generate(d.RootPath+"encoding.go", src, d,
+			bavard.Package("backend_"+strings.ToLower(d.Curve)),
+			bavard.Apache2("ConsenSys AG", 2020),
+			bavard.GeneratedBy("gnark/internal/generators"),
+		); err != nil {
+			return err
+		}
+	}
+
 	{
 		// generate FFT
 		src := []string{
@@ -132,3 +192,57 @@ func GenerateGroth16(d GenerateData) error {
 	}
 	return nil
 }
+
+func GeneratePLONK(d GenerateData) error {
+	if !strings.HasSuffix(d.RootPath, "/") {
+		d.RootPath += "/"
+	}
+	fmt.Println()
+	fmt.Println("generating plonk backend for ", d.Curve)
+	fmt.Println()
+	if d.Curve == "GENERIC" {
+		return nil
+	}
+
+	{
+		// generate sparse_r1cs.go
+		src := []string{
+			templates.ImportCurve,
+			representations.SparseR1CS,
+		}
+		if err := bavard.Generate(d.RootPath+"sparse_r1cs.go", src, d,
+			bavard.Package("backend_"+strings.ToLower(d.Curve)),
+			bavard.Apache2("ConsenSys AG", 2020),
+			bavard.GeneratedBy("gnark/internal/generators"),
+		); err != nil {
+			return err
+		}
+	}
+
+	// plonk
+	entries := []struct {
+		file     string
+		template string
+	}{
+		{"setup.go", zkpschemes.PlonkSetup},
+		{"prove.go", zkpschemes.PlonkProve},
+		{"verify.go", zkpschemes.PlonkVerify},
+		{"utils.go", zkpschemes.PlonkUtils},
+		{"plonk_test.go", zkpschemes.PlonkTests},
+	}
+	for _, entry := range entries {
+		src := []string{
+			templates.ImportCurve,
+			entry.template,
+		}
+		if err := bavard.Generate(d.RootPath+"plonk/"+entry.file, src, d,
+			bavard.Package("plonk"),
+			bavard.Apache2("ConsenSys AG", 2020),
+			bavard.GeneratedBy("gnark/internal/generators"),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,871 @@
+package representations
+
+const Encoding = `
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils/parallel"
+	"github.com/consensys/gurvy"
+	{{ template "import_curve" . }}
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fp"
+)
+
+// sizes in bytes of the encoded field elements
+const (
+	frSize = fr.ElementLimbs * 8
+	fpSize = fp.ElementLimbs * 8
+)
+
+// flags stored in the 2 most significant bits of an encoded point
+const (
+	mMask               byte = 0b11 << 6
+	mUncompressed       byte = 0b00 << 6
+	mInfinity           byte = 0b01 << 6
+	mCompressedSmallest byte = 0b10 << 6
+	mCompressedLargest  byte = 0b11 << 6
+)
+
+// number of slice elements encoded (or decoded) in parallel before being written (or after being read)
+const chunkSize = 1 << 14
+
+// Encoder writes R1CS, keys and proofs elements in the binary format described in package backend
+type Encoder struct {
+	w          io.Writer
+	n          int64
+	compressed bool
+}
+
+// NewEncoder returns an Encoder writing to w, which compresses the points if compressed is set
+func NewEncoder(w io.Writer, compressed bool) *Encoder {
+	return &Encoder{w: w, compressed: compressed}
+}
+
+// BytesWritten returns the number of bytes written by the encoder
+func (enc *Encoder) BytesWritten() int64 {
+	return enc.n
+}
+
+// Encode writes the binary encoding of v, which must be an integer, a string,
+// an fr.Element, a point, or a slice of those
+func (enc *Encoder) Encode(v interface{}) error {
+	switch t := v.(type) {
+	case uint64:
+		return enc.writeUint64(t)
+	case int:
+		return enc.writeUint64(uint64(t))
+	case string:
+		if err := enc.writeUint32(len(t)); err != nil {
+			return err
+		}
+		return enc.write([]byte(t))
+	case []string:
+		if err := enc.writeUint32(len(t)); err != nil {
+			return err
+		}
+		for i := 0; i < len(t); i++ {
+			if err := enc.Encode(t[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *fr.Element:
+		return enc.write(t.Bytes())
+	case []fr.Element:
+		if err := enc.writeUint32(len(t)); err != nil {
+			return err
+		}
+		return enc.writeChunks(len(t), frSize, func(buf []byte, i int) {
+			copy(buf, t[i].Bytes())
+		})
+	case *curve.G1Affine:
+		buf := make([]byte, enc.g1Size())
+		putG1(buf, t, enc.compressed)
+		return enc.write(buf)
+	case []curve.G1Affine:
+		if err := enc.writeUint32(len(t)); err != nil {
+			return err
+		}
+		return enc.writeChunks(len(t), enc.g1Size(), func(buf []byte, i int) {
+			putG1(buf, &t[i], enc.compressed)
+		})
+	case *curve.G2Affine:
+		buf := make([]byte, enc.g2Size())
+		putG2(buf, t, enc.compressed)
+		return enc.write(buf)
+	case []curve.G2Affine:
+		if err := enc.writeUint32(len(t)); err != nil {
+			return err
+		}
+		return enc.writeChunks(len(t), enc.g2Size(), func(buf []byte, i int) {
+			putG2(buf, &t[i], enc.compressed)
+		})
+	default:
+		return fmt.Errorf("encoder: unsupported type %T", v)
+	}
+}
+
+func (enc *Encoder) g1Size() int {
+	if enc.compressed {
+		return fpSize
+	}
+	return 2 * fpSize
+}
+
+func (enc *Encoder) g2Size() int {
+	return 2 * enc.g1Size()
+}
+
+func (enc *Encoder) write(buf []byte) error {
+	n, err := enc.w.Write(buf)
+	enc.n += int64(n)
+	return err
+}
+
+func (enc *Encoder) writeUint64(v uint64) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return enc.write(buf[:])
+}
+
+func (enc *Encoder) writeUint32(v int) error {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(v))
+	return enc.write(buf[:])
+}
+
+// writeChunks encodes in parallel nbElements of size bytes, using put, and writes them chunk by chunk
+func (enc *Encoder) writeChunks(nbElements, size int, put func(buf []byte, i int)) error {
+	buf := make([]byte, min(nbElements, chunkSize)*size)
+	for start := 0; start < nbElements; start += chunkSize {
+		end := min(start+chunkSize, nbElements)
+		chunk := buf[:(end-start)*size]
+		parallel.Execute(end-start, func(s, e int) {
+			for i := s; i < e; i++ {
+				put(chunk[i*size:(i+1)*size], start+i)
+			}
+		})
+		if err := enc.write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decoder reads R1CS, keys and proofs elements in the binary format described in package backend
+type Decoder struct {
+	r          io.Reader
+	n          int64
+	compressed bool
+}
+
+// NewDecoder returns a Decoder reading from r, which expects compressed points if compressed is set
+func NewDecoder(r io.Reader, compressed bool) *Decoder {
+	return &Decoder{r: r, compressed: compressed}
+}
+
+// BytesRead returns the number of bytes read by the decoder
+func (dec *Decoder) BytesRead() int64 {
+	return dec.n
+}
+
+// Decode reads the binary encoding of an integer, a string, an fr.Element, a point,
+// or a slice of those, and stores it in the value pointed to by v
+func (dec *Decoder) Decode(v interface{}) error {
+	switch t := v.(type) {
+	case *uint64:
+		r, err := dec.readUint64()
+		*t = r
+		return err
+	case *int:
+		r, err := dec.readUint64()
+		if err != nil {
+			return err
+		}
+		if r > math.MaxInt64 || uint64(int(r)) != r {
+			return fmt.Errorf("%w: %d overflows an int", backend.ErrInvalidLength, r)
+		}
+		*t = int(r)
+		return nil
+	case *string:
+		n, err := dec.readUint32()
+		if err != nil {
+			return err
+		}
+		// the buffer grows as the bytes are read, n may be corrupted
+		var buf bytes.Buffer
+		read, err := io.CopyN(&buf, dec.r, int64(n))
+		dec.n += read
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		*t = buf.String()
+		return nil
+	case *[]string:
+		n, err := dec.readUint32()
+		if err != nil {
+			return err
+		}
+		*t = make([]string, 0, min(n, chunkSize))
+		for i := 0; i < n; i++ {
+			var str string
+			if err := dec.Decode(&str); err != nil {
+				return err
+			}
+			*t = append(*t, str)
+		}
+		return nil
+	case *fr.Element:
+		buf := make([]byte, frSize)
+		if err := dec.read(buf); err != nil {
+			return err
+		}
+		t.SetBytes(buf)
+		return nil
+	case *[]fr.Element:
+		n, err := dec.readUint32()
+		if err != nil {
+			return err
+		}
+		*t = make([]fr.Element, 0, min(n, chunkSize))
+		grow := func(size int) { *t = append(*t, make([]fr.Element, size-len(*t))...) }
+		return dec.readChunks(n, frSize, grow, func(buf []byte, i int) error {
+			(*t)[i].SetBytes(buf)
+			return nil
+		})
+	case *curve.G1Affine:
+		buf := make([]byte, dec.g1Size())
+		if err := dec.read(buf); err != nil {
+			return err
+		}
+		return getG1(buf, t, dec.compressed)
+	case *[]curve.G1Affine:
+		n, err := dec.readUint32()
+		if err != nil {
+			return err
+		}
+		*t = make([]curve.G1Affine, 0, min(n, chunkSize))
+		grow := func(size int) { *t = append(*t, make([]curve.G1Affine, size-len(*t))...) }
+		return dec.readChunks(n, dec.g1Size(), grow, func(buf []byte, i int) error {
+			return getG1(buf, &(*t)[i], dec.compressed)
+		})
+	case *curve.G2Affine:
+		buf := make([]byte, dec.g2Size())
+		if err := dec.read(buf); err != nil {
+			return err
+		}
+		return getG2(buf, t, dec.compressed)
+	case *[]curve.G2Affine:
+		n, err := dec.readUint32()
+		if err != nil {
+			return err
+		}
+		*t = make([]curve.G2Affine, 0, min(n, chunkSize))
+		grow := func(size int) { *t = append(*t, make([]curve.G2Affine, size-len(*t))...) }
+		return dec.readChunks(n, dec.g2Size(), grow, func(buf []byte, i int) error {
+			return getG2(buf, &(*t)[i], dec.compressed)
+		})
+	default:
+		return fmt.Errorf("decoder: unsupported type %T", v)
+	}
+}
+
+func (dec *Decoder) g1Size() int {
+	if dec.compressed {
+		return fpSize
+	}
+	return 2 * fpSize
+}
+
+func (dec *Decoder) g2Size() int {
+	return 2 * dec.g1Size()
+}
+
+func (dec *Decoder) read(buf []byte) error {
+	n, err := io.ReadFull(dec.r, buf)
+	dec.n += int64(n)
+	return err
+}
+
+func (dec *Decoder) readUint64() (uint64, error) {
+	var buf [8]byte
+	if err := dec.read(buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+func (dec *Decoder) readUint32() (int, error) {
+	var buf [4]byte
+	if err := dec.read(buf[:]); err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(buf[:])), nil
+}
+
+// readChunks reads nbElements of size bytes chunk by chunk, and decodes each chunk in parallel using get.
+// grow extends the slice of the decoded elements to the given size once the chunk is read: nbElements may be
+// corrupted, the memory allocated is bounded by the size of the input
+func (dec *Decoder) readChunks(nbElements, size int, grow func(size int), get func(buf []byte, i int) error) error {
+	buf := make([]byte, min(nbElements, chunkSize)*size)
+	for start := 0; start < nbElements; start += chunkSize {
+		end := min(start+chunkSize, nbElements)
+		chunk := buf[:(end-start)*size]
+		if err := dec.read(chunk); err != nil {
+			return err
+		}
+		grow(end)
+		var lock sync.Mutex
+		var chunkErr error
+		parallel.Execute(end-start, func(s, e int) {
+			for i := s; i < e; i++ {
+				if err := get(chunk[i*size:(i+1)*size], start+i); err != nil {
+					lock.Lock()
+					chunkErr = err
+					lock.Unlock()
+					return
+				}
+			}
+		})
+		if chunkErr != nil {
+			return chunkErr
+		}
+	}
+	return nil
+}
+
+// putG1 encodes p in buf, which must be of size fpSize (compressed) or 2*fpSize
+func putG1(buf []byte, p *curve.G1Affine, compressed bool) {
+	if p.IsInfinity() {
+		for i := 0; i < len(buf); i++ {
+			buf[i] = 0
+		}
+		buf[0] = mInfinity
+		return
+	}
+	copy(buf, p.X.Bytes())
+	if !compressed {
+		copy(buf[fpSize:], p.Y.Bytes())
+		return
+	}
+	if isLexicographicallyLargest(&p.Y) {
+		buf[0] |= mCompressedLargest
+	} else {
+		buf[0] |= mCompressedSmallest
+	}
+}
+
+// getG1 decodes p from buf, and checks its coordinates are canonical (less than p),
+// it is on the curve{{if ne .Curve "BN256"}} and in the subgroup of order r{{end}}
+func getG1(buf []byte, p *curve.G1Affine, compressed bool) error {
+	flag, err := pointFlag(buf, compressed)
+	if err != nil || flag == mInfinity {
+		p.X.SetZero()
+		p.Y.SetZero()
+		return err
+	}
+
+	if err := setFp(&p.X, buf[:fpSize], true); err != nil {
+		return err
+	}
+
+	// y² = x³ + b
+	var y2 fp.Element
+	y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &bCurve)
+
+	if !compressed {
+		if err := setFp(&p.Y, buf[fpSize:], false); err != nil {
+			return err
+		}
+		var check fp.Element
+		check.Square(&p.Y)
+		if !check.Equal(&y2) {
+			return fmt.Errorf("%w: G1 point is not on the curve", backend.ErrInvalidPoint)
+		}
+	} else {
+		if p.Y.Sqrt(&y2) == nil {
+			return fmt.Errorf("%w: no G1 point with this x coordinate", backend.ErrInvalidPoint)
+		}
+		if isLexicographicallyLargest(&p.Y) != (flag == mCompressedLargest) {
+			p.Y.Neg(&p.Y)
+		}
+	}
+	{{- if ne .Curve "BN256"}}
+
+	if !isInSubGroupG1(p) {
+		return fmt.Errorf("%w: G1 point is not in the subgroup", backend.ErrInvalidPoint)
+	}
+	{{- end}}
+	return nil
+}
+
+// putG2 encodes p in buf, which must be of size 2*fpSize (compressed) or 4*fpSize
+func putG2(buf []byte, p *curve.G2Affine, compressed bool) {
+	if p.IsInfinity() {
+		for i := 0; i < len(buf); i++ {
+			buf[i] = 0
+		}
+		buf[0] = mInfinity
+		return
+	}
+	copy(buf, p.X.A1.Bytes())
+	copy(buf[fpSize:], p.X.A0.Bytes())
+	if !compressed {
+		copy(buf[2*fpSize:], p.Y.A1.Bytes())
+		copy(buf[3*fpSize:], p.Y.A0.Bytes())
+		return
+	}
+	if isLexicographicallyLargestE2(&p.Y.A0, &p.Y.A1) {
+		buf[0] |= mCompressedLargest
+	} else {
+		buf[0] |= mCompressedSmallest
+	}
+}
+
+// getG2 decodes p from buf, and checks its coordinates are canonical (less than p),
+// it is on the twist and in the subgroup of order r
+func getG2(buf []byte, p *curve.G2Affine, compressed bool) error {
+	flag, err := pointFlag(buf, compressed)
+	if err != nil || flag == mInfinity {
+		p.X.SetZero()
+		p.Y.SetZero()
+		return err
+	}
+
+	if err := setFp(&p.X.A1, buf[:fpSize], true); err != nil {
+		return err
+	}
+	if err := setFp(&p.X.A0, buf[fpSize:2*fpSize], false); err != nil {
+		return err
+	}
+
+	// y² = x³ + b'
+	y2 := p.X
+	y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, &bTwist)
+
+	if !compressed {
+		if err := setFp(&p.Y.A1, buf[2*fpSize:3*fpSize], false); err != nil {
+			return err
+		}
+		if err := setFp(&p.Y.A0, buf[3*fpSize:], false); err != nil {
+			return err
+		}
+		check := p.Y
+		check.Square(&p.Y)
+		if !check.Equal(&y2) {
+			return fmt.Errorf("%w: G2 point is not on the twist", backend.ErrInvalidPoint)
+		}
+	} else {
+		if !sqrtE2(&p.Y.A0, &p.Y.A1, &y2.A0, &y2.A1) {
+			return fmt.Errorf("%w: no G2 point with this x coordinate", backend.ErrInvalidPoint)
+		}
+		check := p.Y
+		check.Square(&p.Y)
+		if !check.Equal(&y2) {
+			return fmt.Errorf("%w: no G2 point with this x coordinate", backend.ErrInvalidPoint)
+		}
+		if isLexicographicallyLargestE2(&p.Y.A0, &p.Y.A1) != (flag == mCompressedLargest) {
+			p.Y.Neg(&p.Y)
+		}
+	}
+
+	if !isInSubGroupG2(p) {
+		return fmt.Errorf("%w: G2 point is not in the subgroup", backend.ErrInvalidPoint)
+	}
+	return nil
+}
+
+// pointFlag returns the flag of the encoded point in buf, and checks it is consistent with the compression mode
+func pointFlag(buf []byte, compressed bool) (byte, error) {
+	flag := buf[0] & mMask
+	switch flag {
+	case mInfinity:
+		if buf[0] != mInfinity {
+			return flag, fmt.Errorf("%w: non zero point at infinity", backend.ErrInvalidPoint)
+		}
+		for i := 1; i < len(buf); i++ {
+			if buf[i] != 0 {
+				return flag, fmt.Errorf("%w: non zero point at infinity", backend.ErrInvalidPoint)
+			}
+		}
+	case mUncompressed:
+		if compressed {
+			return flag, fmt.Errorf("%w: expected a compressed point", backend.ErrInvalidPoint)
+		}
+	default:
+		if !compressed {
+			return flag, fmt.Errorf("%w: expected an uncompressed point", backend.ErrInvalidPoint)
+		}
+	}
+	return flag, nil
+}
+
+// setFp sets z from its big-endian encoding in buf, ignoring the flags if masked is set.
+// It fails if the encoded value is not less than p, such that each point has a single encoding
+func setFp(z *fp.Element, buf []byte, masked bool) error {
+	var tmp [fpSize]byte
+	copy(tmp[:], buf)
+	if masked {
+		tmp[0] &^= mMask
+	}
+	z.SetBytes(tmp[:])
+	if !bytes.Equal(z.Bytes(), tmp[:]) {
+		return fmt.Errorf("%w: non canonical coordinate", backend.ErrInvalidPoint)
+	}
+	return nil
+}
+
+// minusOne is r-1 in regular form, [r-1]p = -p if and only if p is in the subgroup of order r
+var minusOne = func() fr.Element {
+	var res fr.Element
+	res.SetOne().Neg(&res)
+	return res.ToRegular()
+}()
+{{if ne .Curve "BN256"}}
+// isInSubGroupG1 returns true if p, on the curve, is in the subgroup of order r
+func isInSubGroupG1(p *curve.G1Affine) bool {
+	var pJac, res, neg curve.G1Jac
+	p.ToJacobian(&pJac)
+	res.ScalarMul(curve.{{.Curve}}(), &pJac, minusOne)
+	neg.Neg(&pJac)
+	return res.Equal(&neg)
+}
+{{end}}
+// isInSubGroupG2 returns true if p, on the twist, is in the subgroup of order r
+func isInSubGroupG2(p *curve.G2Affine) bool {
+	var pJac, res, neg curve.G2Jac
+	p.ToJacobian(&pJac)
+	res.ScalarMul(curve.{{.Curve}}(), &pJac, minusOne)
+	neg.Neg(&pJac)
+	return res.Equal(&neg)
+}
+
+// b coefficients of the curve y² = x³ + b and of the twist y² = x³ + b' on which G2 is defined,
+// computed from the generators
+var (
+	bCurve = curveCoefficient()
+	bTwist = twistCoefficient().X
+)
+
+func curveCoefficient() fp.Element {
+	var one fr.Element
+	one.SetOne()
+	var g curve.G1Jac
+	var gAff curve.G1Affine
+	g.ScalarMulByGen(curve.{{.Curve}}(), one.ToRegular()).ToAffineFromJac(&gAff)
+
+	var res, x3 fp.Element
+	x3.Square(&gAff.X).Mul(&x3, &gAff.X)
+	res.Square(&gAff.Y).Sub(&res, &x3)
+	return res
+}
+
+// twistCoefficient returns a G2 point whose X coordinate is b'
+func twistCoefficient() curve.G2Affine {
+	var one fr.Element
+	one.SetOne()
+	var g curve.G2Jac
+	var res curve.G2Affine
+	g.ScalarMulByGen(curve.{{.Curve}}(), one.ToRegular()).ToAffineFromJac(&res)
+
+	x3 := res.X
+	x3.Square(&res.X).Mul(&x3, &res.X)
+	res.X.Square(&res.Y).Sub(&res.X, &x3)
+	return res
+}
+
+// halfP is (p-1)/2 in regular form
+var halfP = func() fp.Element {
+	var res fp.Element
+	res.SetOne().Neg(&res)
+	res = res.ToRegular()
+	for i := 0; i < fp.ElementLimbs-1; i++ {
+		res[i] = (res[i] >> 1) | (res[i+1] << 63)
+	}
+	res[fp.ElementLimbs-1] >>= 1
+	return res
+}()
+
+// isLexicographicallyLargest returns true if y > (p-1)/2, that is if y > -y
+func isLexicographicallyLargest(y *fp.Element) bool {
+	regular := y.ToRegular()
+	for i := fp.ElementLimbs - 1; i >= 0; i-- {
+		if regular[i] != halfP[i] {
+			return regular[i] > halfP[i]
+		}
+	}
+	return false
+}
+
+// isLexicographicallyLargestE2 compares a1 if it isn't 0, a0 otherwise
+func isLexicographicallyLargestE2(a0, a1 *fp.Element) bool {
+	if a1.IsZero() {
+		return isLexicographicallyLargest(a0)
+	}
+	return isLexicographicallyLargest(a1)
+}
+
+// sqrtE2 sets c0 + c1*u to a square root of a0 + a1*u, u² being the non residue of the quadratic extension.
+// It returns false if the norm of a0 + a1*u isn't a square, in which case c0 and c1 are undefined.
+func sqrtE2(c0, c1, a0, a1 *fp.Element) bool {
+	if a1.IsZero() {
+		// either a0 or a0/u² is a square
+		if c0.Sqrt(a0) != nil {
+			c1.SetZero()
+			return true
+		}
+		var t fp.Element
+		curve.MulByNonResidueInv(&t, a0)
+		c0.SetZero()
+		return c1.Sqrt(&t) != nil
+	}
+
+	// n = √(a0² - u²*a1²)
+	var n, t, half fp.Element
+	n.Square(a0)
+	t.Square(a1)
+	curve.MulByNonResidue(&t, &t)
+	n.Sub(&n, &t)
+	if n.Sqrt(&n) == nil {
+		return false
+	}
+
+	// c0 = √((a0 ± n) / 2)
+	half.SetUint64(2).Inverse(&half)
+	t.Add(a0, &n).Mul(&t, &half)
+	if c0.Sqrt(&t) == nil {
+		t.Sub(a0, &n).Mul(&t, &half)
+		if c0.Sqrt(&t) == nil {
+			return false
+		}
+	}
+
+	// c1 = a1 / (2 * c0)
+	t.Double(c0).Inverse(&t)
+	c1.Mul(a1, &t)
+	return true
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// WriteTo writes the binary encoding of the R1CS to w: the wires, the tags sorted by wire ID,
+// the constraints and their call sites. The number of tags, constraints and terms are encoded as integers,
+// and a term as its wire ID (integer) followed by its coefficient (fr)
+func (r1cs *R1CS) WriteTo(w io.Writer) (int64, error) {
+	n, err := backend.WriteHeader(w, backend.BinaryHeader{
+		Version: backend.BinaryVersion,
+		CurveID: gurvy.{{.Curve}},
+		Object:  backend.BinaryR1CS,
+	})
+	if err != nil {
+		return n, err
+	}
+	enc := NewEncoder(w, false)
+
+	// tags are sorted by wire ID, so that the encoding is deterministic
+	tagged := make([]int, 0, len(r1cs.WireTags))
+	for wireID := range r1cs.WireTags {
+		tagged = append(tagged, wireID)
+	}
+	sort.Ints(tagged)
+
+	toEncode := []interface{}{
+		r1cs.NbWires,
+		r1cs.NbPublicWires,
+		r1cs.NbPrivateWires,
+		r1cs.PrivateWires,
+		r1cs.PublicWires,
+		len(tagged),
+	}
+	for _, wireID := range tagged {
+		toEncode = append(toEncode, wireID, r1cs.WireTags[wireID])
+	}
+	toEncode = append(toEncode,
+		r1cs.NbConstraints,
+		r1cs.NbCOConstraints,
+		len(r1cs.Constraints),
+	)
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return n + enc.BytesWritten(), err
+		}
+	}
+
+	for i := 0; i < len(r1cs.Constraints); i++ {
+		r1c := &r1cs.Constraints[i]
+		if err := enc.Encode(uint64(r1c.Solver)); err != nil {
+			return n + enc.BytesWritten(), err
+		}
+		if r1c.Solver == frontend.Hint {
+			if err := enc.Encode(uint64(r1c.HintID)); err != nil {
+				return n + enc.BytesWritten(), err
+			}
+		}
+		for _, l := range []LinearExpression{r1c.L, r1c.R, r1c.O} {
+			if err := enc.Encode(len(l)); err != nil {
+				return n + enc.BytesWritten(), err
+			}
+			for j := 0; j < len(l); j++ {
+				if err := enc.Encode(uint64(l[j].ID)); err != nil {
+					return n + enc.BytesWritten(), err
+				}
+				if err := enc.Encode(&l[j].Coeff); err != nil {
+					return n + enc.BytesWritten(), err
+				}
+			}
+		}
+	}
+	if err := enc.Encode(r1cs.CallSites); err != nil {
+		return n + enc.BytesWritten(), err
+	}
+
+	return n + enc.BytesWritten(), nil
+}
+
+// ReadFrom reads the binary encoding of a R1CS from r
+func (r1cs *R1CS) ReadFrom(r io.Reader) (int64, error) {
+	n, err := r1cs.readFrom(r)
+	if errors.Is(err, backend.ErrInvalidLength) {
+		// the counts of the R1CS are lengths
+		err = fmt.Errorf("%w: %v", backend.ErrInvalidR1CS, err)
+	}
+	return n, err
+}
+
+func (r1cs *R1CS) readFrom(r io.Reader) (int64, error) {
+	header, n, err := backend.ReadHeader(r, gurvy.{{.Curve}}, backend.BinaryR1CS)
+	if err != nil {
+		return n, err
+	}
+	dec := NewDecoder(r, false)
+
+	var nbTags, nbConstraints int
+	toDecode := []interface{}{
+		&r1cs.NbWires,
+		&r1cs.NbPublicWires,
+		&r1cs.NbPrivateWires,
+		&r1cs.PrivateWires,
+		&r1cs.PublicWires,
+		&nbTags,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return n + dec.BytesRead(), err
+		}
+	}
+
+	if r1cs.NbPublicWires != len(r1cs.PublicWires) || r1cs.NbPrivateWires != len(r1cs.PrivateWires) ||
+		r1cs.NbPublicWires+r1cs.NbPrivateWires > r1cs.NbWires {
+		return n + dec.BytesRead(), fmt.Errorf("%w: inconsistent number of wires", backend.ErrInvalidR1CS)
+	}
+
+	r1cs.WireTags = make(map[int][]string, min(nbTags, chunkSize))
+	for i := 0; i < nbTags; i++ {
+		var wireID int
+		var tags []string
+		if err := dec.Decode(&wireID); err != nil {
+			return n + dec.BytesRead(), err
+		}
+		if wireID >= r1cs.NbWires {
+			return n + dec.BytesRead(), fmt.Errorf("%w: tagged wire %d out of range", backend.ErrInvalidR1CS, wireID)
+		}
+		if err := dec.Decode(&tags); err != nil {
+			return n + dec.BytesRead(), err
+		}
+		r1cs.WireTags[wireID] = tags
+	}
+
+	toDecode = []interface{}{
+		&r1cs.NbConstraints,
+		&r1cs.NbCOConstraints,
+		&nbConstraints,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return n + dec.BytesRead(), err
+		}
+	}
+
+	if r1cs.NbConstraints != nbConstraints || r1cs.NbCOConstraints > nbConstraints {
+		return n + dec.BytesRead(), fmt.Errorf("%w: inconsistent number of constraints", backend.ErrInvalidR1CS)
+	}
+
+	// the slices grow as the constraints are read, the counts may be corrupted
+	r1cs.Constraints = make([]R1C, 0, min(nbConstraints, chunkSize))
+	for i := 0; i < nbConstraints; i++ {
+		r1cs.Constraints = append(r1cs.Constraints, R1C{})
+		r1c := &r1cs.Constraints[i]
+		var solver uint64
+		if err := dec.Decode(&solver); err != nil {
+			return n + dec.BytesRead(), err
+		}
+		if solver > uint64(frontend.Hint) {
+			return n + dec.BytesRead(), fmt.Errorf("%w: unknown solving method %d", backend.ErrInvalidR1CS, solver)
+		}
+		r1c.Solver = frontend.SolvingMethod(solver)
+		if r1c.Solver == frontend.Hint {
+			// the hints were added in version 3
+			if header.Version < 3 {
+				return n + dec.BytesRead(), fmt.Errorf("%w: hint solver in a version %d R1CS", backend.ErrInvalidR1CS, header.Version)
+			}
+			var hintID uint64
+			if err := dec.Decode(&hintID); err != nil {
+				return n + dec.BytesRead(), err
+			}
+			r1c.HintID = frontend.HintID(hintID)
+		}
+		for _, l := range []*LinearExpression{&r1c.L, &r1c.R, &r1c.O} {
+			var nbTerms int
+			if err := dec.Decode(&nbTerms); err != nil {
+				return n + dec.BytesRead(), err
+			}
+			*l = make(LinearExpression, 0, min(nbTerms, chunkSize))
+			for j := 0; j < nbTerms; j++ {
+				var id uint64
+				if err := dec.Decode(&id); err != nil {
+					return n + dec.BytesRead(), err
+				}
+				if id >= uint64(r1cs.NbWires) {
+					return n + dec.BytesRead(), fmt.Errorf("%w: wire %d out of range", backend.ErrInvalidR1CS, id)
+				}
+				*l = append(*l, Term{ID: int64(id)})
+				if err := dec.Decode(&(*l)[j].Coeff); err != nil {
+					return n + dec.BytesRead(), err
+				}
+			}
+		}
+	}
+	// the call sites were added in version 2
+	r1cs.CallSites = nil
+	if header.Version >= 2 {
+		if err := dec.Decode(&r1cs.CallSites); err != nil {
+			return n + dec.BytesRead(), err
+		}
+		if len(r1cs.CallSites) > nbConstraints {
+			return n + dec.BytesRead(), fmt.Errorf("%w: more call sites than constraints", backend.ErrInvalidR1CS)
+		}
+		if len(r1cs.CallSites) == 0 {
+			r1cs.CallSites = nil
+		}
+	}
+
+	return n + dec.BytesRead(), nil
+}
+`
@@ -0,0 +1,148 @@
+package zkpschemes
+
+const Groth16BatchVerify = `
+
+import (
+	"errors"
+
+	{{ template "import_curve" . }}
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/utils/parallel"
+)
+
+var (
+	// ErrBatchSize is returned by BatchVerify when the number of proofs and the number of inputs differ
+	ErrBatchSize = errors.New("the number of proofs and of inputs must be equal")
+)
+
+// BatchVerify verifies a batch of proofs against the same verifying key
+//
+// the verification equations are combined with random coefficients rᵢ:
+// Π e(rᵢ⋅[Ar]1, [Bs]2) ⋅ e(Σrᵢ⋅[Krs]1, -[δ]2) ⋅ e(Σrᵢ⋅Σx.[Kvk(t)]1, -[γ]2) == e(α, β)^Σrᵢ
+// which costs len(proofs)+2 Miller loops and a single final exponentiation.
+//
+// if the batch is rejected, it is bisected to find the first invalid proof;
+// BatchVerify returns its index (or -1 if all the proofs are valid)
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, inputs []backend.Assignments) (bool, int, error) {
+	if len(proofs) != len(inputs) {
+		return false, -1, ErrBatchSize
+	}
+
+	kInputs := make([][]fr.Element, len(inputs))
+	for i := 0; i < len(inputs); i++ {
+		var err error
+		if kInputs[i], err = parsePublicInput(vk.PublicInputs, inputs[i]); err != nil {
+			return false, i, err
+		}
+	}
+
+	index := bisect(proofs, vk, kInputs, 0, len(proofs))
+	return index == -1, index, nil
+}
+
+// bisect returns the index of the first invalid proof in proofs[start:end], or -1 if they are all valid
+func bisect(proofs []*Proof, vk *VerifyingKey, kInputs [][]fr.Element, start, end int) int {
+	if start == end || batchVerify(proofs[start:end], vk, kInputs[start:end]) {
+		return -1
+	}
+	if end-start == 1 {
+		return start
+	}
+	middle := (start + end) / 2
+	if index := bisect(proofs, vk, kInputs, start, middle); index != -1 {
+		return index
+	}
+	return bisect(proofs, vk, kInputs, middle, end)
+}
+
+// batchVerify checks the random linear combination of the verification equations of the proofs
+// kInputs are the public inputs, in regular form
+func batchVerify(proofs []*Proof, vk *VerifyingKey, kInputs [][]fr.Element) bool {
+
+	c := {{- if eq .Curve "GENERIC"}}curve.GetCurve(){{- else}}curve.{{.Curve}}(){{- end}}
+
+	// random coefficients, in Montgomery and regular form
+	r := make([]fr.Element, len(proofs))
+	rRegular := make([]fr.Element, len(proofs))
+	var rSum fr.Element
+	for i := 0; i < len(proofs); i++ {
+		r[i].SetRandom()
+		rRegular[i] = r[i].ToRegular()
+		rSum.Add(&rSum, &r[i])
+	}
+
+	// e(rᵢ⋅[Ar]1, [Bs]2)
+	eArBs := make([]curve.PairingResult, len(proofs))
+	parallel.Execute(len(proofs), func(start, end int) {
+		var ar curve.G1Jac
+		var arAff curve.G1Affine
+		for i := start; i < end; i++ {
+			proofs[i].Ar.ToJacobian(&ar)
+			ar.ScalarMul(c, &ar, rRegular[i]).ToAffineFromJac(&arAff)
+			c.MillerLoop(arAff, proofs[i].Bs, &eArBs[i])
+		}
+	})
+
+	// e(Σrᵢ⋅[Krs]1, -[δ]2)
+	var krsSum curve.G1Jac
+	var krsSumAff curve.G1Affine
+	var eKrsδ curve.PairingResult
+	krs := make([]curve.G1Affine, len(proofs))
+	for i := 0; i < len(proofs); i++ {
+		krs[i] = proofs[i].Krs
+	}
+	<-krsSum.MultiExp(c, krs, rRegular)
+	krsSum.ToAffineFromJac(&krsSumAff)
+	c.MillerLoop(krsSumAff, vk.G2.DeltaNeg, &eKrsδ)
+
+	// e(Σrᵢ⋅Σx.[Kvk(t)]1, -[γ]2) = e(Σ(Σrᵢ⋅xᵢ).[Kvk(t)]1, -[γ]2)
+	var kSum curve.G1Jac
+	var kSumAff curve.G1Affine
+	var eKvkγ curve.PairingResult
+	scalars := make([]fr.Element, len(vk.G1.K))
+	var x fr.Element
+	for i := 0; i < len(proofs); i++ {
+		for j := 0; j < len(scalars); j++ {
+			x.Set(&kInputs[i][j]).ToMont().MulAssign(&r[i])
+			scalars[j].Add(&scalars[j], &x)
+		}
+	}
+	for j := 0; j < len(scalars); j++ {
+		scalars[j].FromMont()
+	}
+	<-kSum.MultiExp(c, vk.G1.K, scalars)
+	kSum.ToAffineFromJac(&kSumAff)
+	c.MillerLoop(kSumAff, vk.G2.GammaNeg, &eKvkγ)
+
+	// e(α, β)^Σrᵢ
+	expected := exp(&vk.E, rSum.ToRegular())
+
+	eArBs = append(eArBs, eKvkγ)
+	right := c.FinalExponentiation(&eKrsδ, pointers(eArBs)...)
+	return expected.Equal(&right)
+}
+
+// exp returns x^s, s in regular form
+func exp(x *curve.PairingResult, s fr.Element) curve.PairingResult {
+	var res curve.PairingResult
+	res.SetOne()
+	for i := len(s) - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			res.Square(&res)
+			if (s[i]>>uint(j))&1 == 1 {
+				res.Mul(&res, x)
+			}
+		}
+	}
+	return res
+}
+
+func pointers(s []curve.PairingResult) []*curve.PairingResult {
+	res := make([]*curve.PairingResult, len(s))
+	for i := 0; i < len(s); i++ {
+		res[i] = &s[i]
+	}
+	return res
+}
+
+`
@@ -308,6 +308,10 @@ func asyncExpTable(scale, w fr.Element, table []fr.Element, wg *sync.WaitGroup)
 }
 
 func precomputeExpTableChunk(scale, w fr.Element, power uint64, table []fr.Element) {
+	if len(table) == 0 {
+		// single constraint circuit, the table is reduced to scale
+		return
+	}
 	table[0].Exp(w, power)
 	table[0].MulAssign(&scale)
 	for i := 1; i < len(table); i++ {
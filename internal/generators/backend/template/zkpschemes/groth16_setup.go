@@ -30,16 +30,20 @@ type VerifyingKey struct {
 	// e(α, β)
 	E curve.PairingResult
 
-	// -[γ]2, -[δ]2
+	// [β]2, -[γ]2, -[δ]2
 	// note: storing GammaNeg and DeltaNeg instead of Gamma and Delta
 	// see proof.Verify() for more details
 	G2 struct {
+		Beta               curve.G2Affine
 		GammaNeg, DeltaNeg curve.G2Affine
 	}
 
-	// [Kvk]1
+	// [α]1, [Kvk]1
+	// note: [α]1 and [β]2 are not used by Verify (which uses E), but are needed
+	// by verifiers that can't deal with elements of the pairing target group (e.g. smart contracts)
 	G1 struct {
-		K []curve.G1Affine // The indexes correspond to the public wires
+		Alpha curve.G1Affine
+		K     []curve.G1Affine // The indexes correspond to the public wires
 	}
 
 	PublicInputs []string // maps the name of the public input
@@ -149,6 +153,9 @@ func setupToxicWaste(pk *ProvingKey, vk *VerifyingKey, tw toxicWaste) {
 	vkG2JacGammaNeg.Neg(&vkG2JacGammaNeg).
 		ToAffineFromJac(&vk.G2.GammaNeg)
 
+	// sets vk: [α]1, [β]2, e(α, β)
+	vk.G1.Alpha = pk.G1.Alpha
+	vk.G2.Beta = pk.G2.Beta
 	vk.E = c.FinalExponentiation(c.MillerLoop(pk.G1.Alpha, pk.G2.Beta, &vk.E))
 
 }
@@ -0,0 +1,164 @@
+package zkpschemes
+
+const PlonkVerify = `
+
+import (
+	{{ template "import_curve" . }}
+
+	"github.com/consensys/gnark/backend"
+)
+
+// Verify verifies a proof
+func Verify(proof *Proof, vk *VerifyingKey, inputs backend.Assignments) (bool, error) {
+
+	c := curve.{{.Curve}}()
+
+	publicInputs, err := parsePublicInput(vk.PublicInputs, inputs)
+	if err != nil {
+		return false, err
+	}
+
+	// recompute the challenges
+	fs := newTranscript()
+	fs.appendScalars(toPointers(publicInputs)...)
+	fs.appendPoints(&proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
+	beta := fs.challenge()
+	gamma := fs.challenge()
+	fs.appendPoints(&proof.Z)
+	alpha := fs.challenge()
+	fs.appendPoints(&proof.T)
+	zeta := fs.challenge()
+	fs.appendScalars(toPointers(proof.Evaluations[:])...)
+	fs.appendScalars(&proof.ZShifted)
+	v := fs.challenge()
+	fs.appendPoints(&proof.W, &proof.WShifted)
+	u := fs.challenge()
+
+	// ζⁿ - 1
+	var one, zh fr.Element
+	one.SetOne()
+	zh.Exp(zeta, uint64(vk.Size)).Sub(&zh, &one)
+
+	// Lᵢ(ζ) = ωⁱ⋅(ζⁿ - 1) / (n⋅(ζ - ωⁱ))
+	var n, wi, lagrange, den, pi, l0 fr.Element
+	n.SetUint64(uint64(vk.Size))
+	wi.SetOne()
+	for i := 0; i < len(publicInputs); i++ {
+		den.Sub(&zeta, &wi).MulAssign(&n)
+		lagrange.Div(&zh, &den).MulAssign(&wi)
+		if i == 0 {
+			l0 = lagrange
+		}
+		lagrange.MulAssign(&publicInputs[i])
+		pi.Add(&pi, &lagrange)
+		wi.MulAssign(&vk.Generator)
+	}
+	if len(publicInputs) == 0 {
+		den.Sub(&zeta, &one).MulAssign(&n)
+		l0.Div(&zh, &den)
+	}
+
+	e := &proof.Evaluations
+
+	// gate: qL⋅a + qR⋅b + qM⋅a⋅b + qO⋅c + qK + PI
+	var gate, tmp fr.Element
+	gate.Mul(&e[idxQl], &e[idxL])
+	tmp.Mul(&e[idxQr], &e[idxR])
+	gate.Add(&gate, &tmp)
+	tmp.Mul(&e[idxL], &e[idxR]).MulAssign(&e[idxQm])
+	gate.Add(&gate, &tmp)
+	tmp.Mul(&e[idxQo], &e[idxO])
+	gate.Add(&gate, &tmp).Add(&gate, &e[idxQk]).Add(&gate, &pi)
+
+	// permutation: (a+βζ+γ)(b+βk1ζ+γ)(c+βk2ζ+γ)Z(ζ) - (a+βS1+γ)(b+βS2+γ)(c+βS3+γ)Z(ζω)
+	var f, g fr.Element
+	f.Mul(&beta, &zeta).Add(&f, &gamma).Add(&f, &e[idxL])
+	tmp.Mul(&beta, &zeta).MulAssign(&vk.Shifter[0]).Add(&tmp, &gamma).Add(&tmp, &e[idxR])
+	f.MulAssign(&tmp)
+	tmp.Mul(&beta, &zeta).MulAssign(&vk.Shifter[1]).Add(&tmp, &gamma).Add(&tmp, &e[idxO])
+	f.MulAssign(&tmp).MulAssign(&e[idxZ])
+
+	g.Mul(&beta, &e[idxS1]).Add(&g, &gamma).Add(&g, &e[idxL])
+	tmp.Mul(&beta, &e[idxS2]).Add(&tmp, &gamma).Add(&tmp, &e[idxR])
+	g.MulAssign(&tmp)
+	tmp.Mul(&beta, &e[idxS3]).Add(&tmp, &gamma).Add(&tmp, &e[idxO])
+	g.MulAssign(&tmp).MulAssign(&proof.ZShifted)
+
+	f.Sub(&f, &g).MulAssign(&alpha)
+
+	// α²⋅(Z(ζ) - 1)⋅L0(ζ)
+	var alphaSquare fr.Element
+	alphaSquare.Square(&alpha)
+	tmp.Sub(&e[idxZ], &one).MulAssign(&l0).MulAssign(&alphaSquare)
+
+	// t(ζ)⋅(ζⁿ - 1)
+	var lhs, rhs fr.Element
+	lhs.Add(&gate, &f).Add(&lhs, &tmp)
+	rhs.Mul(&e[idxT], &zh)
+	if !lhs.Equal(&rhs) {
+		return false, nil
+	}
+
+	// check the openings (KZG), batched with u:
+	// e(F - [y]1 + ζ⋅W + u⋅(Z - [Z(ζω)]1 + ζω⋅Wω), [1]2) = e(W + u⋅Wω, [τ]2)
+	// where F = Σ vⁱ⋅Cᵢ and y = Σ vⁱ⋅ēᵢ
+	commitments := []curve.G1Affine{
+		proof.LRO[0], proof.LRO[1], proof.LRO[2],
+		vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk,
+		vk.S1, vk.S2, vk.S3,
+		proof.Z, proof.T,
+	}
+	var zetaShifted fr.Element
+	zetaShifted.Mul(&zeta, &vk.Generator)
+
+	points := make([]curve.G1Affine, 0, len(commitments)+4)
+	scalars := make([]fr.Element, 0, len(commitments)+4)
+	var vi, y fr.Element
+	vi.SetOne()
+	for i := 0; i < len(commitments); i++ {
+		points = append(points, commitments[i])
+		scalars = append(scalars, vi)
+		tmp.Mul(&vi, &e[i])
+		y.Add(&y, &tmp)
+		vi.MulAssign(&v)
+	}
+	// u⋅Z
+	scalars[idxZ].Add(&scalars[idxZ], &u)
+	// -(y + u⋅Z(ζω))⋅[1]1
+	tmp.Mul(&u, &proof.ZShifted).Add(&tmp, &y).Neg(&tmp)
+	points = append(points, vk.G1)
+	scalars = append(scalars, tmp)
+	// ζ⋅W + uζω⋅Wω
+	points = append(points, proof.W, proof.WShifted)
+	tmp.Mul(&u, &zetaShifted)
+	scalars = append(scalars, zeta, tmp)
+
+	for i := 0; i < len(scalars); i++ {
+		scalars[i].FromMont()
+	}
+
+	var left curve.G1Jac
+	var leftAffine curve.G1Affine
+	<-left.MultiExp(c, points, scalars)
+	left.ToAffineFromJac(&leftAffine)
+
+	var right curve.G1Jac
+	var rightAffine curve.G1Affine
+	var uRegular fr.Element
+	uRegular.Set(&u).FromMont()
+	right.ScalarMul(c, proof.WShifted.ToJacobian(&curve.G1Jac{}), uRegular)
+	right.AddMixed(&proof.W)
+	right.ToAffineFromJac(&rightAffine)
+	rightAffine.Neg(&rightAffine)
+
+	var eLeft, eRight curve.PairingResult
+	c.MillerLoop(leftAffine, vk.G2[0], &eLeft)
+	c.MillerLoop(rightAffine, vk.G2[1], &eRight)
+
+	var expected curve.PairingResult
+	expected.SetOne()
+	result := c.FinalExponentiation(&eLeft, &eRight)
+
+	return result.Equal(&expected), nil
+}
+`
@@ -0,0 +1,86 @@
+package zkpschemes
+
+const PlonkTests = `
+
+import (
+	{{ template "import_curve" . }}
+
+	{{ template "import_backend" . }}
+
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/generators/testcircuits/circuits"
+)
+
+func TestCircuits(t *testing.T) {
+	// the SRS is universal, one is enough for all the circuits
+	sprs := make(map[string]backend_{{toLower .Curve}}.SparseR1CS)
+	size := 0
+	for name, circuit := range circuits.Circuits {
+		spr := backend_{{toLower .Curve}}.CastSparseR1CS(circuit.R1CS.ToSparseR1CS())
+		if SRSSize(&spr) > size {
+			size = SRSSize(&spr)
+		}
+		sprs[name] = spr
+	}
+	srs := NewSRS(size)
+
+	for name, circuit := range circuits.Circuits {
+		t.Log(curve.ID.String(), " -- ", name)
+
+		spr := sprs[name]
+
+		var pk ProvingKey
+		var vk VerifyingKey
+		if err := Setup(&spr, srs, &pk, &vk); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Prove(&spr, &pk, circuit.Bad); err == nil {
+			t.Fatal(name, ": proving with bad solution should output an error")
+		}
+
+		proof, err := Prove(&spr, &pk, circuit.Good)
+		if err != nil {
+			t.Fatal(name, ": proving with good solution should not output an error", err)
+		}
+		if !verify(t, proof, &vk, circuit.Good) {
+			t.Fatal(name, ": verifying a correct proof with correct public inputs should return true")
+		}
+
+		// tampered proof
+		tampered := *proof
+		tampered.Evaluations[idxL].SetRandom()
+		if verify(t, &tampered, &vk, circuit.Good) {
+			t.Fatal(name, ": verifying a tampered proof should return false")
+		}
+		tampered = *proof
+		tampered.W = proof.WShifted
+		if verify(t, &tampered, &vk, circuit.Good) {
+			t.Fatal(name, ": verifying a tampered proof should return false")
+		}
+
+		// wrong public inputs
+		for k, v := range circuit.Good.DiscardSecrets() {
+			wrong := circuit.Good.DiscardSecrets()
+			var value big.Int
+			value.Add(&v.Value, big.NewInt(1))
+			wrong[k] = backend.Assignment{Value: value, IsPublic: true}
+			if verify(t, proof, &vk, wrong) {
+				t.Fatal(name, ": verifying a correct proof with wrong public inputs should return false")
+			}
+		}
+	}
+}
+
+func verify(t *testing.T, proof *Proof, vk *VerifyingKey, solution backend.Assignments) bool {
+	t.Helper()
+	ok, err := Verify(proof, vk, solution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+`
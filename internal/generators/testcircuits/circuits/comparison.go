@@ -0,0 +1,160 @@
+package circuits
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+func isZero() {
+
+	circuit := frontend.New()
+
+	x0 := circuit.SECRET_INPUT("x0")
+	x1 := circuit.SECRET_INPUT("x1")
+	y0 := circuit.PUBLIC_INPUT("y0")
+	y1 := circuit.PUBLIC_INPUT("y1")
+
+	circuit.MUSTBE_EQ(circuit.IS_ZERO(x0), y0)
+	circuit.MUSTBE_EQ(circuit.IS_ZERO(x1), y1)
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x0", 0)
+	good.Assign(backend.Secret, "x1", 42)
+	good.Assign(backend.Public, "y0", 1)
+	good.Assign(backend.Public, "y1", 0)
+
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x0", 0)
+	bad.Assign(backend.Secret, "x1", 42)
+	bad.Assign(backend.Public, "y0", 0)
+	bad.Assign(backend.Public, "y1", 1)
+
+	r1cs := circuit.ToR1CS()
+	addEntry("is_zero", r1cs, good, bad)
+}
+
+func isEqual() {
+
+	circuit := frontend.New()
+
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.SECRET_INPUT("y")
+	z0 := circuit.PUBLIC_INPUT("z0")
+	z1 := circuit.PUBLIC_INPUT("z1")
+
+	circuit.MUSTBE_EQ(circuit.IS_EQUAL(x, y), z0)
+	circuit.MUSTBE_EQ(circuit.IS_EQUAL(x, 7), z1)
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x", 7)
+	good.Assign(backend.Secret, "y", 8)
+	good.Assign(backend.Public, "z0", 0)
+	good.Assign(backend.Public, "z1", 1)
+
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 7)
+	bad.Assign(backend.Secret, "y", 8)
+	bad.Assign(backend.Public, "z0", 1)
+	bad.Assign(backend.Public, "z1", 1)
+
+	r1cs := circuit.ToR1CS()
+	addEntry("is_equal", r1cs, good, bad)
+}
+
+func isLess() {
+
+	circuit := frontend.New()
+
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.SECRET_INPUT("y")
+	z0 := circuit.PUBLIC_INPUT("z0")
+	z1 := circuit.PUBLIC_INPUT("z1")
+
+	circuit.MUSTBE_IN_RANGE(x, 8)
+	circuit.MUSTBE_IN_RANGE(y, 8)
+
+	circuit.MUSTBE_EQ(circuit.IS_LESS(x, y, 8), z0)
+	circuit.MUSTBE_EQ(circuit.IS_LESS(y, 200, 8), z1)
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x", 199)
+	good.Assign(backend.Secret, "y", 200)
+	good.Assign(backend.Public, "z0", 1)
+	good.Assign(backend.Public, "z1", 0)
+
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 199)
+	bad.Assign(backend.Secret, "y", 200)
+	bad.Assign(backend.Public, "z0", 0)
+	bad.Assign(backend.Public, "z1", 0)
+
+	r1cs := circuit.ToR1CS()
+	addEntry("is_less", r1cs, good, bad)
+}
+
+func cmp() {
+
+	circuit := frontend.New()
+
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.SECRET_INPUT("y")
+	z0 := circuit.PUBLIC_INPUT("z0")
+	z1 := circuit.PUBLIC_INPUT("z1")
+	z2 := circuit.PUBLIC_INPUT("z2")
+
+	circuit.MUSTBE_EQ(circuit.CMP(x, y, 16), z0)
+	circuit.MUSTBE_EQ(circuit.CMP(y, x, 16), z1)
+	circuit.MUSTBE_EQ(circuit.CMP(x, x, 16), z2)
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x", 1000)
+	good.Assign(backend.Secret, "y", 60000)
+	good.Assign(backend.Public, "z0", -1)
+	good.Assign(backend.Public, "z1", 1)
+	good.Assign(backend.Public, "z2", 0)
+
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 1000)
+	bad.Assign(backend.Secret, "y", 60000)
+	bad.Assign(backend.Public, "z0", 1)
+	bad.Assign(backend.Public, "z1", 1)
+	bad.Assign(backend.Public, "z2", 0)
+
+	r1cs := circuit.ToR1CS()
+	addEntry("cmp", r1cs, good, bad)
+}
+
+func rangeBits() {
+
+	circuit := frontend.New()
+
+	x := circuit.PUBLIC_INPUT("x")
+
+	circuit.MUSTBE_IN_RANGE(x, 4)
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Public, "x", 15)
+
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Public, "x", 16)
+
+	r1cs := circuit.ToR1CS()
+	addEntry("range_bits", r1cs, good, bad)
+}
+
+func init() {
+
+	fmt.Println("init comparison")
+
+	isZero()
+
+	isEqual()
+
+	isLess()
+
+	cmp()
+
+	rangeBits()
+}
@@ -0,0 +1,56 @@
+package circuits
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+// hintQuoRem identifies the hint computing the quotient and the remainder of an euclidean division
+const hintQuoRem frontend.HintID = 1
+
+func init() {
+	fmt.Println("init hint")
+	if err := frontend.RegisterHint(hintQuoRem, quoRem); err != nil {
+		panic(err)
+	}
+	circuit := frontend.New()
+
+	x := circuit.SECRET_INPUT("x")
+	y := circuit.PUBLIC_INPUT("y")
+	q := circuit.PUBLIC_INPUT("q")
+
+	// the solver computes quo and rem, the circuit checks x == quo*y + rem with 0 <= rem < y
+	res := circuit.HINT(hintQuoRem, 2, x, y)
+	quo, rem := res[0], res[1]
+	circuit.MUSTBE_EQ(circuit.ADD(circuit.MUL(quo, y), rem), x)
+	circuit.MUSTBE_IN_RANGE(y, 16)
+	circuit.MUSTBE_IN_RANGE(rem, 16)
+	circuit.MUSTBE_EQ(circuit.IS_LESS(rem, y, 16), 1)
+	circuit.MUSTBE_EQ(quo, q)
+
+	good := backend.NewAssignment()
+	good.Assign(backend.Secret, "x", 1000)
+	good.Assign(backend.Public, "y", 7)
+	good.Assign(backend.Public, "q", 142)
+
+	bad := backend.NewAssignment()
+	bad.Assign(backend.Secret, "x", 1000)
+	bad.Assign(backend.Public, "y", 7)
+	bad.Assign(backend.Public, "q", 143)
+
+	r1cs := circuit.ToR1CS()
+	addEntry("hint", r1cs, good, bad)
+}
+
+// quoRem sets outputs to the quotient and the remainder of the euclidean division of inputs[0] by inputs[1]
+func quoRem(_ *big.Int, inputs, outputs []*big.Int) error {
+	if inputs[1].Sign() == 0 {
+		return errors.New("division by zero")
+	}
+	outputs[0].QuoRem(inputs[0], inputs[1], outputs[1])
+	return nil
+}